@@ -1,5 +1,7 @@
 package gophercloud
 
+import "time"
+
 /*
 AuthResult is the result from the request that was used to obtain a provider
 client's Keystone token. It is returned from ProviderClient.GetAuthResult().
@@ -50,3 +52,9 @@ type tokens3.User).
 type AuthResult interface {
 	ExtractTokenID() (string, error)
 }
+
+// expiringAuthResult is implemented by the AuthResults which know when their
+// token expires, so that the ProviderClient can refresh it beforehand.
+type expiringAuthResult interface {
+	ExtractExpiresAt() (time.Time, error)
+}
@@ -9,12 +9,13 @@ Provider structs represent the cloud providers that offer and manage a
 collection of services. You will generally want to create one Provider
 client per OpenStack cloud.
 
-	It is now recommended to use the `clientconfig` package found at
-	https://github.com/gophercloud/utils/tree/master/openstack/clientconfig
-	for all authentication purposes.
+The simplest way to create a Provider client is to load the configuration of
+a cloud from a clouds.yaml file, with its secure.yaml and clouds-public.yaml
+companions:
 
-	The below documentation is still relevant. clientconfig simply implements
-	the below and presents it in an easier and more flexible way.
+	config, err := openstack.ClientConfigFromCloudsYAML("mycloud")
+	provider, err := config.AuthenticatedClient()
+	client, err := openstack.NewComputeV2(provider, config.EndpointOpts)
 
 Use your OpenStack credentials to create a Provider client.  The
 IdentityEndpoint is typically refered to as "auth_url" or "OS_AUTH_URL" in
@@ -49,6 +50,27 @@ instead of "project".
 	opts, err := openstack.AuthOptionsFromEnv()
 	provider, err := openstack.AuthenticatedClient(opts)
 
+Similarly, openstack.AuthOptionsFromCloudsYAML() returns the AuthOptions of a
+cloud of clouds.yaml, or of the cloud named by OS_CLOUD if the name is empty:
+
+	opts, err := openstack.AuthOptionsFromCloudsYAML("")
+	provider, err := openstack.AuthenticatedClient(opts)
+
+Short-lived processes, such as CLI tools, can share their Keystone v3 tokens
+through a TokenCache instead of authenticating on every run:
+
+	provider, err := openstack.NewClient(opts.IdentityEndpoint)
+	provider.TokenCache, err = gophercloud.NewFileTokenCache("")
+	err = openstack.Authenticate(provider, opts)
+
+When AllowReauth is set, a new token is requested after a 401 response. A
+request whose RawBody is not an io.Seeker can't be sent again, though, so a
+token about to expire can be refreshed before the requests instead, or in the
+background:
+
+	provider.TokenRefreshMargin = 5 * time.Minute
+	provider.RefreshTokenInBackground(ctx)
+
 Service Clients
 
 Service structs are specific to a provider and handle all of the logic and
@@ -103,6 +125,30 @@ intermediary processing on each page, you can use the AllPages method:
 	allPages, err := servers.List(client, nil).AllPages()
 	allServers, err := servers.ExtractServers(allPages)
 
+To iterate over the items of a large collection without holding all of them in
+memory, use an Iterator. It can fetch the next page while the current one is
+being consumed, and stop after a given number of items:
+
+	it := servers.List(client, nil).Iterator(func(page pagination.Page) (interface{}, error) {
+		return servers.ExtractServers(page)
+	}, &pagination.IteratorOpts{Prefetch: true, Limit: 100})
+	defer it.Close()
+
+	for it.Next() {
+		server := it.Item().(servers.Server)
+	}
+	err := it.Err()
+
+To bound a single request or listing with its own deadline or cancellation,
+bind a context to the service client with WithContext. The context is used for
+the request, any retries, and a reauthentication triggered by that request:
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server, err := servers.Get(client.WithContext(ctx), "server_id").Extract()
+	allPages, err := servers.List(client.WithContext(ctx), nil).AllPages()
+
 This top-level package contains utility functions and data types that are used
 throughout the provider and service packages. Of particular note for end users
 are the AuthOptions and EndpointOpts structs.
@@ -145,5 +191,37 @@ An example retry backoff function, which respects the 429 HTTP response code and
 		return nil
 	}
 
+Transient failures, such as a 503 response or a refused connection, can be
+retried with exponential backoff by setting a RetryPolicy. Only idempotent
+requests are retried:
+
+	provider.RetryPolicy = &gophercloud.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}
+
+The requests sent by a ProviderClient can be traced by setting a Logger. Tokens,
+passwords, signatures and other secrets are redacted from the logged URLs,
+headers and bodies:
+
+	provider.Logger = gophercloud.LoggerFunc(func(l gophercloud.RequestLog) {
+		log.Print(l)
+	})
+	provider.LogBodies = true
+
+Metrics and tracing spans can be recorded with Instrumentation callbacks, which
+receive the service type, the microversion and a templated URL suitable as a
+metric label. Retries and reauthentication are reported as separate events:
+
+	provider.Instrumentation = &gophercloud.Instrumentation{
+		AfterRequest: func(ctx context.Context, info gophercloud.RequestInfo, stats gophercloud.RequestStats) {
+			requestDuration.WithLabelValues(info.ServiceType, info.Method, info.URLTemplate).Observe(stats.Duration.Seconds())
+		},
+		OnReauth: func(ctx context.Context, info gophercloud.RequestInfo, d time.Duration, err error) {
+			reauthCount.Inc()
+		},
+	}
+
 */
 package gophercloud
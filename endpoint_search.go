@@ -51,6 +51,31 @@ type EndpointOpts struct {
 	// Availability is not required, and defaults to AvailabilityPublic. Not all
 	// providers or services offer all Availability options.
 	Availability Availability
+
+	// AvailabilityFallback [optional] lists the availabilities to try, in
+	// order, when no endpoint matches Availability. For example, an
+	// Availability of AvailabilityInternal with a fallback of
+	// AvailabilityPublic prefers the internal endpoint of a service, and uses
+	// its public endpoint when it has no internal one.
+	AvailabilityFallback []Availability
+
+	// RequireUnique [optional] makes the endpoint search fail with
+	// ErrMultipleMatchingEndpoints when the criteria match several endpoints
+	// with different URLs. By default, the first matching endpoint is used.
+	RequireUnique bool
+}
+
+// Availabilities returns Availability followed by AvailabilityFallback, in
+// the order they are tried.
+func (eo *EndpointOpts) Availabilities() []Availability {
+	availabilities := make([]Availability, 0, 1+len(eo.AvailabilityFallback))
+	availabilities = append(availabilities, eo.Availability)
+	for _, availability := range eo.AvailabilityFallback {
+		if availability != eo.Availability {
+			availabilities = append(availabilities, availability)
+		}
+	}
+	return availabilities
 }
 
 /*
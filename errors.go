@@ -1,6 +1,8 @@
 package gophercloud
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -76,6 +78,9 @@ func (e ErrMissingAnyoneOfEnvironmentVariables) Error() string {
 
 // ErrUnexpectedResponseCode is returned by the Request method when a response code other than
 // those listed in OkCodes is encountered.
+//
+// When the response body is one of the fault documents returned by the OpenStack services, it
+// is decoded into FaultType, FaultMessage and FaultDetail.
 type ErrUnexpectedResponseCode struct {
 	BaseError
 	URL            string
@@ -84,21 +89,218 @@ type ErrUnexpectedResponseCode struct {
 	Actual         int
 	Body           []byte
 	ResponseHeader http.Header
+
+	// FaultType is the type of the fault reported by the service, e.g. "itemNotFound" for Nova,
+	// "NetworkNotFound" for Neutron or "Not Found" for Keystone. It may be empty.
+	FaultType string
+	// FaultMessage is the human readable message of the fault reported by the service.
+	FaultMessage string
+	// FaultDetail holds additional details about the fault, when the service provides them.
+	FaultDetail string
+	// RequestID is the ID the service assigned to the request, taken from the
+	// X-Openstack-Request-Id or X-Compute-Request-Id response headers.
+	RequestID string
 }
 
 func (e ErrUnexpectedResponseCode) Error() string {
 	e.DefaultErrString = fmt.Sprintf(
 		"Expected HTTP response code %v when accessing [%s %s], but got %d instead\n%s",
-		e.Expected, e.Method, e.URL, e.Actual, e.Body,
+		e.Expected, e.Method, e.URL, e.Actual, e.details(),
 	)
 	return e.choseErrString()
 }
 
+// details returns the decoded fault if any, and the raw body otherwise, followed by the
+// request ID.
+func (e ErrUnexpectedResponseCode) details() string {
+	s := string(e.Body)
+	if e.FaultMessage != "" {
+		s = e.FaultMessage
+		if e.FaultType != "" {
+			s = e.FaultType + ": " + s
+		}
+		if e.FaultDetail != "" {
+			s += ": " + e.FaultDetail
+		}
+	}
+	if e.RequestID != "" {
+		s += fmt.Sprintf(" (request ID: %s)", e.RequestID)
+	}
+	return s
+}
+
+// withFault appends the decoded fault and the request ID to a generic error message.
+func (e ErrUnexpectedResponseCode) withFault(msg string) string {
+	if e.FaultMessage == "" && e.RequestID == "" {
+		return msg
+	}
+	return msg + ": " + strings.TrimSpace(e.details())
+}
+
+// parseFault fills the FaultType, FaultMessage, FaultDetail and RequestID fields from the
+// response body and headers.
+func (e *ErrUnexpectedResponseCode) parseFault() {
+	e.RequestID = requestID(e.ResponseHeader)
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(e.Body, &body); err != nil {
+		return
+	}
+
+	type fault struct {
+		Type        string          `json:"type"`
+		Title       string          `json:"title"`
+		Message     string          `json:"message"`
+		Detail      json.RawMessage `json:"detail"`
+		Details     json.RawMessage `json:"details"`
+		Description string          `json:"description"`
+		FaultString string          `json:"faultstring"`
+		DebugInfo   json.RawMessage `json:"debuginfo"`
+	}
+
+	// Neutron: {"NeutronError": {"type": ..., "message": ..., "detail": ...}}
+	// Keystone, Heat: {"error": {"title": ..., "message": ...}}
+	for _, key := range []string{"NeutronError", "error"} {
+		if raw, ok := body[key]; ok {
+			var f fault
+			if json.Unmarshal(raw, &f) == nil && f.Message != "" {
+				e.FaultType = f.Type
+				if e.FaultType == "" {
+					e.FaultType = f.Title
+				}
+				e.FaultMessage = f.Message
+				e.FaultDetail = rawString(f.Detail)
+				return
+			}
+		}
+	}
+
+	// Octavia and other WSME based services: {"faultcode": ..., "faultstring": ..., "debuginfo": ...}
+	// Ironic wraps the same document in a JSON string: {"error_message": "{\"faultstring\": ...}"}
+	var f fault
+	raw := e.Body
+	if em, ok := body["error_message"]; ok {
+		var s string
+		if json.Unmarshal(em, &s) == nil {
+			raw = []byte(s)
+		}
+	}
+	if json.Unmarshal(raw, &f) == nil && f.FaultString != "" {
+		e.FaultMessage = f.FaultString
+		e.FaultDetail = rawString(f.DebugInfo)
+		return
+	}
+
+	// Designate: {"type": ..., "message": ..., "code": ...}
+	// Barbican: {"title": ..., "description": ..., "code": ...}
+	f = fault{}
+	if json.Unmarshal(e.Body, &f) == nil {
+		if f.Message != "" {
+			e.FaultType = f.Type
+			e.FaultMessage = f.Message
+			return
+		}
+		if f.Description != "" {
+			e.FaultType = f.Title
+			e.FaultMessage = f.Description
+			return
+		}
+	}
+
+	// Nova, Cinder, Manila: {"itemNotFound": {"message": ..., "code": ...}}
+	if len(body) == 1 {
+		for k, raw := range body {
+			f := fault{}
+			if json.Unmarshal(raw, &f) == nil && f.Message != "" {
+				e.FaultType = k
+				e.FaultMessage = f.Message
+				e.FaultDetail = rawString(f.Details)
+			}
+		}
+	}
+}
+
+// rawString returns the value of a raw JSON string, or the raw JSON document
+// if it isn't a string. null is returned as an empty string.
+func rawString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
 // GetStatusCode returns the actual status code of the error.
 func (e ErrUnexpectedResponseCode) GetStatusCode() int {
 	return e.Actual
 }
 
+// Sentinel errors matching the HTTP status code of an ErrUnexpectedResponseCode,
+// or of any error embedding it such as the ErrDefault* types, with errors.Is:
+//
+//	if errors.Is(err, gophercloud.ErrNotFound) {
+//		// the resource is already gone
+//	}
+//
+// Note that ErrNotFound is unrelated to ErrResourceNotFound, which is returned
+// when looking up a resource ID by name.
+var (
+	ErrBadRequest          = errors.New("bad request")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrNotFound            = errors.New("not found")
+	ErrMethodNotAllowed    = errors.New("method not allowed")
+	ErrRequestTimeout      = errors.New("request timeout")
+	ErrConflict            = errors.New("conflict")
+	ErrTooManyRequests     = errors.New("too many requests")
+	ErrInternalServerError = errors.New("internal server error")
+	ErrServiceUnavailable  = errors.New("service unavailable")
+)
+
+var statusCodeSentinels = []struct {
+	err  error
+	code int
+}{
+	{ErrBadRequest, http.StatusBadRequest},
+	{ErrUnauthorized, http.StatusUnauthorized},
+	{ErrForbidden, http.StatusForbidden},
+	{ErrNotFound, http.StatusNotFound},
+	{ErrMethodNotAllowed, http.StatusMethodNotAllowed},
+	{ErrRequestTimeout, http.StatusRequestTimeout},
+	{ErrConflict, http.StatusConflict},
+	{ErrTooManyRequests, http.StatusTooManyRequests},
+	{ErrInternalServerError, http.StatusInternalServerError},
+	{ErrServiceUnavailable, http.StatusServiceUnavailable},
+}
+
+// Is reports whether target is the sentinel error matching the actual status
+// code, e.g. ErrNotFound for a 404. It is used by errors.Is.
+func (e ErrUnexpectedResponseCode) Is(target error) bool {
+	for _, s := range statusCodeSentinels {
+		if target == s.err {
+			return e.Actual == s.code
+		}
+	}
+	return false
+}
+
+// ResponseCodeIs returns true if err, or any error it wraps, carries the given
+// HTTP status code:
+//
+//	if gophercloud.ResponseCodeIs(err, http.StatusNotFound) {
+//		// the resource is already gone
+//	}
+func ResponseCodeIs(err error, status int) bool {
+	var codeError StatusCodeError
+	if errors.As(err, &codeError) {
+		return codeError.GetStatusCode() == status
+	}
+	return false
+}
+
 // StatusCodeError is a convenience interface to easily allow access to the
 // status code field of the various ErrDefault* types.
 //
@@ -161,46 +363,96 @@ type ErrDefault503 struct {
 	ErrUnexpectedResponseCode
 }
 
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault400) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault401) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault403) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault404) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault405) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault408) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault409) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault429) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault500) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode.
+func (e ErrDefault503) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
 func (e ErrDefault400) Error() string {
 	e.DefaultErrString = fmt.Sprintf(
 		"Bad request with: [%s %s], error message: %s",
-		e.Method, e.URL, e.Body,
+		e.Method, e.URL, e.details(),
 	)
 	return e.choseErrString()
 }
 func (e ErrDefault401) Error() string {
-	return "Authentication failed"
+	return e.withFault("Authentication failed")
 }
 func (e ErrDefault403) Error() string {
 	e.DefaultErrString = fmt.Sprintf(
 		"Request forbidden: [%s %s], error message: %s",
-		e.Method, e.URL, e.Body,
+		e.Method, e.URL, e.details(),
 	)
 	return e.choseErrString()
 }
 func (e ErrDefault404) Error() string {
 	e.DefaultErrString = fmt.Sprintf(
 		"Resource not found: [%s %s], error message: %s",
-		e.Method, e.URL, e.Body,
+		e.Method, e.URL, e.details(),
 	)
 	return e.choseErrString()
 }
 func (e ErrDefault405) Error() string {
-	return "Method not allowed"
+	return e.withFault("Method not allowed")
 }
 func (e ErrDefault408) Error() string {
-	return "The server timed out waiting for the request"
+	return e.withFault("The server timed out waiting for the request")
 }
 func (e ErrDefault429) Error() string {
-	return "Too many requests have been sent in a given amount of time. Pause" +
-		" requests, wait up to one minute, and try again."
+	return e.withFault("Too many requests have been sent in a given amount of time. Pause" +
+		" requests, wait up to one minute, and try again.")
 }
 func (e ErrDefault500) Error() string {
-	return "Internal Server Error"
+	return e.withFault("Internal Server Error")
 }
 func (e ErrDefault503) Error() string {
-	return "The service is currently unable to handle the request due to a temporary" +
-		" overloading or maintenance. This is a temporary condition. Try again later."
+	return e.withFault("The service is currently unable to handle the request due to a temporary" +
+		" overloading or maintenance. This is a temporary condition. Try again later.")
 }
 
 // Err400er is the interface resource error types implement to override the error message
@@ -273,6 +525,20 @@ func (e ErrTimeOut) Error() string {
 	return e.choseErrString()
 }
 
+// ErrFailureState is the error type returned by Waiter.Wait when the resource
+// reaches one of the failure states, e.g. "ERROR".
+type ErrFailureState struct {
+	BaseError
+	State    string
+	Expected []string
+}
+
+func (e ErrFailureState) Error() string {
+	e.DefaultErrString = fmt.Sprintf("Resource reached state [%s] while waiting for one of [%s]",
+		e.State, strings.Join(e.Expected, ", "))
+	return e.choseErrString()
+}
+
 // ErrUnableToReauthenticate is the error type returned when reauthentication fails.
 type ErrUnableToReauthenticate struct {
 	BaseError
@@ -285,6 +551,24 @@ func (e ErrUnableToReauthenticate) Error() string {
 	return e.choseErrString()
 }
 
+// Unwrap returns the error of the request which triggered the
+// reauthentication. The reauthentication error is matched by Is and As.
+func (e ErrUnableToReauthenticate) Unwrap() error {
+	return e.ErrOriginal
+}
+
+// Is reports whether the error of the request or the reauthentication error
+// matches target, for errors.Is.
+func (e ErrUnableToReauthenticate) Is(target error) bool {
+	return errors.Is(e.ErrOriginal, target) || errors.Is(e.ErrReauth, target)
+}
+
+// As finds the first error matching target in the error of the request, then
+// in the reauthentication error, for errors.As.
+func (e ErrUnableToReauthenticate) As(target interface{}) bool {
+	return errors.As(e.ErrOriginal, target) || errors.As(e.ErrReauth, target)
+}
+
 // ErrErrorAfterReauthentication is the error type returned when reauthentication
 // succeeds, but an error occurs afterword (usually an HTTP error).
 type ErrErrorAfterReauthentication struct {
@@ -297,6 +581,11 @@ func (e ErrErrorAfterReauthentication) Error() string {
 	return e.choseErrString()
 }
 
+// Unwrap returns the error of the request sent after the reauthentication.
+func (e ErrErrorAfterReauthentication) Unwrap() error {
+	return e.ErrOriginal
+}
+
 // ErrServiceNotFound is returned when no service in a service catalog matches
 // the provided EndpointOpts. This is generally returned by provider service
 // factory methods like "NewComputeV2()" and can mean that a service is not
@@ -323,6 +612,20 @@ func (e ErrEndpointNotFound) Error() string {
 	return e.choseErrString()
 }
 
+// ErrMultipleMatchingEndpoints is returned when several endpoints with
+// different URLs match the provided EndpointOpts and EndpointOpts.RequireUnique
+// is set. A Name or a Region usually tells them apart.
+type ErrMultipleMatchingEndpoints struct {
+	BaseError
+	ServiceType string
+	Candidates  []string
+}
+
+func (e ErrMultipleMatchingEndpoints) Error() string {
+	e.DefaultErrString = fmt.Sprintf("Found %d endpoints of service type %s matching the criteria: %s", len(e.Candidates), e.ServiceType, strings.Join(e.Candidates, ", "))
+	return e.choseErrString()
+}
+
 // ErrResourceNotFound is the error when trying to retrieve a resource's
 // ID by name and the resource doesn't exist.
 type ErrResourceNotFound struct {
@@ -0,0 +1,132 @@
+package gophercloud
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RequestInfo identifies a request passed to the Instrumentation callbacks.
+type RequestInfo struct {
+	// ServiceType is the type of the ServiceClient that sent the request,
+	// e.g. "compute". It is empty for requests sent by a bare ProviderClient.
+	ServiceType string
+	// Microversion is the microversion of the ServiceClient, if any.
+	Microversion string
+	Method       string
+	URL          string
+	// URLTemplate is the URL path relative to the service endpoint, without
+	// query string, in which the segments looking like IDs are replaced by
+	// "{id}", e.g. "servers/{id}/action". It is suitable as a low cardinality
+	// metric label.
+	URLTemplate string
+}
+
+// RequestStats summarizes a request passed to Instrumentation.AfterRequest.
+type RequestStats struct {
+	// StatusCode is the status code of the last response, or 0 if none was
+	// received.
+	StatusCode int
+	// Attempts is the number of HTTP requests sent, including retries.
+	Attempts uint
+	// Retries is the number of times the request was sent again because of an
+	// error, not counting reauthentication.
+	Retries uint
+	// Reauths is the number of reauthentications triggered by the request.
+	Reauths uint
+	// Duration is the total time spent in ProviderClient.Request.
+	Duration time.Duration
+	// Err is the error returned by ProviderClient.Request.
+	Err error
+}
+
+// Instrumentation holds callbacks invoked by ProviderClient.Request, which
+// can be used to record metrics or tracing spans. All the callbacks are
+// optional.
+type Instrumentation struct {
+	// BeforeRequest is called once before a request is sent. The returned
+	// context, if not nil, is used for the request and passed to the other
+	// callbacks, which allows a tracing span to be attached to it.
+	BeforeRequest func(ctx context.Context, info RequestInfo) context.Context
+
+	// AfterRequest is called once the request completed, including all
+	// retries and reauthentication.
+	AfterRequest func(ctx context.Context, info RequestInfo, stats RequestStats)
+
+	// OnRetry is called each time the request is about to be sent again
+	// because of err. attempt is the number of the attempt that failed,
+	// starting at 1.
+	OnRetry func(ctx context.Context, info RequestInfo, attempt uint, err error)
+
+	// OnReauth is called after the request triggered a reauthentication,
+	// with its duration and error, if any.
+	OnReauth func(ctx context.Context, info RequestInfo, duration time.Duration, err error)
+}
+
+// requestInfo builds the RequestInfo passed to the Instrumentation callbacks.
+func requestInfo(method, url string, options *RequestOpts) RequestInfo {
+	return RequestInfo{
+		ServiceType:  options.serviceType,
+		Microversion: options.microversion,
+		Method:       method,
+		URL:          url,
+		URLTemplate:  URLTemplate(options.serviceType, options.resourceBase, url),
+	}
+}
+
+var (
+	uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+	hexRegexp  = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+	numRegexp  = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// URLTemplate returns the path of url relative to base, without the query
+// string, in which the segments looking like IDs (UUIDs, long hexadecimal
+// strings and numbers) are replaced by "{id}". For the object-store service,
+// container and object names are replaced by "{container}" and "{object}".
+func URLTemplate(serviceType, base, url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	if base != "" && strings.HasPrefix(url, base) {
+		url = strings.TrimPrefix(url, base)
+	} else if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+		if j := strings.Index(url, "/"); j >= 0 {
+			url = url[j+1:]
+		} else {
+			url = ""
+		}
+	}
+	url = strings.Trim(url, "/")
+	if url == "" {
+		return ""
+	}
+
+	segments := strings.Split(url, "/")
+	if serviceType == "object-store" {
+		switch len(segments) {
+		case 1:
+			return "{container}"
+		default:
+			return "{container}/{object}"
+		}
+	}
+	for i, s := range segments {
+		if uuidRegexp.MatchString(s) || hexRegexp.MatchString(s) || numRegexp.MatchString(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// statusCodeOf returns the status code carried by err, if any.
+func statusCodeOf(err error) int {
+	var codeError StatusCodeError
+	if errors.As(err, &codeError) {
+		return codeError.GetStatusCode()
+	}
+	return 0
+}
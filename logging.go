@@ -0,0 +1,282 @@
+package gophercloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RedactedValue replaces the secret values in logs.
+const RedactedValue = "***"
+
+// RequestLog describes a single HTTP request sent by a ProviderClient, and its
+// response. The URL, headers and bodies are redacted before being passed to a
+// Logger.
+type RequestLog struct {
+	Method        string
+	URL           string
+	RequestHeader http.Header
+	// RequestBody is only set when ProviderClient.LogBodies is true and the
+	// request has a JSON body.
+	RequestBody []byte
+
+	// StatusCode is 0 if no response was received.
+	StatusCode     int
+	ResponseHeader http.Header
+	// ResponseBody is only set when ProviderClient.LogBodies is true, the
+	// response has a JSON body and RequestOpts.KeepResponseBody is false.
+	ResponseBody []byte
+
+	// RequestID is the value of the X-Openstack-Request-Id or
+	// X-Compute-Request-Id response header.
+	RequestID string
+	// Duration is the time elapsed until the response headers were received.
+	Duration time.Duration
+	// Err is the error returned by the HTTP client, if any. Its message is
+	// redacted like URL.
+	Err error
+}
+
+// String formats the log entry as a single line, followed by the bodies, if
+// any.
+func (l RequestLog) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", l.Method, l.URL)
+	if l.Err != nil {
+		fmt.Fprintf(&b, " failed after %s: %s", l.Duration, l.Err)
+	} else {
+		fmt.Fprintf(&b, " %d in %s", l.StatusCode, l.Duration)
+	}
+	if l.RequestID != "" {
+		fmt.Fprintf(&b, " (request ID: %s)", l.RequestID)
+	}
+	if len(l.RequestBody) > 0 {
+		fmt.Fprintf(&b, "\nRequest body: %s", l.RequestBody)
+	}
+	if len(l.ResponseBody) > 0 {
+		fmt.Fprintf(&b, "\nResponse body: %s", l.ResponseBody)
+	}
+	return b.String()
+}
+
+// Logger receives a RequestLog for every HTTP request sent by a
+// ProviderClient, including retries and reauthentication requests.
+type Logger interface {
+	LogRequest(RequestLog)
+}
+
+// LoggerFunc is an adapter to allow the use of ordinary functions as Logger.
+type LoggerFunc func(RequestLog)
+
+// LogRequest calls f(l).
+func (f LoggerFunc) LogRequest(l RequestLog) {
+	f(l)
+}
+
+// redactedHeaders are the headers whose values are replaced by RedactHeaders.
+var redactedHeaders = []string{
+	"X-Auth-Token",
+	"X-Subject-Token",
+	"X-Service-Token",
+	"X-Auth-Key",
+	"X-Storage-Token",
+	"Authorization",
+	"Openstack-Auth-Receipt",
+	"X-Account-Meta-Temp-Url-Key",
+	"X-Account-Meta-Temp-Url-Key-2",
+	"X-Container-Meta-Temp-Url-Key",
+	"X-Container-Meta-Temp-Url-Key-2",
+}
+
+// RedactHeaders returns a copy of h in which the values of the headers
+// carrying tokens, keys or other credentials are replaced by RedactedValue.
+func RedactHeaders(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	r := make(http.Header, len(h))
+	for k, v := range h {
+		r[k] = append([]string(nil), v...)
+	}
+	for _, k := range redactedHeaders {
+		if _, ok := r[http.CanonicalHeaderKey(k)]; ok {
+			r.Set(k, RedactedValue)
+		}
+	}
+	return r
+}
+
+// redactedKeys are the JSON keys whose values are replaced by RedactJSON. The
+// comparison is case insensitive.
+var redactedKeys = map[string]bool{
+	"password":          true,
+	"original_password": true,
+	"adminpass":         true,
+	"admin_pass":        true,
+	"passcode":          true,
+	"secret":            true,
+	"payload":           true,
+	"blob":              true,
+	"signature":         true,
+	"access_token":      true,
+	"refresh_token":     true,
+	"client_secret":     true,
+	"id_token":          true,
+}
+
+// RedactJSON returns a copy of the JSON document b in which the values of
+// passwords, secrets, application credential secrets, TOTP passcodes,
+// Barbican secret payloads and tokens are replaced by RedactedValue. If b is
+// not a valid JSON document, a placeholder is returned instead, since it
+// cannot be redacted safely.
+func RedactJSON(b []byte) []byte {
+	if len(b) == 0 {
+		return b
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return []byte(fmt.Sprintf("[%d bytes of non-JSON content omitted]", len(b)))
+	}
+	r, err := json.Marshal(redactValue("", v))
+	if err != nil {
+		return []byte(fmt.Sprintf("[%d bytes of content omitted]", len(b)))
+	}
+	return r
+}
+
+// redactedQueryKeys are the query parameters, in addition to redactedKeys,
+// whose values are replaced by RedactQuery. The comparison is case insensitive.
+var redactedQueryKeys = map[string]bool{
+	"temp_url_sig":    true,
+	"x-amz-signature": true,
+}
+
+// RedactQuery returns a copy of the URL-encoded query or form q in which the
+// values of the parameters carrying passwords, secrets, tokens or signatures,
+// like temp_url_sig, are replaced by RedactedValue. The order of the
+// parameters is kept.
+func RedactQuery(q string) string {
+	if q == "" {
+		return q
+	}
+	params := strings.Split(q, "&")
+	for i, param := range params {
+		kv := strings.SplitN(param, "=", 2)
+		k, err := url.QueryUnescape(kv[0])
+		if err != nil {
+			k = kv[0]
+		}
+		k = strings.ToLower(k)
+		if len(kv) == 2 && (redactedKeys[k] || redactedQueryKeys[k]) {
+			params[i] = kv[0] + "=" + RedactedValue
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// RedactURL returns u with the values of its sensitive query parameters
+// replaced by RedactedValue, see RedactQuery.
+func RedactURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.RawQuery == "" {
+		return u
+	}
+	parsed.RawQuery = RedactQuery(parsed.RawQuery)
+	return parsed.String()
+}
+
+// queryParam matches the query parameters of the URLs embedded in a text,
+// e.g. in the message of an error.
+var queryParam = regexp.MustCompile(`[?&][^=&?#\s"']+=[^&#\s"']*`)
+
+// redactedError is an error whose message is redacted. It unwraps to the
+// original error.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e redactedError) Error() string {
+	return e.msg
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError returns err with the values of the sensitive query parameters
+// of the URLs in its message replaced by RedactedValue, see RedactQuery.
+func redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := queryParam.ReplaceAllStringFunc(err.Error(), func(param string) string {
+		return param[:1] + RedactQuery(param[1:])
+	})
+	if msg == err.Error() {
+		return err
+	}
+	return redactedError{msg: msg, err: err}
+}
+
+func redactValue(parent string, v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			lk := strings.ToLower(k)
+			switch {
+			case redactedKeys[lk]:
+				t[k] = RedactedValue
+			case parent == "token" && lk == "id":
+				// {"token": {"id": "..."}} in Keystone v2 and token auth methods
+				t[k] = RedactedValue
+			default:
+				t[k] = redactValue(lk, e)
+			}
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = redactValue(parent, e)
+		}
+		return t
+	}
+	return v
+}
+
+// logRequest sends a redacted RequestLog to the client's Logger.
+func (client *ProviderClient) logRequest(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, start time.Time, err error) {
+	l := RequestLog{
+		Method:        req.Method,
+		URL:           RedactURL(req.URL.String()),
+		RequestHeader: RedactHeaders(req.Header),
+		Duration:      time.Since(start),
+		Err:           redactError(err),
+	}
+	if client.LogBodies && len(reqBody) > 0 {
+		l.RequestBody = RedactJSON(reqBody)
+	}
+	if resp != nil {
+		l.StatusCode = resp.StatusCode
+		l.ResponseHeader = RedactHeaders(resp.Header)
+		l.RequestID = requestID(resp.Header)
+		if client.LogBodies && len(respBody) > 0 {
+			l.ResponseBody = RedactJSON(respBody)
+		}
+	}
+	client.Logger.LogRequest(l)
+}
+
+// requestID returns the ID the service assigned to a request.
+func requestID(h http.Header) string {
+	for _, k := range []string{"X-Openstack-Request-Id", "X-Compute-Request-Id"} {
+		if v := h.Get(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -1,22 +1,31 @@
 package attachments
 
 import (
+	"context"
+	"time"
+
 	"github.com/gophercloud/gophercloud"
 )
 
 // WaitForStatus will continually poll the resource, checking for a particular
-// status. It will do this for the amount of seconds defined.
+// status. It will do this for the amount of seconds defined. See
+// gophercloud.WaitForStatus for the error statuses.
 func WaitForStatus(c *gophercloud.ServiceClient, id, status string, secs int) error {
-	return gophercloud.WaitFor(secs, func() (bool, error) {
-		current, err := Get(c, id).Extract()
-		if err != nil {
-			return false, err
-		}
+	return gophercloud.WaitForStatus(context.Background(), status, time.Duration(secs)*time.Second, refreshStatus(c, id))
+}
 
-		if current.Status == status {
-			return true, nil
-		}
+// WaitForStatusContext is like WaitForStatus, but polls the resource until
+// ctx is done instead of for a number of seconds.
+func WaitForStatusContext(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, status, -1, refreshStatus(c, id))
+}
 
-		return false, nil
-	})
+func refreshStatus(c *gophercloud.ServiceClient, id string) gophercloud.StateRefreshFunc {
+	return func(ctx context.Context) (string, error) {
+		current, err := Get(c.WithContext(ctx), id).Extract()
+		if err != nil {
+			return "", err
+		}
+		return current.Status, nil
+	}
 }
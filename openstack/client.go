@@ -1,6 +1,7 @@
 package openstack
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -8,7 +9,9 @@ import (
 	"github.com/gophercloud/gophercloud"
 	tokens2 "github.com/gophercloud/gophercloud/openstack/identity/v2/tokens"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/ec2tokens"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/k2k"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/oauth1"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/oidc"
 	tokens3 "github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
 	"github.com/gophercloud/gophercloud/openstack/utils"
 )
@@ -155,6 +158,7 @@ func v2auth(client *gophercloud.ProviderClient, endpoint string, options gopherc
 		tac := *client
 		tac.SetThrowaway(true)
 		tac.ReauthFunc = nil
+		tac.ReauthContextFunc = nil
 		tac.SetTokenAndAuthResult(nil)
 		tao := options
 		tao.AllowReauth = false
@@ -166,6 +170,18 @@ func v2auth(client *gophercloud.ProviderClient, endpoint string, options gopherc
 			client.CopyTokenFrom(&tac)
 			return nil
 		}
+		client.ReauthContextFunc = func(ctx context.Context) error {
+			// copy the throw-away client, so that the context of one request
+			// doesn't leak into reauthentications triggered by other requests
+			c := tac
+			c.Context = ctx
+			err := v2auth(&c, endpoint, tao, eo)
+			if err != nil {
+				return err
+			}
+			client.CopyTokenFrom(&c)
+			return nil
+		}
 	}
 	client.EndpointLocator = func(opts gophercloud.EndpointOpts) (string, error) {
 		return V2EndpointURL(catalog, opts)
@@ -227,13 +243,17 @@ func v3auth(client *gophercloud.ProviderClient, endpoint string, opts tokens3.Au
 		}
 	} else {
 		var result tokens3.CreateResult
-		switch opts.(type) {
+		switch o := opts.(type) {
 		case *ec2tokens.AuthOptions:
 			result = ec2tokens.Create(v3Client, opts)
 		case *oauth1.AuthOptions:
 			result = oauth1.Create(v3Client, opts)
+		case oidc.AuthOptionsBuilder:
+			result = oidc.Create(v3Client, o)
+		case *k2k.AuthOptions:
+			result = k2k.Create(v3Client, o)
 		default:
-			result = tokens3.Create(v3Client, opts)
+			result = createV3Token(client, v3Client, tokenCacheKey(v3Client.Endpoint, opts), opts)
 		}
 
 		err = client.SetTokenAndAuthResult(result)
@@ -254,6 +274,7 @@ func v3auth(client *gophercloud.ProviderClient, endpoint string, opts tokens3.Au
 		tac := *client
 		tac.SetThrowaway(true)
 		tac.ReauthFunc = nil
+		tac.ReauthContextFunc = nil
 		tac.SetTokenAndAuthResult(nil)
 		var tao tokens3.AuthOptionsBuilder
 		switch ot := opts.(type) {
@@ -273,10 +294,28 @@ func v3auth(client *gophercloud.ProviderClient, endpoint string, opts tokens3.Au
 			o := *ot
 			o.AllowReauth = false
 			tao = &o
+		case *oidc.PasswordAuthOptions:
+			o := *ot
+			o.AllowReauth = false
+			tao = &o
+		case *oidc.ClientCredentialsAuthOptions:
+			o := *ot
+			o.AllowReauth = false
+			tao = &o
+		case *oidc.AccessTokenAuthOptions:
+			o := *ot
+			o.AllowReauth = false
+			tao = &o
+		case *k2k.AuthOptions:
+			o := *ot
+			o.AllowReauth = false
+			tao = &o
 		default:
 			tao = opts
 		}
+		cacheKey := tokenCacheKey(v3Client.Endpoint, tao)
 		client.ReauthFunc = func() error {
+			evictCachedToken(client, cacheKey)
 			err := v3auth(&tac, endpoint, tao, eo)
 			if err != nil {
 				return err
@@ -284,6 +323,19 @@ func v3auth(client *gophercloud.ProviderClient, endpoint string, opts tokens3.Au
 			client.CopyTokenFrom(&tac)
 			return nil
 		}
+		client.ReauthContextFunc = func(ctx context.Context) error {
+			// copy the throw-away client, so that the context of one request
+			// doesn't leak into reauthentications triggered by other requests
+			c := tac
+			c.Context = ctx
+			evictCachedToken(client, cacheKey)
+			err := v3auth(&c, endpoint, tao, eo)
+			if err != nil {
+				return err
+			}
+			client.CopyTokenFrom(&c)
+			return nil
+		}
 	}
 	client.EndpointLocator = func(opts gophercloud.EndpointOpts) (string, error) {
 		return V3EndpointURL(catalog, opts)
@@ -298,7 +350,9 @@ func NewIdentityV2(client *gophercloud.ProviderClient, eo gophercloud.EndpointOp
 	endpoint := client.IdentityBase + "v2.0/"
 	clientType := "identity"
 	var err error
-	if !reflect.DeepEqual(eo, gophercloud.EndpointOpts{}) {
+	if url, ok := endpointOverride(client, clientType); ok {
+		endpoint = url
+	} else if !reflect.DeepEqual(eo, gophercloud.EndpointOpts{}) {
 		eo.ApplyDefaults(clientType)
 		endpoint, err = client.EndpointLocator(eo)
 		if err != nil {
@@ -319,7 +373,9 @@ func NewIdentityV3(client *gophercloud.ProviderClient, eo gophercloud.EndpointOp
 	endpoint := client.IdentityBase + "v3/"
 	clientType := "identity"
 	var err error
-	if !reflect.DeepEqual(eo, gophercloud.EndpointOpts{}) {
+	if url, ok := endpointOverride(client, clientType); ok {
+		endpoint = url
+	} else if !reflect.DeepEqual(eo, gophercloud.EndpointOpts{}) {
 		eo.ApplyDefaults(clientType)
 		endpoint, err = client.EndpointLocator(eo)
 		if err != nil {
@@ -350,9 +406,13 @@ func NewIdentityV3(client *gophercloud.ProviderClient, eo gophercloud.EndpointOp
 func initClientOpts(client *gophercloud.ProviderClient, eo gophercloud.EndpointOpts, clientType string) (*gophercloud.ServiceClient, error) {
 	sc := new(gophercloud.ServiceClient)
 	eo.ApplyDefaults(clientType)
-	url, err := client.EndpointLocator(eo)
-	if err != nil {
-		return sc, err
+	url, ok := endpointOverride(client, eo.Type)
+	if !ok {
+		var err error
+		url, err = client.EndpointLocator(eo)
+		if err != nil {
+			return sc, err
+		}
 	}
 	sc.ProviderClient = client
 	sc.Endpoint = url
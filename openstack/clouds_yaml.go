@@ -0,0 +1,494 @@
+package openstack
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// CloudAuth holds the "auth" section of a cloud in clouds.yaml.
+type CloudAuth struct {
+	AuthURL  string `yaml:"auth_url"`
+	Token    string `yaml:"token"`
+	Username string `yaml:"username"`
+	UserID   string `yaml:"user_id"`
+	Password string `yaml:"password"`
+	Passcode string `yaml:"passcode"`
+
+	ProjectName string `yaml:"project_name"`
+	ProjectID   string `yaml:"project_id"`
+
+	UserDomainName    string `yaml:"user_domain_name"`
+	UserDomainID      string `yaml:"user_domain_id"`
+	ProjectDomainName string `yaml:"project_domain_name"`
+	ProjectDomainID   string `yaml:"project_domain_id"`
+	DomainName        string `yaml:"domain_name"`
+	DomainID          string `yaml:"domain_id"`
+	DefaultDomain     string `yaml:"default_domain"`
+
+	ApplicationCredentialID     string `yaml:"application_credential_id"`
+	ApplicationCredentialName   string `yaml:"application_credential_name"`
+	ApplicationCredentialSecret string `yaml:"application_credential_secret"`
+
+	SystemScope string `yaml:"system_scope"`
+}
+
+// Cloud is an entry of clouds.yaml, secure.yaml or clouds-public.yaml.
+type Cloud struct {
+	// Profile, or Cloud for backwards compatibility, is the name of an entry
+	// of clouds-public.yaml providing defaults for this cloud.
+	Profile string `yaml:"profile"`
+	Cloud   string `yaml:"cloud"`
+
+	Auth     CloudAuth `yaml:"auth"`
+	AuthType string    `yaml:"auth_type"`
+
+	RegionName   string `yaml:"region_name"`
+	Interface    string `yaml:"interface"`
+	EndpointType string `yaml:"endpoint_type"`
+
+	IdentityAPIVersion string `yaml:"identity_api_version"`
+
+	// Verify can be set to false to skip the verification of the server
+	// certificates.
+	Verify *bool `yaml:"verify"`
+	// CACertFile is the path to the CA bundle verifying the server
+	// certificates.
+	CACertFile string `yaml:"cacert"`
+	// ClientCertFile and ClientKeyFile are the paths to the TLS client
+	// certificate and its key.
+	ClientCertFile string `yaml:"cert"`
+	ClientKeyFile  string `yaml:"key"`
+
+	// Extra holds the other settings, such as the
+	// <service type>_endpoint_override keys.
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// ClientConfig is the configuration of a cloud loaded from clouds.yaml.
+type ClientConfig struct {
+	// CloudName is the name of the cloud in clouds.yaml.
+	CloudName string
+
+	// AuthOptions are the options to authenticate against the cloud.
+	AuthOptions gophercloud.AuthOptions
+
+	// EndpointOpts holds the region and interface of the cloud, to pass to the
+	// service client constructors.
+	EndpointOpts gophercloud.EndpointOpts
+
+	// HTTPClient is the client configured with the TLS settings of the cloud.
+	HTTPClient http.Client
+
+	// EndpointOverrides maps service types to the endpoints which must be
+	// used instead of the ones of the service catalog.
+	EndpointOverrides map[string]string
+}
+
+// AuthenticatedClient returns a ProviderClient authenticated against the
+// cloud, which uses the HTTP client and the endpoint overrides of the
+// configuration.
+func (c *ClientConfig) AuthenticatedClient() (*gophercloud.ProviderClient, error) {
+	client, err := NewClient(c.AuthOptions.IdentityEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	client.HTTPClient = c.HTTPClient
+	client.EndpointOverrides = c.EndpointOverrides
+
+	if err := Authenticate(client, c.AuthOptions); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+/*
+AuthOptionsFromCloudsYAML returns the AuthOptions of the cloud named cloudName
+in clouds.yaml, or of the cloud named by the OS_CLOUD environment variable if
+cloudName is empty. See ClientConfigFromCloudsYAML for how the files are
+found.
+
+	opts, err := openstack.AuthOptionsFromCloudsYAML("mycloud")
+	provider, err := openstack.AuthenticatedClient(opts)
+*/
+func AuthOptionsFromCloudsYAML(cloudName string) (gophercloud.AuthOptions, error) {
+	config, err := ClientConfigFromCloudsYAML(cloudName)
+	if err != nil {
+		return nilOptions, err
+	}
+	return config.AuthOptions, nil
+}
+
+/*
+ClientConfigFromCloudsYAML loads the configuration of the cloud named
+cloudName, or of the cloud named by the OS_CLOUD environment variable if
+cloudName is empty.
+
+The clouds.yaml file is the one named by OS_CLIENT_CONFIG_FILE, or the first
+one found in the current directory, ~/.config/openstack and /etc/openstack.
+secure.yaml, usually holding the passwords, is searched for the same way,
+with OS_CLIENT_SECURE_FILE, and its settings override the ones of
+clouds.yaml. The profile of the cloud, if any, is loaded from
+clouds-public.yaml and provides the defaults.
+
+The following auth_type values are supported: password (the default),
+v3password, token, v3token, v3applicationcredential and v3totp.
+
+	config, err := openstack.ClientConfigFromCloudsYAML("mycloud")
+	provider, err := config.AuthenticatedClient()
+	client, err := openstack.NewComputeV2(provider, config.EndpointOpts)
+*/
+func ClientConfigFromCloudsYAML(cloudName string) (*ClientConfig, error) {
+	if cloudName == "" {
+		cloudName = os.Getenv("OS_CLOUD")
+	}
+	if cloudName == "" {
+		return nil, gophercloud.ErrMissingEnvironmentVariable{EnvironmentVariable: "OS_CLOUD"}
+	}
+
+	cloudsFile := findConfigFile("OS_CLIENT_CONFIG_FILE", "clouds")
+	if cloudsFile == "" {
+		return nil, ErrCloudsYAMLNotFound{}
+	}
+	clouds, err := loadCloudsFile(cloudsFile, "clouds")
+	if err != nil {
+		return nil, err
+	}
+	cloud, ok := clouds[cloudName]
+	if !ok {
+		return nil, ErrCloudNotFound{Cloud: cloudName, File: cloudsFile}
+	}
+
+	if secureFile := findConfigFile("OS_CLIENT_SECURE_FILE", "secure"); secureFile != "" {
+		secure, err := loadCloudsFile(secureFile, "clouds")
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := secure[cloudName]; ok {
+			cloud = mergeYAML(cloud, s)
+		}
+	}
+
+	profile := stringValue(cloud["profile"])
+	if profile == "" {
+		profile = stringValue(cloud["cloud"])
+	}
+	if profile != "" {
+		publicFile := findConfigFile("", "clouds-public")
+		if publicFile == "" {
+			return nil, ErrCloudNotFound{Cloud: profile, File: "clouds-public.yaml"}
+		}
+		public, err := loadCloudsFile(publicFile, "public-clouds")
+		if err != nil {
+			return nil, err
+		}
+		p, ok := public[profile]
+		if !ok {
+			return nil, ErrCloudNotFound{Cloud: profile, File: publicFile}
+		}
+		cloud = mergeYAML(p, cloud)
+	}
+
+	b, err := yaml.Marshal(cloud)
+	if err != nil {
+		return nil, err
+	}
+	var c Cloud
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+
+	return newClientConfig(cloudName, &c)
+}
+
+func newClientConfig(cloudName string, cloud *Cloud) (*ClientConfig, error) {
+	config := &ClientConfig{
+		CloudName:         cloudName,
+		EndpointOverrides: make(map[string]string),
+	}
+
+	ao, err := cloud.authOptions()
+	if err != nil {
+		return nil, err
+	}
+	config.AuthOptions = ao
+
+	config.EndpointOpts.Region = cloud.RegionName
+	iface := cloud.Interface
+	if iface == "" {
+		iface = cloud.EndpointType
+	}
+	switch strings.TrimSuffix(strings.ToLower(iface), "url") {
+	case "", "public":
+		config.EndpointOpts.Availability = gophercloud.AvailabilityPublic
+	case "internal":
+		config.EndpointOpts.Availability = gophercloud.AvailabilityInternal
+	case "admin":
+		config.EndpointOpts.Availability = gophercloud.AvailabilityAdmin
+	default:
+		return nil, ErrInvalidAvailabilityProvided{gophercloud.ErrInvalidInput{Value: iface}}
+	}
+
+	for k, v := range cloud.Extra {
+		if strings.HasSuffix(k, "_endpoint_override") {
+			serviceType := strings.Replace(strings.TrimSuffix(k, "_endpoint_override"), "_", "-", -1)
+			config.EndpointOverrides[serviceType] = stringValue(v)
+		}
+	}
+
+	tlsConfig, err := cloud.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		config.HTTPClient.Transport = transport
+	}
+
+	return config, nil
+}
+
+func (cloud *Cloud) authOptions() (gophercloud.AuthOptions, error) {
+	auth := cloud.Auth
+	if auth.AuthURL == "" {
+		return nilOptions, ErrMissingCloudSetting{Setting: "auth.auth_url"}
+	}
+
+	ao := gophercloud.AuthOptions{
+		IdentityEndpoint: auth.AuthURL,
+		AllowReauth:      true,
+	}
+	// an unversioned auth_url is completed with identity_api_version
+	if !strings.Contains(auth.AuthURL, "/v2.0") && !strings.Contains(auth.AuthURL, "/v3") {
+		switch strings.TrimPrefix(cloud.IdentityAPIVersion, "v") {
+		case "2", "2.0":
+			ao.IdentityEndpoint = strings.TrimSuffix(auth.AuthURL, "/") + "/v2.0/"
+		case "3":
+			ao.IdentityEndpoint = strings.TrimSuffix(auth.AuthURL, "/") + "/v3/"
+		}
+	}
+
+	// the user domain defaults to the generic domain, then to the default domain
+	userDomainID := firstNonEmpty(auth.UserDomainID, auth.DomainID)
+	userDomainName := firstNonEmpty(auth.UserDomainName, auth.DomainName)
+	if userDomainID == "" && userDomainName == "" {
+		userDomainID = auth.DefaultDomain
+	}
+
+	switch cloud.AuthType {
+	case "", "password", "v2password", "v3password":
+		if auth.Username == "" && auth.UserID == "" {
+			return nilOptions, ErrMissingCloudSetting{Setting: "auth.username"}
+		}
+		ao.Username = auth.Username
+		ao.UserID = auth.UserID
+		ao.Password = auth.Password
+		ao.DomainID = userDomainID
+		ao.DomainName = userDomainName
+	case "token", "v2token", "v3token":
+		if auth.Token == "" {
+			return nilOptions, ErrMissingCloudSetting{Setting: "auth.token"}
+		}
+		ao.TokenID = auth.Token
+		// a token can't be used to reauthenticate once it expired
+		ao.AllowReauth = false
+	case "v3applicationcredential":
+		if auth.ApplicationCredentialSecret == "" {
+			return nilOptions, ErrMissingCloudSetting{Setting: "auth.application_credential_secret"}
+		}
+		ao.ApplicationCredentialID = auth.ApplicationCredentialID
+		ao.ApplicationCredentialName = auth.ApplicationCredentialName
+		ao.ApplicationCredentialSecret = auth.ApplicationCredentialSecret
+		if ao.ApplicationCredentialID == "" {
+			ao.Username = auth.Username
+			ao.UserID = auth.UserID
+			ao.DomainID = userDomainID
+			ao.DomainName = userDomainName
+		}
+		// application credentials are already scoped
+		return ao, nil
+	case "v3totp":
+		if auth.Passcode == "" {
+			return nilOptions, ErrMissingCloudSetting{Setting: "auth.passcode"}
+		}
+		ao.Username = auth.Username
+		ao.UserID = auth.UserID
+		ao.Passcode = auth.Passcode
+		ao.DomainID = userDomainID
+		ao.DomainName = userDomainName
+		// a passcode can only be used once
+		ao.AllowReauth = false
+	default:
+		return nilOptions, ErrUnsupportedAuthType{AuthType: cloud.AuthType}
+	}
+
+	switch {
+	case auth.SystemScope != "":
+		ao.Scope = &gophercloud.AuthScope{System: true}
+	case auth.ProjectID != "" || auth.ProjectName != "":
+		scope := &gophercloud.AuthScope{
+			ProjectID:   auth.ProjectID,
+			ProjectName: auth.ProjectName,
+		}
+		if auth.ProjectID == "" {
+			scope.DomainID = firstNonEmpty(auth.ProjectDomainID, auth.DomainID)
+			scope.DomainName = firstNonEmpty(auth.ProjectDomainName, auth.DomainName)
+			if scope.DomainID == "" && scope.DomainName == "" {
+				scope.DomainID = auth.DefaultDomain
+			}
+		}
+		ao.Scope = scope
+		// keep the tenant fields for Keystone v2
+		ao.TenantID = auth.ProjectID
+		ao.TenantName = auth.ProjectName
+	case auth.DomainID != "" || auth.DomainName != "":
+		ao.Scope = &gophercloud.AuthScope{
+			DomainID:   auth.DomainID,
+			DomainName: auth.DomainName,
+		}
+	}
+
+	return ao, nil
+}
+
+// tlsConfig returns the TLS configuration of the cloud, or nil if the
+// defaults are used.
+func (cloud *Cloud) tlsConfig() (*tls.Config, error) {
+	if cloud.Verify == nil && cloud.CACertFile == "" && cloud.ClientCertFile == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{}
+	if cloud.Verify != nil && !*cloud.Verify {
+		config.InsecureSkipVerify = true
+	}
+
+	if cloud.CACertFile != "" {
+		pem, err := ioutil.ReadFile(expandHome(cloud.CACertFile))
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", cloud.CACertFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if cloud.ClientCertFile != "" {
+		if cloud.ClientKeyFile == "" {
+			return nil, ErrMissingCloudSetting{Setting: "key"}
+		}
+		cert, err := tls.LoadX509KeyPair(expandHome(cloud.ClientCertFile), expandHome(cloud.ClientKeyFile))
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// findConfigFile returns the path of the file named by the envVar environment
+// variable, or of the first <name>.yaml or <name>.yml file found in the
+// current directory, ~/.config/openstack and /etc/openstack.
+func findConfigFile(envVar, name string) string {
+	if envVar != "" {
+		if v := os.Getenv(envVar); v != "" {
+			return v
+		}
+	}
+
+	dirs := []string{"."}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config", "openstack"))
+	}
+	dirs = append(dirs, "/etc/openstack")
+
+	for _, dir := range dirs {
+		for _, ext := range []string{".yaml", ".yml"} {
+			path := filepath.Join(dir, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// loadCloudsFile returns the clouds found under the key of a YAML file.
+func loadCloudsFile(path, key string) (map[string]map[interface{}]interface{}, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// the other sections, like cache or client, are ignored
+	var content struct {
+		Clouds       map[string]map[interface{}]interface{} `yaml:"clouds"`
+		PublicClouds map[string]map[interface{}]interface{} `yaml:"public-clouds"`
+	}
+	if err := yaml.Unmarshal(b, &content); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", path, err)
+	}
+	if key == "public-clouds" {
+		return content.PublicClouds, nil
+	}
+	return content.Clouds, nil
+}
+
+// mergeYAML returns a copy of base in which the values of override are
+// merged recursively.
+func mergeYAML(base, override map[interface{}]interface{}) map[interface{}]interface{} {
+	merged := make(map[interface{}]interface{}, len(base))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if o, ok := v.(map[interface{}]interface{}); ok {
+			if b, ok := merged[k].(map[interface{}]interface{}); ok {
+				merged[k] = mergeYAML(b, o)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+func stringValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
@@ -1,6 +1,10 @@
 package tags
 
-import "github.com/gophercloud/gophercloud"
+import (
+	"net/http"
+
+	"github.com/gophercloud/gophercloud"
+)
 
 type commonResult struct {
 	gophercloud.Result
@@ -28,7 +32,7 @@ func (r CheckResult) Extract() (bool, error) {
 	exists := r.Err == nil
 
 	if r.Err != nil {
-		if _, ok := r.Err.(gophercloud.ErrDefault404); ok {
+		if gophercloud.ResponseCodeIs(r.Err, http.StatusNotFound) {
 			r.Err = nil
 		}
 	}
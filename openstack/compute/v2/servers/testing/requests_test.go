@@ -1,11 +1,15 @@
 package testing
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/diskconfig"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/extendedstatus"
@@ -592,3 +596,37 @@ func TestCreateServerWithTags(t *testing.T) {
 	th.AssertNoErr(t, err)
 	th.CheckDeepEquals(t, ServerDerpTags, *actualServer)
 }
+
+func TestWaitForStatus(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleServerGetSuccessfully(t)
+
+	err := servers.WaitForStatus(client.ServiceClient(), "1234asdf", "ACTIVE", 5)
+	th.AssertNoErr(t, err)
+
+	err = servers.WaitForStatusContext(context.Background(), client.ServiceClient(), "1234asdf", "ACTIVE")
+	th.AssertNoErr(t, err)
+
+	// no time to poll the server
+	err = servers.WaitForStatus(client.ServiceClient(), "1234asdf", "ACTIVE", 0)
+	_, ok := err.(gophercloud.ErrTimeOut)
+	th.AssertEquals(t, true, ok)
+}
+
+func TestWaitForStatusError(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	th.Mux.HandleFunc("/servers/1234asdf", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		fmt.Fprint(w, strings.Replace(SingleServerBody, `"status": "ACTIVE"`, `"status": "ERROR"`, 1))
+	})
+
+	err := servers.WaitForStatus(client.ServiceClient(), "1234asdf", "SHUTOFF", 60)
+	th.AssertErr(t, err)
+
+	_, ok := err.(gophercloud.ErrFailureState)
+	th.AssertEquals(t, true, ok)
+}
@@ -10,102 +10,146 @@ import (
 V2EndpointURL discovers the endpoint URL for a specific service from a
 ServiceCatalog acquired during the v2 identity service.
 
-The specified EndpointOpts are used to identify a unique, unambiguous endpoint
-to return. It's an error both when multiple endpoints match the provided
-criteria and when none do. The minimum that can be specified is a Type, but you
-will also often need to specify a Name and/or a Region depending on what's
-available on your OpenStack deployment.
+The specified EndpointOpts are used to identify the endpoint to return. The
+minimum that can be specified is a Type, but you will also often need to specify
+a Name and/or a Region depending on what's available on your OpenStack
+deployment. When no catalog entry has the Type, the aliases of the Type are
+tried, and when no endpoint has the Availability, the AvailabilityFallback is
+tried. If multiple endpoints match, the first one is used, unless RequireUnique
+is set.
 */
 func V2EndpointURL(catalog *tokens2.ServiceCatalog, opts gophercloud.EndpointOpts) (string, error) {
-	// Extract Endpoints from the catalog entries that match the requested Type, Name if provided, and Region if provided.
-	var endpoints = make([]tokens2.Endpoint, 0, 1)
-	for _, entry := range catalog.Entries {
-		if (entry.Type == opts.Type) && (opts.Name == "" || entry.Name == opts.Name) {
-			for _, endpoint := range entry.Endpoints {
-				if opts.Region == "" || endpoint.Region == opts.Region {
-					endpoints = append(endpoints, endpoint)
-				}
-			}
-		}
+	availabilities, err := endpointAvailabilities(opts)
+	if err != nil {
+		return "", err
 	}
 
-	// If multiple endpoints were found, use the first result
-	// and disregard the other endpoints.
-	//
-	// This behavior matches the Python library. See GH-1764.
-	if len(endpoints) > 1 {
-		endpoints = endpoints[0:1]
-	}
+	for _, availability := range availabilities {
+		for _, serviceType := range endpointServiceTypes(opts) {
+			// Extract the URLs of the Endpoints from the catalog entries that match
+			// the requested Type, Name if provided, and Region if provided.
+			var urls []string
+			for _, entry := range catalog.Entries {
+				if (entry.Type == serviceType) && (opts.Name == "" || entry.Name == opts.Name) {
+					for _, endpoint := range entry.Endpoints {
+						if opts.Region == "" || endpoint.Region == opts.Region {
+							var url string
+							switch availability {
+							case gophercloud.AvailabilityPublic:
+								url = endpoint.PublicURL
+							case gophercloud.AvailabilityInternal:
+								url = endpoint.InternalURL
+							case gophercloud.AvailabilityAdmin:
+								url = endpoint.AdminURL
+							}
+							if url != "" {
+								urls = append(urls, url)
+							}
+						}
+					}
+				}
+			}
 
-	// Extract the appropriate URL from the matching Endpoint.
-	for _, endpoint := range endpoints {
-		switch opts.Availability {
-		case gophercloud.AvailabilityPublic:
-			return gophercloud.NormalizeURL(endpoint.PublicURL), nil
-		case gophercloud.AvailabilityInternal:
-			return gophercloud.NormalizeURL(endpoint.InternalURL), nil
-		case gophercloud.AvailabilityAdmin:
-			return gophercloud.NormalizeURL(endpoint.AdminURL), nil
-		default:
-			err := &ErrInvalidAvailabilityProvided{}
-			err.Argument = "Availability"
-			err.Value = opts.Availability
-			return "", err
+			if len(urls) > 0 {
+				return chooseEndpointURL(urls, opts)
+			}
 		}
 	}
 
 	// Report an error if there were no matching endpoints.
-	err := &gophercloud.ErrEndpointNotFound{}
-	return "", err
+	return "", &gophercloud.ErrEndpointNotFound{}
 }
 
 /*
 V3EndpointURL discovers the endpoint URL for a specific service from a Catalog
 acquired during the v3 identity service.
 
-The specified EndpointOpts are used to identify a unique, unambiguous endpoint
-to return. It's an error both when multiple endpoints match the provided
-criteria and when none do. The minimum that can be specified is a Type, but you
-will also often need to specify a Name and/or a Region depending on what's
-available on your OpenStack deployment.
+The specified EndpointOpts are used to identify the endpoint to return. The
+minimum that can be specified is a Type, but you will also often need to specify
+a Name and/or a Region depending on what's available on your OpenStack
+deployment. When no catalog entry has the Type, the aliases of the Type are
+tried, and when no endpoint has the Availability, the AvailabilityFallback is
+tried. If multiple endpoints match, the first one is used, unless RequireUnique
+is set.
 */
 func V3EndpointURL(catalog *tokens3.ServiceCatalog, opts gophercloud.EndpointOpts) (string, error) {
-	// Extract Endpoints from the catalog entries that match the requested Type, Interface,
-	// Name if provided, and Region if provided.
-	var endpoints = make([]tokens3.Endpoint, 0, 1)
-	for _, entry := range catalog.Entries {
-		if (entry.Type == opts.Type) && (opts.Name == "" || entry.Name == opts.Name) {
-			for _, endpoint := range entry.Endpoints {
-				if opts.Availability != gophercloud.AvailabilityAdmin &&
-					opts.Availability != gophercloud.AvailabilityPublic &&
-					opts.Availability != gophercloud.AvailabilityInternal {
-					err := &ErrInvalidAvailabilityProvided{}
-					err.Argument = "Availability"
-					err.Value = opts.Availability
-					return "", err
-				}
-				if (opts.Availability == gophercloud.Availability(endpoint.Interface)) &&
-					(opts.Region == "" || endpoint.Region == opts.Region || endpoint.RegionID == opts.Region) {
-					endpoints = append(endpoints, endpoint)
+	availabilities, err := endpointAvailabilities(opts)
+	if err != nil {
+		return "", err
+	}
+
+	for _, availability := range availabilities {
+		for _, serviceType := range endpointServiceTypes(opts) {
+			// Extract the URLs of the Endpoints from the catalog entries that match
+			// the requested Type, Interface, Name if provided, and Region if provided.
+			var urls []string
+			for _, entry := range catalog.Entries {
+				if (entry.Type == serviceType) && (opts.Name == "" || entry.Name == opts.Name) {
+					for _, endpoint := range entry.Endpoints {
+						if (availability == gophercloud.Availability(endpoint.Interface)) &&
+							(opts.Region == "" || endpoint.Region == opts.Region || endpoint.RegionID == opts.Region) {
+							urls = append(urls, endpoint.URL)
+						}
+					}
 				}
 			}
+
+			if len(urls) > 0 {
+				return chooseEndpointURL(urls, opts)
+			}
 		}
 	}
 
-	// If multiple endpoints were found, use the first result
-	// and disregard the other endpoints.
-	//
-	// This behavior matches the Python library. See GH-1764.
-	if len(endpoints) > 1 {
-		endpoints = endpoints[0:1]
+	// Report an error if there were no matching endpoints.
+	return "", &gophercloud.ErrEndpointNotFound{}
+}
+
+// endpointAvailabilities returns the availabilities to try in order, and
+// checks they are valid.
+func endpointAvailabilities(opts gophercloud.EndpointOpts) ([]gophercloud.Availability, error) {
+	availabilities := opts.Availabilities()
+	for _, availability := range availabilities {
+		switch availability {
+		case gophercloud.AvailabilityPublic, gophercloud.AvailabilityInternal, gophercloud.AvailabilityAdmin:
+		default:
+			err := &ErrInvalidAvailabilityProvided{}
+			err.Argument = "Availability"
+			err.Value = availability
+			return nil, err
+		}
 	}
+	return availabilities, nil
+}
 
-	// Extract the URL from the matching Endpoint.
-	for _, endpoint := range endpoints {
-		return gophercloud.NormalizeURL(endpoint.URL), nil
+// endpointServiceTypes returns the service types to look for in order: the
+// requested type, then its aliases.
+func endpointServiceTypes(opts gophercloud.EndpointOpts) []string {
+	return append([]string{opts.Type}, gophercloud.ServiceTypeAliases(opts.Type)...)
+}
+
+// chooseEndpointURL returns the first of the matching urls. If RequireUnique
+// is set, the urls must all be the same.
+//
+// Using the first result matches the behavior of the Python library. See
+// GH-1764.
+func chooseEndpointURL(urls []string, opts gophercloud.EndpointOpts) (string, error) {
+	if opts.RequireUnique {
+		var candidates []string
+		seen := make(map[string]bool)
+		for _, url := range urls {
+			url = gophercloud.NormalizeURL(url)
+			if !seen[url] {
+				seen[url] = true
+				candidates = append(candidates, url)
+			}
+		}
+		if len(candidates) > 1 {
+			return "", &gophercloud.ErrMultipleMatchingEndpoints{
+				ServiceType: opts.Type,
+				Candidates:  candidates,
+			}
+		}
 	}
 
-	// Report an error if there were no matching endpoints.
-	err := &gophercloud.ErrEndpointNotFound{}
-	return "", err
+	return gophercloud.NormalizeURL(urls[0]), nil
 }
@@ -0,0 +1,48 @@
+package openstack
+
+import (
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	tokens2 "github.com/gophercloud/gophercloud/openstack/identity/v2/tokens"
+	tokens3 "github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+)
+
+// endpointOverride returns the endpoint of client.EndpointOverrides for
+// serviceType or, failing that, for one of its aliases, with the project ID
+// of the token of client filled in.
+func endpointOverride(client *gophercloud.ProviderClient, serviceType string) (string, bool) {
+	if len(client.EndpointOverrides) == 0 {
+		return "", false
+	}
+
+	for _, t := range append([]string{serviceType}, gophercloud.ServiceTypeAliases(serviceType)...) {
+		if url, ok := client.EndpointOverrides[t]; ok {
+			if strings.Contains(url, "%(project_id)s") {
+				url = strings.Replace(url, "%(project_id)s", authResultProjectID(client.GetAuthResult()), -1)
+			}
+			return gophercloud.NormalizeURL(url), true
+		}
+	}
+	return "", false
+}
+
+// authResultProjectID returns the ID of the project the token of r is scoped
+// to, or an empty string if it isn't scoped to a project.
+func authResultProjectID(r gophercloud.AuthResult) string {
+	switch r := r.(type) {
+	case tokens3.CreateResult:
+		if project, err := r.ExtractProject(); err == nil && project != nil {
+			return project.ID
+		}
+	case tokens3.GetResult:
+		if project, err := r.ExtractProject(); err == nil && project != nil {
+			return project.ID
+		}
+	case tokens2.CreateResult:
+		if token, err := r.ExtractToken(); err == nil && token.Tenant.ID != "" {
+			return token.Tenant.ID
+		}
+	}
+	return ""
+}
@@ -45,3 +45,44 @@ type ErrNoPassword struct{ gophercloud.ErrInvalidInput }
 func (e ErrNoPassword) Error() string {
 	return "Environment variable OS_PASSWORD needs to be set."
 }
+
+// ErrCloudsYAMLNotFound is the error when no clouds.yaml file can be found
+type ErrCloudsYAMLNotFound struct{ gophercloud.BaseError }
+
+func (e ErrCloudsYAMLNotFound) Error() string {
+	return "Unable to find a clouds.yaml file. Set OS_CLIENT_CONFIG_FILE or create one in the current directory, ~/.config/openstack or /etc/openstack."
+}
+
+// ErrCloudNotFound is the error when a cloud or a profile is missing from a
+// clouds.yaml or clouds-public.yaml file
+type ErrCloudNotFound struct {
+	gophercloud.BaseError
+	Cloud string
+	File  string
+}
+
+func (e ErrCloudNotFound) Error() string {
+	return fmt.Sprintf("Cloud %s not found in %s", e.Cloud, e.File)
+}
+
+// ErrMissingCloudSetting is the error when a setting required by the auth
+// type of a cloud is missing from clouds.yaml
+type ErrMissingCloudSetting struct {
+	gophercloud.BaseError
+	Setting string
+}
+
+func (e ErrMissingCloudSetting) Error() string {
+	return fmt.Sprintf("Missing setting %s in clouds.yaml", e.Setting)
+}
+
+// ErrUnsupportedAuthType is the error when the auth_type of a cloud is not
+// supported
+type ErrUnsupportedAuthType struct {
+	gophercloud.BaseError
+	AuthType string
+}
+
+func (e ErrUnsupportedAuthType) Error() string {
+	return fmt.Sprintf("Unsupported auth_type %s in clouds.yaml", e.AuthType)
+}
@@ -150,6 +150,16 @@ func (r CreateResult) ExtractTokenID() (string, error) {
 	return s.Access.Token.ID, err
 }
 
+// ExtractExpiresAt returns the expiration time of the Token. It is used by the
+// ProviderClient to refresh the token before it expires.
+func (r CreateResult) ExtractExpiresAt() (time.Time, error) {
+	token, err := r.ExtractToken()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return token.ExpiresAt, nil
+}
+
 // ExtractServiceCatalog returns the ServiceCatalog that was generated along
 // with the user's Token.
 func (r CreateResult) ExtractServiceCatalog() (*ServiceCatalog, error) {
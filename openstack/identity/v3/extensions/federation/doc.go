@@ -0,0 +1,82 @@
+/*
+Package federation manages the identity providers, mappings, protocols and
+service providers of the OS-FEDERATION extension of the OpenStack Identity
+Service, and lists the projects and domains available to federated users.
+
+Example to Create an Identity Provider
+
+	createOpts := federation.CreateIdentityProviderOpts{
+		Description: "Stores ACME identities",
+		Enabled:     gophercloud.Enabled,
+		RemoteIDs:   []string{"https://idp.acme.example.com"},
+	}
+
+	idp, err := federation.CreateIdentityProvider(identityClient, "ACME", createOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Create a Mapping
+
+	createOpts := federation.CreateMappingOpts{
+		Rules: []federation.MappingRule{
+			{
+				Local: []federation.RuleLocal{
+					{
+						User: &federation.RuleUser{
+							Name: "{0}",
+							Type: federation.UserTypeEphemeral,
+						},
+					},
+					{
+						Group: &federation.RuleGroup{
+							ID: "0cd5e9",
+						},
+					},
+				},
+				Remote: []federation.RuleRemote{
+					{
+						Type: "UserName",
+					},
+					{
+						Type:     "orgPersonType",
+						NotAnyOf: []string{"Contractor", "Guest"},
+					},
+				},
+			},
+		},
+	}
+
+	mapping, err := federation.CreateMapping(identityClient, "ACME", createOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Create a Protocol
+
+	createOpts := federation.CreateProtocolOpts{
+		MappingID: "ACME",
+	}
+
+	protocol, err := federation.CreateProtocol(identityClient, "ACME", "saml2", createOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to List the Projects Available to a Federated User
+
+	allPages, err := federation.ListAvailableProjects(identityClient).AllPages()
+	if err != nil {
+		panic(err)
+	}
+
+	allProjects, err := projects.ExtractProjects(allPages)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, project := range allProjects {
+		fmt.Printf("%+v\n", project)
+	}
+*/
+package federation
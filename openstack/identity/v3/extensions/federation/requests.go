@@ -0,0 +1,470 @@
+package federation
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/domains"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// ListIdentityProvidersOptsBuilder allows extensions to add additional
+// parameters to the ListIdentityProviders request.
+type ListIdentityProvidersOptsBuilder interface {
+	ToIdentityProviderListQuery() (string, error)
+}
+
+// ListIdentityProvidersOpts enables filtering of a ListIdentityProviders
+// request.
+type ListIdentityProvidersOpts struct {
+	// ID filters the response by an identity provider ID.
+	ID string `q:"id"`
+
+	// Enabled filters the response by enabled identity providers.
+	Enabled *bool `q:"enabled"`
+}
+
+// ToIdentityProviderListQuery formats a ListIdentityProvidersOpts into a
+// query string.
+func (opts ListIdentityProvidersOpts) ToIdentityProviderListQuery() (string, error) {
+	q, err := gophercloud.BuildQueryString(opts)
+	return q.String(), err
+}
+
+// ListIdentityProviders enumerates the identity providers.
+func ListIdentityProviders(client *gophercloud.ServiceClient, opts ListIdentityProvidersOptsBuilder) pagination.Pager {
+	url := identityProvidersURL(client)
+	if opts != nil {
+		query, err := opts.ToIdentityProviderListQuery()
+		if err != nil {
+			return pagination.Pager{Err: err}
+		}
+		url += query
+	}
+	return pagination.NewPager(client, url, func(r pagination.PageResult) pagination.Page {
+		return IdentityProviderPage{pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// GetIdentityProvider retrieves details on a single identity provider, by
+// ID.
+func GetIdentityProvider(client *gophercloud.ServiceClient, id string) (r GetIdentityProviderResult) {
+	resp, err := client.Get(identityProviderURL(client, id), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// CreateIdentityProviderOptsBuilder allows extensions to add additional
+// parameters to the CreateIdentityProvider request.
+type CreateIdentityProviderOptsBuilder interface {
+	ToIdentityProviderCreateMap() (map[string]interface{}, error)
+}
+
+// CreateIdentityProviderOpts represents parameters used to create an identity
+// provider.
+type CreateIdentityProviderOpts struct {
+	// Description is the description of the identity provider.
+	Description string `json:"description,omitempty"`
+
+	// DomainID is the domain of the federated users. When not set, Keystone
+	// creates a domain for the identity provider.
+	DomainID string `json:"domain_id,omitempty"`
+
+	// Enabled sets the identity provider status to enabled or disabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// RemoteIDs are the entity IDs of the identity provider, e.g. the issuer
+	// of an OpenID Connect provider.
+	RemoteIDs []string `json:"remote_ids,omitempty"`
+
+	// AuthorizationTTL is the number of minutes the group memberships of the
+	// federated users remain valid.
+	AuthorizationTTL *int `json:"authorization_ttl,omitempty"`
+}
+
+// ToIdentityProviderCreateMap formats a CreateIdentityProviderOpts into a
+// create request.
+func (opts CreateIdentityProviderOpts) ToIdentityProviderCreateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "identity_provider")
+}
+
+// CreateIdentityProvider creates an identity provider with the given ID.
+func CreateIdentityProvider(client *gophercloud.ServiceClient, id string, opts CreateIdentityProviderOptsBuilder) (r CreateIdentityProviderResult) {
+	b, err := opts.ToIdentityProviderCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Put(identityProviderURL(client, id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// UpdateIdentityProviderOptsBuilder allows extensions to add additional
+// parameters to the UpdateIdentityProvider request.
+type UpdateIdentityProviderOptsBuilder interface {
+	ToIdentityProviderUpdateMap() (map[string]interface{}, error)
+}
+
+// UpdateIdentityProviderOpts represents parameters to update an identity
+// provider.
+type UpdateIdentityProviderOpts struct {
+	// Description is the description of the identity provider.
+	Description *string `json:"description,omitempty"`
+
+	// Enabled sets the identity provider status to enabled or disabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// RemoteIDs are the entity IDs of the identity provider.
+	RemoteIDs *[]string `json:"remote_ids,omitempty"`
+
+	// AuthorizationTTL is the number of minutes the group memberships of the
+	// federated users remain valid.
+	AuthorizationTTL *int `json:"authorization_ttl,omitempty"`
+}
+
+// ToIdentityProviderUpdateMap formats an UpdateIdentityProviderOpts into an
+// update request.
+func (opts UpdateIdentityProviderOpts) ToIdentityProviderUpdateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "identity_provider")
+}
+
+// UpdateIdentityProvider modifies the attributes of an identity provider.
+func UpdateIdentityProvider(client *gophercloud.ServiceClient, id string, opts UpdateIdentityProviderOptsBuilder) (r UpdateIdentityProviderResult) {
+	b, err := opts.ToIdentityProviderUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Patch(identityProviderURL(client, id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// DeleteIdentityProvider deletes an identity provider, with its protocols.
+func DeleteIdentityProvider(client *gophercloud.ServiceClient, id string) (r DeleteIdentityProviderResult) {
+	resp, err := client.Delete(identityProviderURL(client, id), nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// ListMappings enumerates the mappings.
+func ListMappings(client *gophercloud.ServiceClient) pagination.Pager {
+	return pagination.NewPager(client, mappingsURL(client), func(r pagination.PageResult) pagination.Page {
+		return MappingPage{pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// GetMapping retrieves details on a single mapping, by ID.
+func GetMapping(client *gophercloud.ServiceClient, id string) (r GetMappingResult) {
+	resp, err := client.Get(mappingURL(client, id), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// CreateMappingOptsBuilder allows extensions to add additional parameters to
+// the CreateMapping request.
+type CreateMappingOptsBuilder interface {
+	ToMappingCreateMap() (map[string]interface{}, error)
+}
+
+// CreateMappingOpts represents parameters used to create a mapping.
+type CreateMappingOpts struct {
+	// Rules map the attributes of the federated users to local users,
+	// groups and projects.
+	Rules []MappingRule `json:"rules" required:"true"`
+
+	// SchemaVersion is the version of the schema of the rules.
+	SchemaVersion string `json:"schema_version,omitempty"`
+}
+
+// ToMappingCreateMap formats a CreateMappingOpts into a create request.
+func (opts CreateMappingOpts) ToMappingCreateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "mapping")
+}
+
+// CreateMapping creates a mapping with the given ID.
+func CreateMapping(client *gophercloud.ServiceClient, id string, opts CreateMappingOptsBuilder) (r CreateMappingResult) {
+	b, err := opts.ToMappingCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Put(mappingURL(client, id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// UpdateMappingOptsBuilder allows extensions to add additional parameters to
+// the UpdateMapping request.
+type UpdateMappingOptsBuilder interface {
+	ToMappingUpdateMap() (map[string]interface{}, error)
+}
+
+// UpdateMappingOpts represents parameters to update a mapping. The rules
+// replace the current ones.
+type UpdateMappingOpts struct {
+	// Rules map the attributes of the federated users to local users,
+	// groups and projects.
+	Rules []MappingRule `json:"rules" required:"true"`
+
+	// SchemaVersion is the version of the schema of the rules.
+	SchemaVersion string `json:"schema_version,omitempty"`
+}
+
+// ToMappingUpdateMap formats an UpdateMappingOpts into an update request.
+func (opts UpdateMappingOpts) ToMappingUpdateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "mapping")
+}
+
+// UpdateMapping replaces the rules of a mapping.
+func UpdateMapping(client *gophercloud.ServiceClient, id string, opts UpdateMappingOptsBuilder) (r UpdateMappingResult) {
+	b, err := opts.ToMappingUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Patch(mappingURL(client, id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// DeleteMapping deletes a mapping.
+func DeleteMapping(client *gophercloud.ServiceClient, id string) (r DeleteMappingResult) {
+	resp, err := client.Delete(mappingURL(client, id), nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// ListProtocols enumerates the protocols of an identity provider.
+func ListProtocols(client *gophercloud.ServiceClient, idpID string) pagination.Pager {
+	return pagination.NewPager(client, protocolsURL(client, idpID), func(r pagination.PageResult) pagination.Page {
+		return ProtocolPage{pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// GetProtocol retrieves details on a single protocol of an identity provider.
+func GetProtocol(client *gophercloud.ServiceClient, idpID, id string) (r GetProtocolResult) {
+	resp, err := client.Get(protocolURL(client, idpID, id), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// CreateProtocolOptsBuilder allows extensions to add additional parameters to
+// the CreateProtocol request.
+type CreateProtocolOptsBuilder interface {
+	ToProtocolCreateMap() (map[string]interface{}, error)
+}
+
+// CreateProtocolOpts represents parameters used to create a protocol.
+type CreateProtocolOpts struct {
+	// MappingID is the ID of the mapping applied to the federated users.
+	MappingID string `json:"mapping_id" required:"true"`
+
+	// RemoteIDAttribute is the attribute of the assertion holding the entity
+	// ID of the identity provider.
+	RemoteIDAttribute string `json:"remote_id_attribute,omitempty"`
+}
+
+// ToProtocolCreateMap formats a CreateProtocolOpts into a create request.
+func (opts CreateProtocolOpts) ToProtocolCreateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "protocol")
+}
+
+// CreateProtocol creates a protocol, e.g. "openid" or "saml2", for an
+// identity provider.
+func CreateProtocol(client *gophercloud.ServiceClient, idpID, id string, opts CreateProtocolOptsBuilder) (r CreateProtocolResult) {
+	b, err := opts.ToProtocolCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Put(protocolURL(client, idpID, id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// UpdateProtocolOptsBuilder allows extensions to add additional parameters to
+// the UpdateProtocol request.
+type UpdateProtocolOptsBuilder interface {
+	ToProtocolUpdateMap() (map[string]interface{}, error)
+}
+
+// UpdateProtocolOpts represents parameters to update a protocol.
+type UpdateProtocolOpts struct {
+	// MappingID is the ID of the mapping applied to the federated users.
+	MappingID string `json:"mapping_id,omitempty"`
+
+	// RemoteIDAttribute is the attribute of the assertion holding the entity
+	// ID of the identity provider.
+	RemoteIDAttribute *string `json:"remote_id_attribute,omitempty"`
+}
+
+// ToProtocolUpdateMap formats an UpdateProtocolOpts into an update request.
+func (opts UpdateProtocolOpts) ToProtocolUpdateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "protocol")
+}
+
+// UpdateProtocol modifies the attributes of a protocol.
+func UpdateProtocol(client *gophercloud.ServiceClient, idpID, id string, opts UpdateProtocolOptsBuilder) (r UpdateProtocolResult) {
+	b, err := opts.ToProtocolUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Patch(protocolURL(client, idpID, id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// DeleteProtocol deletes a protocol of an identity provider.
+func DeleteProtocol(client *gophercloud.ServiceClient, idpID, id string) (r DeleteProtocolResult) {
+	resp, err := client.Delete(protocolURL(client, idpID, id), nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// ListServiceProviders enumerates the service providers.
+func ListServiceProviders(client *gophercloud.ServiceClient) pagination.Pager {
+	return pagination.NewPager(client, serviceProvidersURL(client), func(r pagination.PageResult) pagination.Page {
+		return ServiceProviderPage{pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// GetServiceProvider retrieves details on a single service provider, by ID.
+func GetServiceProvider(client *gophercloud.ServiceClient, id string) (r GetServiceProviderResult) {
+	resp, err := client.Get(serviceProviderURL(client, id), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// CreateServiceProviderOptsBuilder allows extensions to add additional
+// parameters to the CreateServiceProvider request.
+type CreateServiceProviderOptsBuilder interface {
+	ToServiceProviderCreateMap() (map[string]interface{}, error)
+}
+
+// CreateServiceProviderOpts represents parameters used to create a service
+// provider.
+type CreateServiceProviderOpts struct {
+	// AuthURL is the federated auth URL of the service provider, i.e.
+	// https://sp.example.com:5000/v3/OS-FEDERATION/identity_providers/{idp}/protocols/saml2/auth.
+	AuthURL string `json:"auth_url" required:"true"`
+
+	// SPURL is the URL of the service provider accepting the SAML2 ECP
+	// assertions, i.e. https://sp.example.com:5000/Shibboleth.sso/SAML2/ECP.
+	SPURL string `json:"sp_url" required:"true"`
+
+	// Description is the description of the service provider.
+	Description string `json:"description,omitempty"`
+
+	// Enabled sets the service provider status to enabled or disabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// RelayStatePrefix is the prefix of the RelayState of the SAML2 ECP
+	// assertions.
+	RelayStatePrefix string `json:"relay_state_prefix,omitempty"`
+}
+
+// ToServiceProviderCreateMap formats a CreateServiceProviderOpts into a
+// create request.
+func (opts CreateServiceProviderOpts) ToServiceProviderCreateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "service_provider")
+}
+
+// CreateServiceProvider creates a service provider with the given ID.
+func CreateServiceProvider(client *gophercloud.ServiceClient, id string, opts CreateServiceProviderOptsBuilder) (r CreateServiceProviderResult) {
+	b, err := opts.ToServiceProviderCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Put(serviceProviderURL(client, id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// UpdateServiceProviderOptsBuilder allows extensions to add additional
+// parameters to the UpdateServiceProvider request.
+type UpdateServiceProviderOptsBuilder interface {
+	ToServiceProviderUpdateMap() (map[string]interface{}, error)
+}
+
+// UpdateServiceProviderOpts represents parameters to update a service
+// provider.
+type UpdateServiceProviderOpts struct {
+	// AuthURL is the federated auth URL of the service provider.
+	AuthURL string `json:"auth_url,omitempty"`
+
+	// SPURL is the URL of the service provider accepting the SAML2 ECP
+	// assertions.
+	SPURL string `json:"sp_url,omitempty"`
+
+	// Description is the description of the service provider.
+	Description *string `json:"description,omitempty"`
+
+	// Enabled sets the service provider status to enabled or disabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// RelayStatePrefix is the prefix of the RelayState of the SAML2 ECP
+	// assertions.
+	RelayStatePrefix *string `json:"relay_state_prefix,omitempty"`
+}
+
+// ToServiceProviderUpdateMap formats an UpdateServiceProviderOpts into an
+// update request.
+func (opts UpdateServiceProviderOpts) ToServiceProviderUpdateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "service_provider")
+}
+
+// UpdateServiceProvider modifies the attributes of a service provider.
+func UpdateServiceProvider(client *gophercloud.ServiceClient, id string, opts UpdateServiceProviderOptsBuilder) (r UpdateServiceProviderResult) {
+	b, err := opts.ToServiceProviderUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Patch(serviceProviderURL(client, id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// DeleteServiceProvider deletes a service provider.
+func DeleteServiceProvider(client *gophercloud.ServiceClient, id string) (r DeleteServiceProviderResult) {
+	resp, err := client.Delete(serviceProviderURL(client, id), nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// ListAvailableProjects enumerates the projects available to the federated
+// user of the token of the client. Use projects.ExtractProjects to interpret
+// the pages.
+func ListAvailableProjects(client *gophercloud.ServiceClient) pagination.Pager {
+	return pagination.NewPager(client, availableProjectsURL(client), func(r pagination.PageResult) pagination.Page {
+		return projects.ProjectPage{LinkedPageBase: pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// ListAvailableDomains enumerates the domains available to the federated user
+// of the token of the client. Use domains.ExtractDomains to interpret the
+// pages.
+func ListAvailableDomains(client *gophercloud.ServiceClient) pagination.Pager {
+	return pagination.NewPager(client, availableDomainsURL(client), func(r pagination.PageResult) pagination.Page {
+		return domains.DomainPage{LinkedPageBase: pagination.LinkedPageBase{PageResult: r}}
+	})
+}
@@ -0,0 +1,460 @@
+package federation
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// IdentityProvider represents an identity provider trusted by Keystone.
+type IdentityProvider struct {
+	// ID is the unique ID of the identity provider.
+	ID string `json:"id"`
+
+	// Description is the description of the identity provider.
+	Description string `json:"description"`
+
+	// DomainID is the domain of the federated users.
+	DomainID string `json:"domain_id"`
+
+	// Enabled is whether or not the identity provider is enabled.
+	Enabled bool `json:"enabled"`
+
+	// RemoteIDs are the entity IDs of the identity provider.
+	RemoteIDs []string `json:"remote_ids"`
+
+	// AuthorizationTTL is the number of minutes the group memberships of the
+	// federated users remain valid.
+	AuthorizationTTL *int `json:"authorization_ttl"`
+
+	// Links contains referencing links to the identity provider.
+	Links map[string]interface{} `json:"links"`
+}
+
+// MappingRule maps the attributes of the federated users, matched by the
+// Remote conditions, to the Local users, groups and projects.
+type MappingRule struct {
+	// Local are the local attributes of the matching users.
+	Local []RuleLocal `json:"local"`
+
+	// Remote are the conditions on the attributes of the federated users.
+	Remote []RuleRemote `json:"remote"`
+}
+
+// RuleLocal is a local attribute of the users matching a MappingRule. The
+// values can reference the remote attributes with "{0}", "{1}", etc.
+type RuleLocal struct {
+	// Domain is the domain of the user, groups or projects.
+	Domain *RuleDomain `json:"domain,omitempty"`
+
+	// Group is a group the user is a member of.
+	Group *RuleGroup `json:"group,omitempty"`
+
+	// GroupIDs are the IDs of groups the user is a member of.
+	GroupIDs string `json:"group_ids,omitempty"`
+
+	// Groups are the names of groups the user is a member of.
+	Groups string `json:"groups,omitempty"`
+
+	// Projects are projects created for the user, with their roles.
+	Projects []RuleProject `json:"projects,omitempty"`
+
+	// User is the local user.
+	User *RuleUser `json:"user,omitempty"`
+}
+
+// RuleDomain references a domain in a MappingRule.
+type RuleDomain struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// RuleGroup references a group in a MappingRule.
+type RuleGroup struct {
+	ID     string      `json:"id,omitempty"`
+	Name   string      `json:"name,omitempty"`
+	Domain *RuleDomain `json:"domain,omitempty"`
+}
+
+// RuleProject is a project created for the users matching a MappingRule.
+type RuleProject struct {
+	Name  string     `json:"name"`
+	Roles []RuleRole `json:"roles,omitempty"`
+}
+
+// RuleRole is a role on a RuleProject.
+type RuleRole struct {
+	Name string `json:"name"`
+}
+
+// UserType is the type of the users matching a MappingRule.
+type UserType string
+
+const (
+	// UserTypeEphemeral users only exist while they are authenticated.
+	UserTypeEphemeral UserType = "ephemeral"
+
+	// UserTypeLocal users are existing local users.
+	UserTypeLocal UserType = "local"
+)
+
+// RuleUser is the local user of the users matching a MappingRule.
+type RuleUser struct {
+	ID     string      `json:"id,omitempty"`
+	Name   string      `json:"name,omitempty"`
+	Email  string      `json:"email,omitempty"`
+	Type   UserType    `json:"type,omitempty"`
+	Domain *RuleDomain `json:"domain,omitempty"`
+}
+
+// RuleRemote is a condition on an attribute of the federated users. Without
+// AnyOneOf, NotAnyOf, Whitelist or Blacklist, it matches the users having the
+// attribute.
+type RuleRemote struct {
+	// Type is the name of the attribute, e.g. "OIDC-preferred_username".
+	Type string `json:"type"`
+
+	// AnyOneOf matches the users with one of the values.
+	AnyOneOf []string `json:"any_one_of,omitempty"`
+
+	// NotAnyOf matches the users with none of the values.
+	NotAnyOf []string `json:"not_any_of,omitempty"`
+
+	// Regex specifies whether AnyOneOf and NotAnyOf are regular expressions.
+	Regex *bool `json:"regex,omitempty"`
+
+	// Whitelist keeps only the listed values of the attribute, e.g. groups.
+	Whitelist []string `json:"whitelist,omitempty"`
+
+	// Blacklist removes the listed values of the attribute.
+	Blacklist []string `json:"blacklist,omitempty"`
+}
+
+// Mapping represents a set of rules mapping the federated users to local
+// users, groups and projects.
+type Mapping struct {
+	// ID is the unique ID of the mapping.
+	ID string `json:"id"`
+
+	// Rules are the rules of the mapping.
+	Rules []MappingRule `json:"rules"`
+
+	// SchemaVersion is the version of the schema of the rules.
+	SchemaVersion string `json:"schema_version"`
+
+	// Links contains referencing links to the mapping.
+	Links map[string]interface{} `json:"links"`
+}
+
+// Protocol represents a federation protocol of an identity provider.
+type Protocol struct {
+	// ID is the name of the protocol, e.g. "openid" or "saml2".
+	ID string `json:"id"`
+
+	// MappingID is the ID of the mapping applied to the federated users.
+	MappingID string `json:"mapping_id"`
+
+	// RemoteIDAttribute is the attribute of the assertion holding the entity
+	// ID of the identity provider.
+	RemoteIDAttribute string `json:"remote_id_attribute"`
+
+	// Links contains referencing links to the protocol.
+	Links map[string]interface{} `json:"links"`
+}
+
+// ServiceProvider represents a Keystone service provider trusting this
+// Keystone as an identity provider.
+type ServiceProvider struct {
+	// ID is the unique ID of the service provider.
+	ID string `json:"id"`
+
+	// AuthURL is the federated auth URL of the service provider.
+	AuthURL string `json:"auth_url"`
+
+	// SPURL is the URL of the service provider accepting the SAML2 ECP
+	// assertions.
+	SPURL string `json:"sp_url"`
+
+	// Description is the description of the service provider.
+	Description string `json:"description"`
+
+	// Enabled is whether or not the service provider is enabled.
+	Enabled bool `json:"enabled"`
+
+	// RelayStatePrefix is the prefix of the RelayState of the SAML2 ECP
+	// assertions.
+	RelayStatePrefix string `json:"relay_state_prefix"`
+
+	// Links contains referencing links to the service provider.
+	Links map[string]interface{} `json:"links"`
+}
+
+type identityProviderResult struct {
+	gophercloud.Result
+}
+
+// Extract interprets any identityProviderResult as an IdentityProvider.
+func (r identityProviderResult) Extract() (*IdentityProvider, error) {
+	var s struct {
+		IdentityProvider *IdentityProvider `json:"identity_provider"`
+	}
+	err := r.ExtractInto(&s)
+	return s.IdentityProvider, err
+}
+
+// GetIdentityProviderResult is the result of a GetIdentityProvider request.
+// Call its Extract method to interpret it as an IdentityProvider.
+type GetIdentityProviderResult struct {
+	identityProviderResult
+}
+
+// CreateIdentityProviderResult is the result of a CreateIdentityProvider
+// request. Call its Extract method to interpret it as an IdentityProvider.
+type CreateIdentityProviderResult struct {
+	identityProviderResult
+}
+
+// UpdateIdentityProviderResult is the result of an UpdateIdentityProvider
+// request. Call its Extract method to interpret it as an IdentityProvider.
+type UpdateIdentityProviderResult struct {
+	identityProviderResult
+}
+
+// DeleteIdentityProviderResult is the result of a DeleteIdentityProvider
+// request. Call its ExtractErr method to determine if the request succeeded
+// or failed.
+type DeleteIdentityProviderResult struct {
+	gophercloud.ErrResult
+}
+
+// IdentityProviderPage is a single page of IdentityProvider results.
+type IdentityProviderPage struct {
+	pagination.LinkedPageBase
+}
+
+// IsEmpty determines whether or not a page of IdentityProviders contains any
+// results.
+func (r IdentityProviderPage) IsEmpty() (bool, error) {
+	identityProviders, err := ExtractIdentityProviders(r)
+	return len(identityProviders) == 0, err
+}
+
+// NextPageURL extracts the "next" link from the links section of the result.
+func (r IdentityProviderPage) NextPageURL() (string, error) {
+	return nextPageURL(r.LinkedPageBase)
+}
+
+// ExtractIdentityProviders returns a slice of IdentityProviders contained in
+// a single page of results.
+func ExtractIdentityProviders(r pagination.Page) ([]IdentityProvider, error) {
+	var s struct {
+		IdentityProviders []IdentityProvider `json:"identity_providers"`
+	}
+	err := (r.(IdentityProviderPage)).ExtractInto(&s)
+	return s.IdentityProviders, err
+}
+
+type mappingResult struct {
+	gophercloud.Result
+}
+
+// Extract interprets any mappingResult as a Mapping.
+func (r mappingResult) Extract() (*Mapping, error) {
+	var s struct {
+		Mapping *Mapping `json:"mapping"`
+	}
+	err := r.ExtractInto(&s)
+	return s.Mapping, err
+}
+
+// GetMappingResult is the result of a GetMapping request. Call its Extract
+// method to interpret it as a Mapping.
+type GetMappingResult struct {
+	mappingResult
+}
+
+// CreateMappingResult is the result of a CreateMapping request. Call its
+// Extract method to interpret it as a Mapping.
+type CreateMappingResult struct {
+	mappingResult
+}
+
+// UpdateMappingResult is the result of an UpdateMapping request. Call its
+// Extract method to interpret it as a Mapping.
+type UpdateMappingResult struct {
+	mappingResult
+}
+
+// DeleteMappingResult is the result of a DeleteMapping request. Call its
+// ExtractErr method to determine if the request succeeded or failed.
+type DeleteMappingResult struct {
+	gophercloud.ErrResult
+}
+
+// MappingPage is a single page of Mapping results.
+type MappingPage struct {
+	pagination.LinkedPageBase
+}
+
+// IsEmpty determines whether or not a page of Mappings contains any results.
+func (r MappingPage) IsEmpty() (bool, error) {
+	mappings, err := ExtractMappings(r)
+	return len(mappings) == 0, err
+}
+
+// NextPageURL extracts the "next" link from the links section of the result.
+func (r MappingPage) NextPageURL() (string, error) {
+	return nextPageURL(r.LinkedPageBase)
+}
+
+// ExtractMappings returns a slice of Mappings contained in a single page of
+// results.
+func ExtractMappings(r pagination.Page) ([]Mapping, error) {
+	var s struct {
+		Mappings []Mapping `json:"mappings"`
+	}
+	err := (r.(MappingPage)).ExtractInto(&s)
+	return s.Mappings, err
+}
+
+type protocolResult struct {
+	gophercloud.Result
+}
+
+// Extract interprets any protocolResult as a Protocol.
+func (r protocolResult) Extract() (*Protocol, error) {
+	var s struct {
+		Protocol *Protocol `json:"protocol"`
+	}
+	err := r.ExtractInto(&s)
+	return s.Protocol, err
+}
+
+// GetProtocolResult is the result of a GetProtocol request. Call its Extract
+// method to interpret it as a Protocol.
+type GetProtocolResult struct {
+	protocolResult
+}
+
+// CreateProtocolResult is the result of a CreateProtocol request. Call its
+// Extract method to interpret it as a Protocol.
+type CreateProtocolResult struct {
+	protocolResult
+}
+
+// UpdateProtocolResult is the result of an UpdateProtocol request. Call its
+// Extract method to interpret it as a Protocol.
+type UpdateProtocolResult struct {
+	protocolResult
+}
+
+// DeleteProtocolResult is the result of a DeleteProtocol request. Call its
+// ExtractErr method to determine if the request succeeded or failed.
+type DeleteProtocolResult struct {
+	gophercloud.ErrResult
+}
+
+// ProtocolPage is a single page of Protocol results.
+type ProtocolPage struct {
+	pagination.LinkedPageBase
+}
+
+// IsEmpty determines whether or not a page of Protocols contains any results.
+func (r ProtocolPage) IsEmpty() (bool, error) {
+	protocols, err := ExtractProtocols(r)
+	return len(protocols) == 0, err
+}
+
+// NextPageURL extracts the "next" link from the links section of the result.
+func (r ProtocolPage) NextPageURL() (string, error) {
+	return nextPageURL(r.LinkedPageBase)
+}
+
+// ExtractProtocols returns a slice of Protocols contained in a single page of
+// results.
+func ExtractProtocols(r pagination.Page) ([]Protocol, error) {
+	var s struct {
+		Protocols []Protocol `json:"protocols"`
+	}
+	err := (r.(ProtocolPage)).ExtractInto(&s)
+	return s.Protocols, err
+}
+
+type serviceProviderResult struct {
+	gophercloud.Result
+}
+
+// Extract interprets any serviceProviderResult as a ServiceProvider.
+func (r serviceProviderResult) Extract() (*ServiceProvider, error) {
+	var s struct {
+		ServiceProvider *ServiceProvider `json:"service_provider"`
+	}
+	err := r.ExtractInto(&s)
+	return s.ServiceProvider, err
+}
+
+// GetServiceProviderResult is the result of a GetServiceProvider request.
+// Call its Extract method to interpret it as a ServiceProvider.
+type GetServiceProviderResult struct {
+	serviceProviderResult
+}
+
+// CreateServiceProviderResult is the result of a CreateServiceProvider
+// request. Call its Extract method to interpret it as a ServiceProvider.
+type CreateServiceProviderResult struct {
+	serviceProviderResult
+}
+
+// UpdateServiceProviderResult is the result of an UpdateServiceProvider
+// request. Call its Extract method to interpret it as a ServiceProvider.
+type UpdateServiceProviderResult struct {
+	serviceProviderResult
+}
+
+// DeleteServiceProviderResult is the result of a DeleteServiceProvider
+// request. Call its ExtractErr method to determine if the request succeeded
+// or failed.
+type DeleteServiceProviderResult struct {
+	gophercloud.ErrResult
+}
+
+// ServiceProviderPage is a single page of ServiceProvider results.
+type ServiceProviderPage struct {
+	pagination.LinkedPageBase
+}
+
+// IsEmpty determines whether or not a page of ServiceProviders contains any
+// results.
+func (r ServiceProviderPage) IsEmpty() (bool, error) {
+	serviceProviders, err := ExtractServiceProviders(r)
+	return len(serviceProviders) == 0, err
+}
+
+// NextPageURL extracts the "next" link from the links section of the result.
+func (r ServiceProviderPage) NextPageURL() (string, error) {
+	return nextPageURL(r.LinkedPageBase)
+}
+
+// ExtractServiceProviders returns a slice of ServiceProviders contained in a
+// single page of results.
+func ExtractServiceProviders(r pagination.Page) ([]ServiceProvider, error) {
+	var s struct {
+		ServiceProviders []ServiceProvider `json:"service_providers"`
+	}
+	err := (r.(ServiceProviderPage)).ExtractInto(&s)
+	return s.ServiceProviders, err
+}
+
+// nextPageURL extracts the "next" link from the links section of a page.
+func nextPageURL(r pagination.LinkedPageBase) (string, error) {
+	var s struct {
+		Links struct {
+			Next     string `json:"next"`
+			Previous string `json:"previous"`
+		} `json:"links"`
+	}
+	err := r.ExtractInto(&s)
+	if err != nil {
+		return "", err
+	}
+	return s.Links.Next, err
+}
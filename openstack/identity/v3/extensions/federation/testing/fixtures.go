@@ -0,0 +1,583 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/federation"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+// ListIdentityProvidersOutput provides a single page of IdentityProvider
+// results.
+const ListIdentityProvidersOutput = `
+{
+  "identity_providers": [
+    {
+      "authorization_ttl": null,
+      "description": "Stores ACME identities",
+      "domain_id": "1789d1",
+      "enabled": true,
+      "id": "ACME",
+      "remote_ids": ["https://idp.acme.example.com"],
+      "links": {
+        "protocols": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols",
+        "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME"
+      }
+    }
+  ],
+  "links": {
+    "next": null,
+    "previous": null,
+    "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers"
+  }
+}
+`
+
+// GetIdentityProviderOutput provides a GetIdentityProvider result.
+const GetIdentityProviderOutput = `
+{
+  "identity_provider": {
+    "authorization_ttl": null,
+    "description": "Stores ACME identities",
+    "domain_id": "1789d1",
+    "enabled": true,
+    "id": "ACME",
+    "remote_ids": ["https://idp.acme.example.com"],
+    "links": {
+      "protocols": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols",
+      "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME"
+    }
+  }
+}
+`
+
+// CreateIdentityProviderRequest provides the input to a
+// CreateIdentityProvider request.
+const CreateIdentityProviderRequest = `
+{
+  "identity_provider": {
+    "description": "Stores ACME identities",
+    "domain_id": "1789d1",
+    "enabled": true,
+    "remote_ids": ["https://idp.acme.example.com"]
+  }
+}
+`
+
+// UpdateIdentityProviderRequest provides the input to an
+// UpdateIdentityProvider request.
+const UpdateIdentityProviderRequest = `
+{
+  "identity_provider": {
+    "enabled": false
+  }
+}
+`
+
+// UpdateIdentityProviderOutput provides an UpdateIdentityProvider result.
+const UpdateIdentityProviderOutput = `
+{
+  "identity_provider": {
+    "authorization_ttl": null,
+    "description": "Stores ACME identities",
+    "domain_id": "1789d1",
+    "enabled": false,
+    "id": "ACME",
+    "remote_ids": ["https://idp.acme.example.com"],
+    "links": {
+      "protocols": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols",
+      "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME"
+    }
+  }
+}
+`
+
+// GetMappingOutput provides a GetMapping result.
+const GetMappingOutput = `
+{
+  "mapping": {
+    "id": "ACME",
+    "links": {
+      "self": "http://example.com/identity/v3/OS-FEDERATION/mappings/ACME"
+    },
+    "rules": [
+      {
+        "local": [
+          {
+            "user": {
+              "name": "{0}",
+              "type": "ephemeral"
+            }
+          },
+          {
+            "group": {
+              "id": "0cd5e9"
+            }
+          }
+        ],
+        "remote": [
+          {
+            "type": "UserName"
+          },
+          {
+            "type": "orgPersonType",
+            "not_any_of": ["Contractor", "Guest"]
+          }
+        ]
+      }
+    ],
+    "schema_version": "1.0"
+  }
+}
+`
+
+// ListMappingsOutput provides a single page of Mapping results.
+const ListMappingsOutput = `
+{
+  "links": {
+    "next": null,
+    "previous": null,
+    "self": "http://example.com/identity/v3/OS-FEDERATION/mappings"
+  },
+  "mappings": [
+    {
+      "id": "ACME",
+      "links": {
+        "self": "http://example.com/identity/v3/OS-FEDERATION/mappings/ACME"
+      },
+      "rules": [
+        {
+          "local": [
+            {
+              "user": {
+                "name": "{0}",
+                "type": "ephemeral"
+              }
+            },
+            {
+              "group": {
+                "id": "0cd5e9"
+              }
+            }
+          ],
+          "remote": [
+            {
+              "type": "UserName"
+            },
+            {
+              "type": "orgPersonType",
+              "not_any_of": ["Contractor", "Guest"]
+            }
+          ]
+        }
+      ],
+      "schema_version": "1.0"
+    }
+  ]
+}
+`
+
+// CreateMappingRequest provides the input to a CreateMapping request.
+const CreateMappingRequest = `
+{
+  "mapping": {
+    "rules": [
+      {
+        "local": [
+          {
+            "user": {
+              "name": "{0}",
+              "type": "ephemeral"
+            }
+          },
+          {
+            "group": {
+              "id": "0cd5e9"
+            }
+          }
+        ],
+        "remote": [
+          {
+            "type": "UserName"
+          },
+          {
+            "type": "orgPersonType",
+            "not_any_of": ["Contractor", "Guest"]
+          }
+        ]
+      }
+    ]
+  }
+}
+`
+
+// GetProtocolOutput provides a GetProtocol result.
+const GetProtocolOutput = `
+{
+  "protocol": {
+    "id": "saml2",
+    "links": {
+      "identity_provider": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+      "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2"
+    },
+    "mapping_id": "ACME",
+    "remote_id_attribute": "Shib-Identity-Provider"
+  }
+}
+`
+
+// ListProtocolsOutput provides a single page of Protocol results.
+const ListProtocolsOutput = `
+{
+  "links": {
+    "next": null,
+    "previous": null,
+    "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols"
+  },
+  "protocols": [
+    {
+      "id": "saml2",
+      "links": {
+        "identity_provider": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+        "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2"
+      },
+      "mapping_id": "ACME",
+      "remote_id_attribute": "Shib-Identity-Provider"
+    }
+  ]
+}
+`
+
+// CreateProtocolRequest provides the input to a CreateProtocol request.
+const CreateProtocolRequest = `
+{
+  "protocol": {
+    "mapping_id": "ACME",
+    "remote_id_attribute": "Shib-Identity-Provider"
+  }
+}
+`
+
+// GetServiceProviderOutput provides a GetServiceProvider result.
+const GetServiceProviderOutput = `
+{
+  "service_provider": {
+    "auth_url": "https://sp.example.com:5000/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth",
+    "description": "Remote Service Provider",
+    "enabled": true,
+    "id": "ACME-SP",
+    "links": {
+      "self": "http://example.com/identity/v3/OS-FEDERATION/service_providers/ACME-SP"
+    },
+    "relay_state_prefix": "ss:mem:",
+    "sp_url": "https://sp.example.com:5000/Shibboleth.sso/SAML2/ECP"
+  }
+}
+`
+
+// ListServiceProvidersOutput provides a single page of ServiceProvider
+// results.
+const ListServiceProvidersOutput = `
+{
+  "links": {
+    "next": null,
+    "previous": null,
+    "self": "http://example.com/identity/v3/OS-FEDERATION/service_providers"
+  },
+  "service_providers": [
+    {
+      "auth_url": "https://sp.example.com:5000/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth",
+      "description": "Remote Service Provider",
+      "enabled": true,
+      "id": "ACME-SP",
+      "links": {
+        "self": "http://example.com/identity/v3/OS-FEDERATION/service_providers/ACME-SP"
+      },
+      "relay_state_prefix": "ss:mem:",
+      "sp_url": "https://sp.example.com:5000/Shibboleth.sso/SAML2/ECP"
+    }
+  ]
+}
+`
+
+// CreateServiceProviderRequest provides the input to a CreateServiceProvider
+// request.
+const CreateServiceProviderRequest = `
+{
+  "service_provider": {
+    "auth_url": "https://sp.example.com:5000/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth",
+    "description": "Remote Service Provider",
+    "enabled": true,
+    "sp_url": "https://sp.example.com:5000/Shibboleth.sso/SAML2/ECP"
+  }
+}
+`
+
+// ListAvailableProjectsOutput provides a single page of available Project
+// results.
+const ListAvailableProjectsOutput = `
+{
+  "links": {
+    "next": null,
+    "previous": null,
+    "self": "http://example.com/identity/v3/OS-FEDERATION/projects"
+  },
+  "projects": [
+    {
+      "domain_id": "37ef61",
+      "enabled": true,
+      "id": "12d706",
+      "links": {
+        "self": "http://example.com/identity/v3/projects/12d706"
+      },
+      "name": "a project name"
+    }
+  ]
+}
+`
+
+// ListAvailableDomainsOutput provides a single page of available Domain
+// results.
+const ListAvailableDomainsOutput = `
+{
+  "domains": [
+    {
+      "description": "desc of domain",
+      "enabled": true,
+      "id": "37ef61",
+      "links": {
+        "self": "http://example.com/identity/v3/domains/37ef61"
+      },
+      "name": "my domain"
+    }
+  ],
+  "links": {
+    "next": null,
+    "previous": null,
+    "self": "http://example.com/identity/v3/OS-FEDERATION/domains"
+  }
+}
+`
+
+var identityProviderLinks = map[string]interface{}{
+	"protocols": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols",
+	"self":      "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+}
+
+// ACMEIdentityProvider is the IdentityProvider in the fixtures.
+var ACMEIdentityProvider = federation.IdentityProvider{
+	ID:          "ACME",
+	Description: "Stores ACME identities",
+	DomainID:    "1789d1",
+	Enabled:     true,
+	RemoteIDs:   []string{"https://idp.acme.example.com"},
+	Links:       identityProviderLinks,
+}
+
+// ACMEMappingRules are the rules of the Mapping in the fixtures.
+var ACMEMappingRules = []federation.MappingRule{
+	{
+		Local: []federation.RuleLocal{
+			{
+				User: &federation.RuleUser{
+					Name: "{0}",
+					Type: federation.UserTypeEphemeral,
+				},
+			},
+			{
+				Group: &federation.RuleGroup{
+					ID: "0cd5e9",
+				},
+			},
+		},
+		Remote: []federation.RuleRemote{
+			{
+				Type: "UserName",
+			},
+			{
+				Type:     "orgPersonType",
+				NotAnyOf: []string{"Contractor", "Guest"},
+			},
+		},
+	},
+}
+
+// ACMEMapping is the Mapping in the fixtures.
+var ACMEMapping = federation.Mapping{
+	ID:            "ACME",
+	Rules:         ACMEMappingRules,
+	SchemaVersion: "1.0",
+	Links: map[string]interface{}{
+		"self": "http://example.com/identity/v3/OS-FEDERATION/mappings/ACME",
+	},
+}
+
+// SAML2Protocol is the Protocol in the fixtures.
+var SAML2Protocol = federation.Protocol{
+	ID:                "saml2",
+	MappingID:         "ACME",
+	RemoteIDAttribute: "Shib-Identity-Provider",
+	Links: map[string]interface{}{
+		"identity_provider": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+		"self":              "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2",
+	},
+}
+
+// ACMEServiceProvider is the ServiceProvider in the fixtures.
+var ACMEServiceProvider = federation.ServiceProvider{
+	ID:               "ACME-SP",
+	AuthURL:          "https://sp.example.com:5000/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth",
+	SPURL:            "https://sp.example.com:5000/Shibboleth.sso/SAML2/ECP",
+	Description:      "Remote Service Provider",
+	Enabled:          true,
+	RelayStatePrefix: "ss:mem:",
+	Links: map[string]interface{}{
+		"self": "http://example.com/identity/v3/OS-FEDERATION/service_providers/ACME-SP",
+	},
+}
+
+// handleGet registers a handler at path which checks the method and the
+// token and responds with output.
+func handleGet(t *testing.T, path, output string) {
+	th.Mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, output)
+	})
+}
+
+// HandleListIdentityProvidersSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers` on the test handler mux that responds
+// with a list of identity providers.
+func HandleListIdentityProvidersSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/OS-FEDERATION/identity_providers", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestFormValues(t, r, map[string]string{"enabled": "true"})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListIdentityProvidersOutput)
+	})
+}
+
+// HandleIdentityProviderSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME` on the test handler mux that
+// handles the creation, retrieval, update and deletion of an identity provider.
+func HandleIdentityProviderSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case "PUT":
+			th.TestJSONRequest(t, r, CreateIdentityProviderRequest)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, GetIdentityProviderOutput)
+		case "GET":
+			fmt.Fprint(w, GetIdentityProviderOutput)
+		case "PATCH":
+			th.TestJSONRequest(t, r, UpdateIdentityProviderRequest)
+			fmt.Fprint(w, UpdateIdentityProviderOutput)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+}
+
+// HandleMappingsSuccessfully creates HTTP handlers at `/OS-FEDERATION/mappings`
+// on the test handler mux that handle the mappings.
+func HandleMappingsSuccessfully(t *testing.T) {
+	handleGet(t, "/OS-FEDERATION/mappings", ListMappingsOutput)
+	th.Mux.HandleFunc("/OS-FEDERATION/mappings/ACME", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case "PUT":
+			th.TestJSONRequest(t, r, CreateMappingRequest)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, GetMappingOutput)
+		case "GET":
+			fmt.Fprint(w, GetMappingOutput)
+		case "PATCH":
+			th.TestJSONRequest(t, r, CreateMappingRequest)
+			fmt.Fprint(w, GetMappingOutput)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+}
+
+// HandleProtocolsSuccessfully creates HTTP handlers at
+// `/OS-FEDERATION/identity_providers/ACME/protocols` on the test handler mux
+// that handle the protocols.
+func HandleProtocolsSuccessfully(t *testing.T) {
+	handleGet(t, "/OS-FEDERATION/identity_providers/ACME/protocols", ListProtocolsOutput)
+	th.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME/protocols/saml2", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case "PUT":
+			th.TestJSONRequest(t, r, CreateProtocolRequest)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, GetProtocolOutput)
+		case "GET":
+			fmt.Fprint(w, GetProtocolOutput)
+		case "PATCH":
+			th.TestJSONRequest(t, r, `{"protocol": {"mapping_id": "ACME"}}`)
+			fmt.Fprint(w, GetProtocolOutput)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+}
+
+// HandleServiceProvidersSuccessfully creates HTTP handlers at
+// `/OS-FEDERATION/service_providers` on the test handler mux that handle the
+// service providers.
+func HandleServiceProvidersSuccessfully(t *testing.T) {
+	handleGet(t, "/OS-FEDERATION/service_providers", ListServiceProvidersOutput)
+	th.Mux.HandleFunc("/OS-FEDERATION/service_providers/ACME-SP", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case "PUT":
+			th.TestJSONRequest(t, r, CreateServiceProviderRequest)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, GetServiceProviderOutput)
+		case "GET":
+			fmt.Fprint(w, GetServiceProviderOutput)
+		case "PATCH":
+			th.TestJSONRequest(t, r, `{"service_provider": {"relay_state_prefix": "ss:mem:"}}`)
+			fmt.Fprint(w, GetServiceProviderOutput)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+}
+
+// HandleListAvailableSuccessfully creates HTTP handlers at
+// `/OS-FEDERATION/projects` and `/OS-FEDERATION/domains` on the test handler
+// mux that respond with the projects and domains available to a federated
+// user.
+func HandleListAvailableSuccessfully(t *testing.T) {
+	handleGet(t, "/OS-FEDERATION/projects", ListAvailableProjectsOutput)
+	handleGet(t, "/OS-FEDERATION/domains", ListAvailableDomainsOutput)
+}
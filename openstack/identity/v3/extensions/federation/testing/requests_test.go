@@ -0,0 +1,213 @@
+package testing
+
+import (
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/domains"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/federation"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/pagination"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+func TestListIdentityProviders(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleListIdentityProvidersSuccessfully(t)
+
+	count := 0
+	opts := federation.ListIdentityProvidersOpts{Enabled: gophercloud.Enabled}
+	err := federation.ListIdentityProviders(client.ServiceClient(), opts).EachPage(func(page pagination.Page) (bool, error) {
+		count++
+
+		actual, err := federation.ExtractIdentityProviders(page)
+		th.AssertNoErr(t, err)
+
+		th.CheckDeepEquals(t, []federation.IdentityProvider{ACMEIdentityProvider}, actual)
+
+		return true, nil
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, count, 1)
+}
+
+func TestIdentityProviderCRUD(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleIdentityProviderSuccessfully(t)
+
+	createOpts := federation.CreateIdentityProviderOpts{
+		Description: "Stores ACME identities",
+		DomainID:    "1789d1",
+		Enabled:     gophercloud.Enabled,
+		RemoteIDs:   []string{"https://idp.acme.example.com"},
+	}
+	actual, err := federation.CreateIdentityProvider(client.ServiceClient(), "ACME", createOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ACMEIdentityProvider, *actual)
+
+	actual, err = federation.GetIdentityProvider(client.ServiceClient(), "ACME").Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ACMEIdentityProvider, *actual)
+
+	updateOpts := federation.UpdateIdentityProviderOpts{Enabled: gophercloud.Disabled}
+	actual, err = federation.UpdateIdentityProvider(client.ServiceClient(), "ACME", updateOpts).Extract()
+	th.AssertNoErr(t, err)
+	expected := ACMEIdentityProvider
+	expected.Enabled = false
+	th.CheckDeepEquals(t, expected, *actual)
+
+	err = federation.DeleteIdentityProvider(client.ServiceClient(), "ACME").ExtractErr()
+	th.AssertNoErr(t, err)
+}
+
+func TestMappingCRUD(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleMappingsSuccessfully(t)
+
+	count := 0
+	err := federation.ListMappings(client.ServiceClient()).EachPage(func(page pagination.Page) (bool, error) {
+		count++
+
+		actual, err := federation.ExtractMappings(page)
+		th.AssertNoErr(t, err)
+
+		th.CheckDeepEquals(t, []federation.Mapping{ACMEMapping}, actual)
+
+		return true, nil
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, count, 1)
+
+	createOpts := federation.CreateMappingOpts{Rules: ACMEMappingRules}
+	actual, err := federation.CreateMapping(client.ServiceClient(), "ACME", createOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ACMEMapping, *actual)
+
+	actual, err = federation.GetMapping(client.ServiceClient(), "ACME").Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ACMEMapping, *actual)
+
+	updateOpts := federation.UpdateMappingOpts{Rules: ACMEMappingRules}
+	actual, err = federation.UpdateMapping(client.ServiceClient(), "ACME", updateOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ACMEMapping, *actual)
+
+	err = federation.DeleteMapping(client.ServiceClient(), "ACME").ExtractErr()
+	th.AssertNoErr(t, err)
+}
+
+func TestCreateMappingMissingRules(t *testing.T) {
+	err := federation.CreateMapping(client.ServiceClient(), "ACME", federation.CreateMappingOpts{}).Err
+	if _, ok := err.(gophercloud.ErrMissingInput); !ok {
+		t.Fatalf("expected an ErrMissingInput, got %T: %v", err, err)
+	}
+}
+
+func TestProtocolCRUD(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleProtocolsSuccessfully(t)
+
+	count := 0
+	err := federation.ListProtocols(client.ServiceClient(), "ACME").EachPage(func(page pagination.Page) (bool, error) {
+		count++
+
+		actual, err := federation.ExtractProtocols(page)
+		th.AssertNoErr(t, err)
+
+		th.CheckDeepEquals(t, []federation.Protocol{SAML2Protocol}, actual)
+
+		return true, nil
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, count, 1)
+
+	createOpts := federation.CreateProtocolOpts{
+		MappingID:         "ACME",
+		RemoteIDAttribute: "Shib-Identity-Provider",
+	}
+	actual, err := federation.CreateProtocol(client.ServiceClient(), "ACME", "saml2", createOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, SAML2Protocol, *actual)
+
+	actual, err = federation.GetProtocol(client.ServiceClient(), "ACME", "saml2").Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, SAML2Protocol, *actual)
+
+	updateOpts := federation.UpdateProtocolOpts{MappingID: "ACME"}
+	actual, err = federation.UpdateProtocol(client.ServiceClient(), "ACME", "saml2", updateOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, SAML2Protocol, *actual)
+
+	err = federation.DeleteProtocol(client.ServiceClient(), "ACME", "saml2").ExtractErr()
+	th.AssertNoErr(t, err)
+}
+
+func TestServiceProviderCRUD(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleServiceProvidersSuccessfully(t)
+
+	count := 0
+	err := federation.ListServiceProviders(client.ServiceClient()).EachPage(func(page pagination.Page) (bool, error) {
+		count++
+
+		actual, err := federation.ExtractServiceProviders(page)
+		th.AssertNoErr(t, err)
+
+		th.CheckDeepEquals(t, []federation.ServiceProvider{ACMEServiceProvider}, actual)
+
+		return true, nil
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, count, 1)
+
+	createOpts := federation.CreateServiceProviderOpts{
+		AuthURL:     ACMEServiceProvider.AuthURL,
+		SPURL:       ACMEServiceProvider.SPURL,
+		Description: "Remote Service Provider",
+		Enabled:     gophercloud.Enabled,
+	}
+	actual, err := federation.CreateServiceProvider(client.ServiceClient(), "ACME-SP", createOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ACMEServiceProvider, *actual)
+
+	actual, err = federation.GetServiceProvider(client.ServiceClient(), "ACME-SP").Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ACMEServiceProvider, *actual)
+
+	prefix := "ss:mem:"
+	updateOpts := federation.UpdateServiceProviderOpts{RelayStatePrefix: &prefix}
+	actual, err = federation.UpdateServiceProvider(client.ServiceClient(), "ACME-SP", updateOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ACMEServiceProvider, *actual)
+
+	err = federation.DeleteServiceProvider(client.ServiceClient(), "ACME-SP").ExtractErr()
+	th.AssertNoErr(t, err)
+}
+
+func TestListAvailable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleListAvailableSuccessfully(t)
+
+	allPages, err := federation.ListAvailableProjects(client.ServiceClient()).AllPages()
+	th.AssertNoErr(t, err)
+	allProjects, err := projects.ExtractProjects(allPages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(allProjects))
+	th.CheckEquals(t, "12d706", allProjects[0].ID)
+	th.CheckEquals(t, "a project name", allProjects[0].Name)
+
+	allPages, err = federation.ListAvailableDomains(client.ServiceClient()).AllPages()
+	th.AssertNoErr(t, err)
+	allDomains, err := domains.ExtractDomains(allPages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(allDomains))
+	th.CheckEquals(t, "37ef61", allDomains[0].ID)
+	th.CheckEquals(t, "my domain", allDomains[0].Name)
+}
@@ -0,0 +1,45 @@
+package federation
+
+import "github.com/gophercloud/gophercloud"
+
+const rootPath = "OS-FEDERATION"
+
+func identityProvidersURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath, "identity_providers")
+}
+
+func identityProviderURL(client *gophercloud.ServiceClient, id string) string {
+	return client.ServiceURL(rootPath, "identity_providers", id)
+}
+
+func mappingsURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath, "mappings")
+}
+
+func mappingURL(client *gophercloud.ServiceClient, id string) string {
+	return client.ServiceURL(rootPath, "mappings", id)
+}
+
+func protocolsURL(client *gophercloud.ServiceClient, idpID string) string {
+	return client.ServiceURL(rootPath, "identity_providers", idpID, "protocols")
+}
+
+func protocolURL(client *gophercloud.ServiceClient, idpID, id string) string {
+	return client.ServiceURL(rootPath, "identity_providers", idpID, "protocols", id)
+}
+
+func serviceProvidersURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath, "service_providers")
+}
+
+func serviceProviderURL(client *gophercloud.ServiceClient, id string) string {
+	return client.ServiceURL(rootPath, "service_providers", id)
+}
+
+func availableProjectsURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath, "projects")
+}
+
+func availableDomainsURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath, "domains")
+}
@@ -0,0 +1,34 @@
+/*
+Package k2k provides Keystone to Keystone federated authentication: a token of
+the local Keystone, acting as the identity provider, is exchanged for a SAML2
+assertion, which a Keystone service provider accepts through the SAML2 ECP
+profile.
+
+Example to Authenticate against a Service Provider
+
+	client, err := openstack.NewClient("https://sp.example.com:5000/v3")
+	if err != nil {
+		panic(err)
+	}
+
+	authOptions := &k2k.AuthOptions{
+		LocalIdentityEndpoint: "https://keystone.example.com:5000/v3",
+		LocalAuthOptions: &tokens.AuthOptions{
+			Username:   "alice",
+			Password:   "wonderland",
+			DomainName: "Default",
+		},
+		ServiceProvider: "mysp",
+		Scope: tokens.Scope{
+			ProjectName: "myproject",
+			DomainName:  "federated_domain",
+		},
+		AllowReauth: true,
+	}
+
+	err = openstack.AuthenticateV3(client, authOptions, gophercloud.EndpointOpts{})
+	if err != nil {
+		panic(err)
+	}
+*/
+package k2k
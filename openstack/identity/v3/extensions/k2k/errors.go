@@ -0,0 +1,18 @@
+package k2k
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// ErrServiceProviderNotFound is the error when the service provider isn't
+// listed in the token of the local Keystone.
+type ErrServiceProviderNotFound struct {
+	gophercloud.BaseError
+	ServiceProvider string
+}
+
+func (e ErrServiceProviderNotFound) Error() string {
+	return fmt.Sprintf("Service provider %s not found in the token of the local Keystone", e.ServiceProvider)
+}
@@ -0,0 +1,243 @@
+package k2k
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+)
+
+// AuthOptions authenticates against a Keystone service provider with the
+// credentials of a user of the local Keystone, which acts as the identity
+// provider: a token of the local Keystone is exchanged for a SAML2 assertion,
+// which the service provider accepts through the ECP profile.
+type AuthOptions struct {
+	// LocalIdentityEndpoint is the versioned Identity v3 endpoint of the
+	// local Keystone, e.g. https://keystone.example.com/v3/.
+	LocalIdentityEndpoint string `required:"true"`
+
+	// LocalAuthOptions authenticate against the local Keystone. It is
+	// usually a *gophercloud.AuthOptions or a *tokens.AuthOptions.
+	LocalAuthOptions tokens.AuthOptionsBuilder `required:"true"`
+
+	// ServiceProvider is the ID of the service provider in the local
+	// Keystone.
+	ServiceProvider string `required:"true"`
+
+	// Scope is the scope of the token of the service provider. The token is
+	// unscoped when empty.
+	Scope tokens.Scope
+
+	// AllowReauth allows Gophercloud to authenticate again with the same
+	// options when the token expires.
+	AllowReauth bool
+}
+
+// ToTokenV3CreateMap is a dummy method to satisfy tokens.AuthOptionsBuilder
+// interface, the token is created by Create.
+func (opts *AuthOptions) ToTokenV3CreateMap(map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+// ToTokenV3HeadersMap is a dummy method to satisfy tokens.AuthOptionsBuilder
+// interface.
+func (opts *AuthOptions) ToTokenV3HeadersMap(map[string]interface{}) (map[string]string, error) {
+	return nil, nil
+}
+
+// ToTokenV3ScopeMap builds the scope of the token of the service provider.
+func (opts *AuthOptions) ToTokenV3ScopeMap() (map[string]interface{}, error) {
+	if opts.Scope == (tokens.Scope{}) {
+		return nil, nil
+	}
+	o := tokens.AuthOptions{Scope: opts.Scope}
+	return o.ToTokenV3ScopeMap()
+}
+
+// CanReauth implements tokens.AuthOptionsBuilder.
+func (opts *AuthOptions) CanReauth() bool {
+	return opts.AllowReauth
+}
+
+// ToK2KAssertionMap builds the request body of the SAML2 ECP assertion of a
+// token of the local Keystone.
+func (opts *AuthOptions) ToK2KAssertionMap(localToken string) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"token"},
+				"token":   map[string]interface{}{"id": localToken},
+			},
+			"scope": map[string]interface{}{
+				"service_provider": map[string]interface{}{"id": opts.ServiceProvider},
+			},
+		},
+	}, nil
+}
+
+// Create authenticates against the local Keystone, requests a SAML2 ECP
+// assertion for the service provider, exchanges it for an unscoped token of the
+// service provider, whose Identity v3 service client is c, and scopes the token
+// if a scope was requested.
+func Create(c *gophercloud.ServiceClient, opts *AuthOptions) (r tokens.CreateResult) {
+	if _, err := gophercloud.BuildRequestBody(opts, ""); err != nil {
+		r.Err = err
+		return
+	}
+	scope, err := opts.ToTokenV3ScopeMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	// the local Keystone is reached through a client sharing the transport,
+	// but not the token, of the client of the service provider
+	local := &gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{
+			HTTPClient: c.HTTPClient,
+			UserAgent:  c.UserAgent,
+			Context:    c.Context,
+			Logger:     c.Logger,
+			LogBodies:  c.LogBodies,
+		},
+		Endpoint: gophercloud.NormalizeURL(opts.LocalIdentityEndpoint),
+	}
+	localResult := tokens.Create(local, opts.LocalAuthOptions)
+	localToken, err := localResult.ExtractTokenID()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	spURL, err := serviceProviderURL(localResult, opts.ServiceProvider)
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	assertion, err := requestAssertion(local, opts, localToken)
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	r = exchangeAssertion(c, spURL, assertion)
+	if r.Err != nil || scope == nil {
+		return
+	}
+
+	unscoped, err := r.ExtractTokenID()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	var scoped tokens.CreateResult
+	resp, err := c.Post(tokenURL(c), map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"token"},
+				"token":   map[string]interface{}{"id": unscoped},
+			},
+			"scope": scope,
+		},
+	}, &scoped.Body, &gophercloud.RequestOpts{
+		MoreHeaders: map[string]string{"X-Auth-Token": ""},
+	})
+	_, scoped.Header, scoped.Err = gophercloud.ParseResponse(resp, err)
+	return scoped
+}
+
+// serviceProviderURL returns the ECP endpoint of the service provider listed
+// in the local token.
+func serviceProviderURL(r tokens.CreateResult, id string) (string, error) {
+	var s struct {
+		ServiceProviders []struct {
+			ID      string `json:"id"`
+			AuthURL string `json:"auth_url"`
+			SPURL   string `json:"sp_url"`
+		} `json:"service_providers"`
+	}
+	if err := r.ExtractInto(&s); err != nil {
+		return "", err
+	}
+	for _, sp := range s.ServiceProviders {
+		if sp.ID == id {
+			return sp.SPURL, nil
+		}
+	}
+	return "", ErrServiceProviderNotFound{ServiceProvider: id}
+}
+
+// requestAssertion returns the SAML2 ECP assertion of the local token for the
+// service provider.
+func requestAssertion(local *gophercloud.ServiceClient, opts *AuthOptions, localToken string) ([]byte, error) {
+	b, err := opts.ToK2KAssertionMap(localToken)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := local.Post(ecpAssertionURL(local), b, nil, &gophercloud.RequestOpts{
+		MoreHeaders:      map[string]string{"X-Auth-Token": localToken},
+		OkCodes:          []int{200, 201},
+		KeepResponseBody: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+// exchangeAssertion posts the assertion to the ECP endpoint of the service
+// provider, which redirects to its federated auth URL with a session cookie.
+// The redirect is followed, and the response holds the unscoped token.
+func exchangeAssertion(c *gophercloud.ServiceClient, spURL string, assertion []byte) (r tokens.CreateResult) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		r.Err = err
+		return
+	}
+	client := c.HTTPClient
+	client.Jar = jar
+
+	req, err := http.NewRequest("POST", spURL, bytes.NewReader(assertion))
+	if err != nil {
+		r.Err = err
+		return
+	}
+	if c.Context != nil {
+		req = req.WithContext(c.Context)
+	}
+	req.Header.Set("Content-Type", "application/vnd.paos+xml")
+	req.Header.Set("User-Agent", c.UserAgent.Join())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		r.Err = err
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		r.Err = err
+		return
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		r.Err = gophercloud.ErrUnexpectedResponseCode{
+			URL:            resp.Request.URL.String(),
+			Method:         resp.Request.Method,
+			Expected:       []int{200, 201},
+			Actual:         resp.StatusCode,
+			Body:           body,
+			ResponseHeader: resp.Header,
+		}
+		return
+	}
+
+	r.Header = resp.Header
+	r.Err = json.Unmarshal(body, &r.Body)
+	return
+}
@@ -0,0 +1,2 @@
+// k2k unit tests
+package testing
@@ -0,0 +1,189 @@
+package testing
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/k2k"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	th "github.com/gophercloud/gophercloud/testhelper"
+)
+
+const assertion = `<?xml version="1.0" encoding="UTF-8"?><soap11:Envelope xmlns:soap11="http://schemas.xmlsoap.org/soap/envelope/"/>`
+
+const tokenOutput = `
+{
+	"token": {
+		"methods": ["%s"],
+		"expires_at": "2017-06-03T02:19:49.000000Z",
+		"catalog": [],
+		"service_providers": [
+			{
+				"id": "mysp",
+				"auth_url": "%ssp/v3/OS-FEDERATION/identity_providers/myidp/protocols/saml2/auth",
+				"sp_url": "%ssp/Shibboleth.sso/SAML2/ECP"
+			}
+		]
+	}
+}
+`
+
+// handleK2K registers a fake local Keystone under /local/v3/ and a fake
+// service provider Keystone under /sp/v3/. It returns the number of tokens
+// created by the local Keystone.
+func handleK2K(t *testing.T) *int32 {
+	var localTokens int32
+
+	th.Mux.HandleFunc("/local/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, `{
+			"auth": {
+				"identity": {
+					"methods": ["password"],
+					"password": {"user": {"name": "alice", "password": "wonderland", "domain": {"name": "Default"}}}
+				}
+			}
+		}`)
+		n := atomic.AddInt32(&localTokens, 1)
+		w.Header().Set("X-Subject-Token", fmt.Sprintf("local-%d", n))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, tokenOutput, "password", th.Endpoint(), th.Endpoint())
+	})
+
+	th.Mux.HandleFunc("/local/v3/auth/OS-FEDERATION/saml2/ecp", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		token := r.Header.Get("X-Auth-Token")
+		th.TestJSONRequest(t, r, fmt.Sprintf(`{
+			"auth": {
+				"identity": {
+					"methods": ["token"],
+					"token": {"id": "%s"}
+				},
+				"scope": {"service_provider": {"id": "mysp"}}
+			}
+		}`, token))
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, assertion)
+	})
+
+	th.Mux.HandleFunc("/sp/Shibboleth.sso/SAML2/ECP", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Content-Type", "application/vnd.paos+xml")
+		b, err := ioutil.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, assertion, string(b))
+
+		http.SetCookie(w, &http.Cookie{Name: "_shibsession_1", Value: "session", Path: "/"})
+		http.Redirect(w, r, "/sp/v3/OS-FEDERATION/identity_providers/myidp/protocols/saml2/auth", http.StatusFound)
+	})
+
+	th.Mux.HandleFunc("/sp/v3/OS-FEDERATION/identity_providers/myidp/protocols/saml2/auth", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		cookie, err := r.Cookie("_shibsession_1")
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, "session", cookie.Value)
+
+		w.Header().Set("X-Subject-Token", "sp-unscoped")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, tokenOutput, "saml2", th.Endpoint(), th.Endpoint())
+	})
+
+	th.Mux.HandleFunc("/sp/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, `{
+			"auth": {
+				"identity": {
+					"methods": ["token"],
+					"token": {"id": "sp-unscoped"}
+				},
+				"scope": {"project": {"id": "myproject"}}
+			}
+		}`)
+		w.Header().Set("X-Subject-Token", fmt.Sprintf("sp-scoped-%d", atomic.LoadInt32(&localTokens)))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, tokenOutput, "token", th.Endpoint(), th.Endpoint())
+	})
+
+	return &localTokens
+}
+
+func authOptions() *k2k.AuthOptions {
+	return &k2k.AuthOptions{
+		LocalIdentityEndpoint: th.Endpoint() + "local/v3",
+		LocalAuthOptions: &tokens.AuthOptions{
+			Username:   "alice",
+			Password:   "wonderland",
+			DomainName: "Default",
+		},
+		ServiceProvider: "mysp",
+		Scope:           tokens.Scope{ProjectID: "myproject"},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	handleK2K(t)
+
+	client := &gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Endpoint:       th.Endpoint() + "sp/v3/",
+	}
+	tokenID, err := k2k.Create(client, authOptions()).ExtractTokenID()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "sp-scoped-1", tokenID)
+}
+
+func TestCreateUnknownServiceProvider(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	handleK2K(t)
+
+	client := &gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Endpoint:       th.Endpoint() + "sp/v3/",
+	}
+	opts := authOptions()
+	opts.ServiceProvider = "unknown"
+	err := k2k.Create(client, opts).Err
+	if _, ok := err.(k2k.ErrServiceProviderNotFound); !ok {
+		t.Fatalf("expected an ErrServiceProviderNotFound, got %T: %v", err, err)
+	}
+}
+
+func TestAuthenticateV3Reauth(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	localTokens := handleK2K(t)
+
+	th.Mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "sp-scoped-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	provider, err := openstack.NewClient(th.Endpoint() + "sp/")
+	th.AssertNoErr(t, err)
+	opts := authOptions()
+	opts.AllowReauth = true
+	th.AssertNoErr(t, openstack.AuthenticateV3(provider, opts, gophercloud.EndpointOpts{}))
+	th.AssertEquals(t, "sp-scoped-1", provider.Token())
+
+	_, err = provider.Request("GET", th.Endpoint()+"resource", &gophercloud.RequestOpts{
+		OkCodes: []int{204},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "sp-scoped-2", provider.Token())
+	th.AssertEquals(t, int32(2), atomic.LoadInt32(localTokens))
+}
@@ -0,0 +1,11 @@
+package k2k
+
+import "github.com/gophercloud/gophercloud"
+
+func ecpAssertionURL(c *gophercloud.ServiceClient) string {
+	return c.ServiceURL("auth", "OS-FEDERATION", "saml2", "ecp")
+}
+
+func tokenURL(c *gophercloud.ServiceClient) string {
+	return c.ServiceURL("auth", "tokens")
+}
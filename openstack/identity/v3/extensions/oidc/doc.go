@@ -0,0 +1,51 @@
+/*
+Package oidc provides authentication against Keystone with the OpenID Connect
+federation protocol: an access token obtained from an OpenID Connect provider
+is exchanged for a Keystone token through the
+/OS-FEDERATION/identity_providers/{idp}/protocols/{protocol}/auth URL.
+
+The password, client credentials and access token flows match the
+v3oidcpassword, v3oidcclientcredentials and v3oidcaccesstoken auth types of
+keystoneauth.
+
+Example to Authenticate with the Password Flow
+
+	client, err := openstack.NewClient("https://keystone.example.com/v3")
+	if err != nil {
+		panic(err)
+	}
+
+	authOptions := &oidc.PasswordAuthOptions{
+		IdentityProvider:  "myidp",
+		Protocol:          "openid",
+		ClientID:          "myclient",
+		ClientSecret:      "s3cr3t",
+		DiscoveryEndpoint: "https://idp.example.com/.well-known/openid-configuration",
+		Username:          "alice",
+		Password:          "wonderland",
+		Scope: tokens.Scope{
+			ProjectName: "myproject",
+			DomainName:  "Default",
+		},
+		AllowReauth: true,
+	}
+
+	err = openstack.AuthenticateV3(client, authOptions, gophercloud.EndpointOpts{})
+	if err != nil {
+		panic(err)
+	}
+
+Example to Create a Token from an Access Token
+
+	authOptions := &oidc.AccessTokenAuthOptions{
+		IdentityProvider: "myidp",
+		Protocol:         "openid",
+		AccessToken:      accessToken,
+	}
+
+	token, err := oidc.Create(identityClient, authOptions).ExtractToken()
+	if err != nil {
+		panic(err)
+	}
+*/
+package oidc
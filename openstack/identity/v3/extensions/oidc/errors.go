@@ -0,0 +1,29 @@
+package oidc
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// ErrTokenEndpointNotFound is the error when the discovery document of the
+// OpenID Connect provider has no token endpoint.
+type ErrTokenEndpointNotFound struct {
+	gophercloud.BaseError
+	DiscoveryEndpoint string
+}
+
+func (e ErrTokenEndpointNotFound) Error() string {
+	return fmt.Sprintf("No token_endpoint found in the OpenID Connect discovery document %s", e.DiscoveryEndpoint)
+}
+
+// ErrTokenNotFound is the error when the response of the OpenID Connect
+// provider has no token of the requested type.
+type ErrTokenNotFound struct {
+	gophercloud.BaseError
+	TokenType AccessTokenType
+}
+
+func (e ErrTokenNotFound) Error() string {
+	return fmt.Sprintf("No %s found in the response of the OpenID Connect provider", e.TokenType)
+}
@@ -0,0 +1,399 @@
+package oidc
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+)
+
+// AccessTokenType is the token of the OpenID Connect provider response which
+// is presented to Keystone.
+type AccessTokenType string
+
+const (
+	// AccessToken presents the OAuth 2.0 access token. This is the default.
+	AccessToken AccessTokenType = "access_token"
+
+	// IDToken presents the OpenID Connect ID token.
+	IDToken AccessTokenType = "id_token"
+)
+
+// AuthOptionsBuilder is implemented by the options of the OpenID Connect
+// authentication flows. They obtain an access token, which is exchanged for an
+// unscoped Keystone token through a federation protocol of an identity
+// provider, and which is then scoped.
+type AuthOptionsBuilder interface {
+	tokens.AuthOptionsBuilder
+
+	// ToOIDCAccessToken returns the token to present to Keystone. The client
+	// sends the requests to the OpenID Connect provider, if any.
+	ToOIDCAccessToken(client *gophercloud.ProviderClient) (string, error)
+
+	// ToOIDCProtocol returns the identity provider and the protocol which
+	// accept the token.
+	ToOIDCProtocol() (identityProvider, protocol string)
+}
+
+// PasswordAuthOptions authenticates with the username and password of a user
+// of the OpenID Connect provider, with the resource owner password credentials
+// grant. It matches the v3oidcpassword auth type of clouds.yaml.
+type PasswordAuthOptions struct {
+	// IdentityProvider is the ID of the identity provider in Keystone.
+	IdentityProvider string `required:"true"`
+
+	// Protocol is the federation protocol of the identity provider, usually
+	// "openid".
+	Protocol string `required:"true"`
+
+	// ClientID and ClientSecret authenticate the client to the OpenID Connect
+	// provider.
+	ClientID     string `required:"true"`
+	ClientSecret string
+
+	// AccessTokenEndpoint is the token endpoint of the OpenID Connect
+	// provider. When not set, it is discovered from DiscoveryEndpoint.
+	AccessTokenEndpoint string
+
+	// DiscoveryEndpoint is the URL of the OpenID Connect discovery document,
+	// i.e. https://idp.example.com/.well-known/openid-configuration.
+	DiscoveryEndpoint string
+
+	// Username and Password are the credentials of the user in the OpenID
+	// Connect provider.
+	Username string `required:"true"`
+	Password string `required:"true"`
+
+	// OpenIDScope are the scopes requested to the OpenID Connect provider.
+	// "openid" is always requested.
+	OpenIDScope []string
+
+	// AccessTokenType is the token presented to Keystone. Defaults to
+	// AccessToken.
+	AccessTokenType AccessTokenType
+
+	// Scope is the scope of the Keystone token. The token is unscoped when
+	// empty.
+	Scope tokens.Scope
+
+	// AllowReauth allows Gophercloud to authenticate again with the same
+	// options when the token expires.
+	AllowReauth bool
+}
+
+// ToOIDCAccessToken implements AuthOptionsBuilder.
+func (opts *PasswordAuthOptions) ToOIDCAccessToken(client *gophercloud.ProviderClient) (string, error) {
+	if err := checkRequired(opts); err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {opts.Username},
+		"password":   {opts.Password},
+	}
+	return requestAccessToken(client, opts.AccessTokenEndpoint, opts.DiscoveryEndpoint,
+		opts.ClientID, opts.ClientSecret, opts.OpenIDScope, opts.AccessTokenType, form)
+}
+
+// ToOIDCProtocol implements AuthOptionsBuilder.
+func (opts *PasswordAuthOptions) ToOIDCProtocol() (string, string) {
+	return opts.IdentityProvider, opts.Protocol
+}
+
+// ToTokenV3CreateMap is a dummy method to satisfy tokens.AuthOptionsBuilder
+// interface, the token is created by Create.
+func (opts *PasswordAuthOptions) ToTokenV3CreateMap(map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+// ToTokenV3HeadersMap is a dummy method to satisfy tokens.AuthOptionsBuilder
+// interface.
+func (opts *PasswordAuthOptions) ToTokenV3HeadersMap(map[string]interface{}) (map[string]string, error) {
+	return nil, nil
+}
+
+// ToTokenV3ScopeMap builds the scope of the Keystone token.
+func (opts *PasswordAuthOptions) ToTokenV3ScopeMap() (map[string]interface{}, error) {
+	return scopeMap(opts.Scope)
+}
+
+// CanReauth implements tokens.AuthOptionsBuilder.
+func (opts *PasswordAuthOptions) CanReauth() bool {
+	return opts.AllowReauth
+}
+
+// ClientCredentialsAuthOptions authenticates as the client itself, with the
+// client credentials grant. It matches the v3oidcclientcredentials auth type
+// of clouds.yaml.
+type ClientCredentialsAuthOptions struct {
+	// IdentityProvider is the ID of the identity provider in Keystone.
+	IdentityProvider string `required:"true"`
+
+	// Protocol is the federation protocol of the identity provider, usually
+	// "openid".
+	Protocol string `required:"true"`
+
+	// ClientID and ClientSecret authenticate the client to the OpenID Connect
+	// provider.
+	ClientID     string `required:"true"`
+	ClientSecret string `required:"true"`
+
+	// AccessTokenEndpoint is the token endpoint of the OpenID Connect
+	// provider. When not set, it is discovered from DiscoveryEndpoint.
+	AccessTokenEndpoint string
+
+	// DiscoveryEndpoint is the URL of the OpenID Connect discovery document,
+	// i.e. https://idp.example.com/.well-known/openid-configuration.
+	DiscoveryEndpoint string
+
+	// OpenIDScope are the scopes requested to the OpenID Connect provider.
+	// "openid" is always requested.
+	OpenIDScope []string
+
+	// AccessTokenType is the token presented to Keystone. Defaults to
+	// AccessToken.
+	AccessTokenType AccessTokenType
+
+	// Scope is the scope of the Keystone token. The token is unscoped when
+	// empty.
+	Scope tokens.Scope
+
+	// AllowReauth allows Gophercloud to authenticate again with the same
+	// options when the token expires.
+	AllowReauth bool
+}
+
+// ToOIDCAccessToken implements AuthOptionsBuilder.
+func (opts *ClientCredentialsAuthOptions) ToOIDCAccessToken(client *gophercloud.ProviderClient) (string, error) {
+	if err := checkRequired(opts); err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+	}
+	return requestAccessToken(client, opts.AccessTokenEndpoint, opts.DiscoveryEndpoint,
+		opts.ClientID, opts.ClientSecret, opts.OpenIDScope, opts.AccessTokenType, form)
+}
+
+// ToOIDCProtocol implements AuthOptionsBuilder.
+func (opts *ClientCredentialsAuthOptions) ToOIDCProtocol() (string, string) {
+	return opts.IdentityProvider, opts.Protocol
+}
+
+// ToTokenV3CreateMap is a dummy method to satisfy tokens.AuthOptionsBuilder
+// interface, the token is created by Create.
+func (opts *ClientCredentialsAuthOptions) ToTokenV3CreateMap(map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+// ToTokenV3HeadersMap is a dummy method to satisfy tokens.AuthOptionsBuilder
+// interface.
+func (opts *ClientCredentialsAuthOptions) ToTokenV3HeadersMap(map[string]interface{}) (map[string]string, error) {
+	return nil, nil
+}
+
+// ToTokenV3ScopeMap builds the scope of the Keystone token.
+func (opts *ClientCredentialsAuthOptions) ToTokenV3ScopeMap() (map[string]interface{}, error) {
+	return scopeMap(opts.Scope)
+}
+
+// CanReauth implements tokens.AuthOptionsBuilder.
+func (opts *ClientCredentialsAuthOptions) CanReauth() bool {
+	return opts.AllowReauth
+}
+
+// AccessTokenAuthOptions authenticates with an access token obtained
+// beforehand from the OpenID Connect provider. It matches the
+// v3oidcaccesstoken auth type of clouds.yaml. Since the access token itself
+// expires, reauthentication only works as long as it's valid.
+type AccessTokenAuthOptions struct {
+	// IdentityProvider is the ID of the identity provider in Keystone.
+	IdentityProvider string `required:"true"`
+
+	// Protocol is the federation protocol of the identity provider, usually
+	// "openid".
+	Protocol string `required:"true"`
+
+	// AccessToken is the token presented to Keystone.
+	AccessToken string `required:"true"`
+
+	// Scope is the scope of the Keystone token. The token is unscoped when
+	// empty.
+	Scope tokens.Scope
+
+	// AllowReauth allows Gophercloud to authenticate again with the same
+	// options when the token expires.
+	AllowReauth bool
+}
+
+// ToOIDCAccessToken implements AuthOptionsBuilder.
+func (opts *AccessTokenAuthOptions) ToOIDCAccessToken(*gophercloud.ProviderClient) (string, error) {
+	if err := checkRequired(opts); err != nil {
+		return "", err
+	}
+	return opts.AccessToken, nil
+}
+
+// ToOIDCProtocol implements AuthOptionsBuilder.
+func (opts *AccessTokenAuthOptions) ToOIDCProtocol() (string, string) {
+	return opts.IdentityProvider, opts.Protocol
+}
+
+// ToTokenV3CreateMap is a dummy method to satisfy tokens.AuthOptionsBuilder
+// interface, the token is created by Create.
+func (opts *AccessTokenAuthOptions) ToTokenV3CreateMap(map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+// ToTokenV3HeadersMap is a dummy method to satisfy tokens.AuthOptionsBuilder
+// interface.
+func (opts *AccessTokenAuthOptions) ToTokenV3HeadersMap(map[string]interface{}) (map[string]string, error) {
+	return nil, nil
+}
+
+// ToTokenV3ScopeMap builds the scope of the Keystone token.
+func (opts *AccessTokenAuthOptions) ToTokenV3ScopeMap() (map[string]interface{}, error) {
+	return scopeMap(opts.Scope)
+}
+
+// CanReauth implements tokens.AuthOptionsBuilder.
+func (opts *AccessTokenAuthOptions) CanReauth() bool {
+	return opts.AllowReauth
+}
+
+// Create obtains an access token from the OpenID Connect provider, exchanges
+// it for an unscoped Keystone token, and scopes the token if a scope was
+// requested.
+func Create(c *gophercloud.ServiceClient, opts AuthOptionsBuilder) (r tokens.CreateResult) {
+	scope, err := opts.ToTokenV3ScopeMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	accessToken, err := opts.ToOIDCAccessToken(c.ProviderClient)
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	idp, protocol := opts.ToOIDCProtocol()
+	resp, err := c.Post(federatedAuthURL(c, idp, protocol), nil, &r.Body, &gophercloud.RequestOpts{
+		MoreHeaders: map[string]string{
+			"X-Auth-Token":  "",
+			"Authorization": "Bearer " + accessToken,
+		},
+		OkCodes: []int{200, 201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	if r.Err != nil || scope == nil {
+		return
+	}
+
+	unscoped, err := r.ExtractTokenID()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	var scoped tokens.CreateResult
+	resp, err = c.Post(tokenURL(c), map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"token"},
+				"token":   map[string]interface{}{"id": unscoped},
+			},
+			"scope": scope,
+		},
+	}, &scoped.Body, &gophercloud.RequestOpts{
+		MoreHeaders: map[string]string{"X-Auth-Token": ""},
+	})
+	_, scoped.Header, scoped.Err = gophercloud.ParseResponse(resp, err)
+	return scoped
+}
+
+// requestAccessToken requests a token to the token endpoint of the OpenID
+// Connect provider, discovering the endpoint if needed.
+func requestAccessToken(provider *gophercloud.ProviderClient, endpoint, discoveryEndpoint, clientID, clientSecret string, openIDScope []string, tokenType AccessTokenType, form url.Values) (string, error) {
+	// the Keystone token of the client, if any, must not be sent to the
+	// OpenID Connect provider, whose 401 responses mustn't trigger a
+	// reauthentication either
+	client := *provider
+	client.SetThrowaway(true)
+	client.ReauthFunc = nil
+	client.ReauthContextFunc = nil
+
+	if endpoint == "" {
+		if discoveryEndpoint == "" {
+			err := gophercloud.ErrMissingInput{}
+			err.Argument = "AccessTokenEndpoint"
+			return "", err
+		}
+		var discovery struct {
+			TokenEndpoint string `json:"token_endpoint"`
+		}
+		_, err := client.Request("GET", discoveryEndpoint, &gophercloud.RequestOpts{
+			JSONResponse: &discovery,
+			OkCodes:      []int{200},
+		})
+		if err != nil {
+			return "", err
+		}
+		if discovery.TokenEndpoint == "" {
+			return "", ErrTokenEndpointNotFound{DiscoveryEndpoint: discoveryEndpoint}
+		}
+		endpoint = discovery.TokenEndpoint
+	}
+
+	scopes := []string{"openid"}
+	for _, s := range openIDScope {
+		if s != "openid" {
+			scopes = append(scopes, s)
+		}
+	}
+	form.Set("scope", strings.Join(scopes, " "))
+
+	// the client authenticates with HTTP basic authentication, as recommended
+	// by RFC 6749
+	credentials := url.QueryEscape(clientID) + ":" + url.QueryEscape(clientSecret)
+
+	var resp map[string]interface{}
+	_, err := client.Request("POST", endpoint, &gophercloud.RequestOpts{
+		RawBody:      strings.NewReader(form.Encode()),
+		JSONResponse: &resp,
+		MoreHeaders: map[string]string{
+			"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials)),
+			"Content-Type":  "application/x-www-form-urlencoded",
+		},
+		OkCodes: []int{200},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if tokenType == "" {
+		tokenType = AccessToken
+	}
+	token, _ := resp[string(tokenType)].(string)
+	if token == "" {
+		return "", ErrTokenNotFound{TokenType: tokenType}
+	}
+	return token, nil
+}
+
+// scopeMap returns the scope of the Keystone token, or nil if it's unscoped.
+func scopeMap(scope tokens.Scope) (map[string]interface{}, error) {
+	if scope == (tokens.Scope{}) {
+		return nil, nil
+	}
+	opts := tokens.AuthOptions{Scope: scope}
+	return opts.ToTokenV3ScopeMap()
+}
+
+// checkRequired returns an ErrMissingInput if a required field of opts is not
+// set.
+func checkRequired(opts interface{}) error {
+	_, err := gophercloud.BuildRequestBody(opts, "")
+	return err
+}
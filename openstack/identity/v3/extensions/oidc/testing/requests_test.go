@@ -0,0 +1,226 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/oidc"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+const tokenOutput = `
+{
+	"token": {
+		"methods": ["%s"],
+		"expires_at": "2017-06-03T02:19:49.000000Z",
+		"catalog": []
+	}
+}
+`
+
+// handleIdP registers the discovery document and the token endpoint of a fake
+// OpenID Connect provider, which checks the grant type.
+func handleIdP(t *testing.T, form map[string]string) *int32 {
+	var requests int32
+
+	th.Mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"issuer": "%s", "token_endpoint": "%soidc/token"}`, th.Endpoint(), th.Endpoint())
+	})
+
+	th.Mux.HandleFunc("/oidc/token", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Content-Type", "application/x-www-form-urlencoded")
+		th.TestHeaderUnset(t, r, "X-Auth-Token")
+		id, secret, ok := r.BasicAuth()
+		th.AssertEquals(t, true, ok)
+		th.AssertEquals(t, "myclient", id)
+		th.AssertEquals(t, "s3cr3t", secret)
+		th.TestFormValues(t, r, form)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "access-%d", "id_token": "id-%d", "token_type": "Bearer"}`, n, n)
+	})
+
+	return &requests
+}
+
+// handleKeystone registers the federated auth URL of the "myidp" identity
+// provider and the token endpoint of a fake Keystone under prefix. The
+// federated auth URL returns the unscoped token "unscoped-<access token>".
+func handleKeystone(t *testing.T, prefix string, scopeJSON string) {
+	th.Mux.HandleFunc(prefix+"OS-FEDERATION/identity_providers/myidp/protocols/openid/auth", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		var accessToken string
+		fmt.Sscanf(r.Header.Get("Authorization"), "Bearer %s", &accessToken)
+
+		w.Header().Set("X-Subject-Token", "unscoped-"+accessToken)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, tokenOutput, "openid")
+	})
+
+	th.Mux.HandleFunc(prefix+"auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		var body struct {
+			Auth struct {
+				Identity struct {
+					Methods []string `json:"methods"`
+					Token   struct {
+						ID string `json:"id"`
+					} `json:"token"`
+				} `json:"identity"`
+				Scope json.RawMessage `json:"scope"`
+			} `json:"auth"`
+		}
+		th.AssertNoErr(t, json.NewDecoder(r.Body).Decode(&body))
+		th.AssertDeepEquals(t, []string{"token"}, body.Auth.Identity.Methods)
+		th.AssertEquals(t, scopeJSON, string(body.Auth.Scope))
+
+		w.Header().Set("X-Subject-Token", "scoped-"+body.Auth.Identity.Token.ID)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, tokenOutput, "token")
+	})
+}
+
+func TestCreatePassword(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	handleIdP(t, map[string]string{
+		"grant_type": "password",
+		"username":   "alice",
+		"password":   "wonderland",
+		"scope":      "openid profile",
+	})
+	handleKeystone(t, "/", `{"project":{"id":"myproject"}}`)
+
+	opts := oidc.PasswordAuthOptions{
+		IdentityProvider:  "myidp",
+		Protocol:          "openid",
+		ClientID:          "myclient",
+		ClientSecret:      "s3cr3t",
+		DiscoveryEndpoint: th.Endpoint() + ".well-known/openid-configuration",
+		Username:          "alice",
+		Password:          "wonderland",
+		OpenIDScope:       []string{"openid", "profile"},
+		Scope:             tokens.Scope{ProjectID: "myproject"},
+	}
+	tokenID, err := oidc.Create(client.ServiceClient(), &opts).ExtractTokenID()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "scoped-unscoped-access-1", tokenID)
+}
+
+func TestCreateClientCredentialsIDToken(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	handleIdP(t, map[string]string{
+		"grant_type": "client_credentials",
+		"scope":      "openid",
+	})
+	handleKeystone(t, "/", "")
+
+	opts := oidc.ClientCredentialsAuthOptions{
+		IdentityProvider:    "myidp",
+		Protocol:            "openid",
+		ClientID:            "myclient",
+		ClientSecret:        "s3cr3t",
+		AccessTokenEndpoint: th.Endpoint() + "oidc/token",
+		AccessTokenType:     oidc.IDToken,
+	}
+	r := oidc.Create(client.ServiceClient(), &opts)
+	tokenID, err := r.ExtractTokenID()
+	th.AssertNoErr(t, err)
+	// the token is left unscoped
+	th.AssertEquals(t, "unscoped-id-1", tokenID)
+
+	token, err := r.ExtractToken()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "unscoped-id-1", token.ID)
+}
+
+func TestCreateAccessToken(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	handleKeystone(t, "/", "")
+
+	opts := oidc.AccessTokenAuthOptions{
+		IdentityProvider: "myidp",
+		Protocol:         "openid",
+		AccessToken:      "mytoken",
+	}
+	tokenID, err := oidc.Create(client.ServiceClient(), &opts).ExtractTokenID()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "unscoped-mytoken", tokenID)
+}
+
+func TestCreateMissingInput(t *testing.T) {
+	opts := oidc.PasswordAuthOptions{
+		IdentityProvider: "myidp",
+		Protocol:         "openid",
+		ClientID:         "myclient",
+		Username:         "alice",
+		Password:         "wonderland",
+	}
+	err := oidc.Create(client.ServiceClient(), &opts).Err
+	if _, ok := err.(gophercloud.ErrMissingInput); !ok {
+		t.Fatalf("expected an ErrMissingInput, got %T: %v", err, err)
+	}
+}
+
+func TestAuthenticateV3Reauth(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	requests := handleIdP(t, map[string]string{
+		"grant_type": "password",
+		"username":   "alice",
+		"password":   "wonderland",
+		"scope":      "openid",
+	})
+	handleKeystone(t, "/v3/", `{"project":{"id":"myproject"}}`)
+
+	th.Mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "scoped-unscoped-access-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	provider, err := openstack.NewClient(th.Endpoint())
+	th.AssertNoErr(t, err)
+	err = openstack.AuthenticateV3(provider, &oidc.PasswordAuthOptions{
+		IdentityProvider:    "myidp",
+		Protocol:            "openid",
+		ClientID:            "myclient",
+		ClientSecret:        "s3cr3t",
+		AccessTokenEndpoint: th.Endpoint() + "oidc/token",
+		Username:            "alice",
+		Password:            "wonderland",
+		Scope:               tokens.Scope{ProjectID: "myproject"},
+		AllowReauth:         true,
+	}, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "scoped-unscoped-access-1", provider.Token())
+
+	// the first token is rejected, a new access token is requested
+	_, err = provider.Request("GET", th.Endpoint()+"resource", &gophercloud.RequestOpts{
+		OkCodes: []int{204},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "scoped-unscoped-access-2", provider.Token())
+	th.AssertEquals(t, int32(2), atomic.LoadInt32(requests))
+}
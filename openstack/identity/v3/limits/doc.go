@@ -0,0 +1,79 @@
+/*
+Package limits provides information and interaction with the project and
+domain limits of the unified limits API in the OpenStack Identity Service.
+A limit overrides the default of a registered limit, see the
+registeredlimits package.
+
+Example to Get the Enforcement Model
+
+	model, err := limits.GetEnforcementModel(identityClient).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to List Limits
+
+	listOpts := limits.ListOpts{
+		ProjectID: "3a705b9f56bb439381b43c4fe59dccce",
+	}
+
+	allPages, err := limits.List(identityClient, listOpts).AllPages()
+	if err != nil {
+		panic(err)
+	}
+
+	allLimits, err := limits.ExtractLimits(allPages)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, limit := range allLimits {
+		fmt.Printf("%+v\n", limit)
+	}
+
+Example to Create Limits
+
+	batchCreateOpts := limits.BatchCreateOpts{
+		limits.CreateOpts{
+			ServiceID:     "9408080f1970482aa0e38bc2d4ea34b7",
+			ProjectID:     "3a705b9f56bb439381b43c4fe59dccce",
+			RegionID:      "RegionOne",
+			ResourceName:  "snapshot",
+			ResourceLimit: 5,
+		},
+		limits.CreateOpts{
+			ServiceID:     "9408080f1970482aa0e38bc2d4ea34b7",
+			DomainID:      "edbafc92be354ffa977c58aa79c7bdb2",
+			ResourceName:  "volume",
+			ResourceLimit: 10,
+		},
+	}
+
+	createdLimits, err := limits.BatchCreate(identityClient, batchCreateOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Update a Limit
+
+	limitID := "25a04c7a065c430590881c646cdcdd58"
+
+	resourceLimit := 11
+	updateOpts := limits.UpdateOpts{
+		ResourceLimit: &resourceLimit,
+	}
+
+	limit, err := limits.Update(identityClient, limitID, updateOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Delete a Limit
+
+	limitID := "25a04c7a065c430590881c646cdcdd58"
+	err := limits.Delete(identityClient, limitID).ExtractErr()
+	if err != nil {
+		panic(err)
+	}
+*/
+package limits
@@ -0,0 +1,172 @@
+package limits
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// GetEnforcementModel retrieves the limit enforcement model used by the
+// deployment.
+func GetEnforcementModel(client *gophercloud.ServiceClient) (r EnforcementModelResult) {
+	resp, err := client.Get(enforcementModelURL(client), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// ListOptsBuilder allows extensions to add additional parameters to
+// the List request.
+type ListOptsBuilder interface {
+	ToLimitListQuery() (string, error)
+}
+
+// ListOpts provides options to filter the List results.
+type ListOpts struct {
+	// ServiceID filters the response by a service ID.
+	ServiceID string `q:"service_id"`
+
+	// RegionID filters the response by a region ID.
+	RegionID string `q:"region_id"`
+
+	// ResourceName filters the response by a resource name.
+	ResourceName string `q:"resource_name"`
+
+	// ProjectID filters the response by a project ID.
+	ProjectID string `q:"project_id"`
+
+	// DomainID filters the response by a domain ID.
+	DomainID string `q:"domain_id"`
+}
+
+// ToLimitListQuery formats a ListOpts into a query string.
+func (opts ListOpts) ToLimitListQuery() (string, error) {
+	q, err := gophercloud.BuildQueryString(opts)
+	return q.String(), err
+}
+
+// List enumerates the limits.
+func List(client *gophercloud.ServiceClient, opts ListOptsBuilder) pagination.Pager {
+	url := listURL(client)
+	if opts != nil {
+		query, err := opts.ToLimitListQuery()
+		if err != nil {
+			return pagination.Pager{Err: err}
+		}
+		url += query
+	}
+	return pagination.NewPager(client, url, func(r pagination.PageResult) pagination.Page {
+		return LimitPage{pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// Get retrieves details on a single limit, by ID.
+func Get(client *gophercloud.ServiceClient, limitID string) (r GetResult) {
+	resp, err := client.Get(getURL(client, limitID), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// BatchCreateOptsBuilder allows extensions to add additional parameters to
+// the BatchCreate request.
+type BatchCreateOptsBuilder interface {
+	ToLimitsCreateMap() (map[string]interface{}, error)
+}
+
+// CreateOpts provides options used to create a limit.
+type CreateOpts struct {
+	// RegionID is the ID of the region where the limit is applied.
+	RegionID string `json:"region_id,omitempty"`
+
+	// ProjectID is the ID of the project to which the limit belongs.
+	ProjectID string `json:"project_id,omitempty"`
+
+	// DomainID is the ID of the domain to which the limit belongs.
+	DomainID string `json:"domain_id,omitempty"`
+
+	// ServiceID is the ID of the service where the limit is applied.
+	ServiceID string `json:"service_id" required:"true"`
+
+	// Description of the limit.
+	Description string `json:"description,omitempty"`
+
+	// ResourceName is the name of the resource that the limit is applied to.
+	ResourceName string `json:"resource_name" required:"true"`
+
+	// ResourceLimit is the override limit.
+	ResourceLimit int `json:"resource_limit"`
+}
+
+// ToMap formats a CreateOpts into a map.
+func (opts CreateOpts) ToMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "")
+}
+
+// BatchCreateOpts provides options used to create several limits at once.
+type BatchCreateOpts []CreateOpts
+
+// ToLimitsCreateMap formats a BatchCreateOpts into a create request.
+func (opts BatchCreateOpts) ToLimitsCreateMap() (map[string]interface{}, error) {
+	limits := make([]map[string]interface{}, len(opts))
+	for i, limit := range opts {
+		limitMap, err := limit.ToMap()
+		if err != nil {
+			return nil, err
+		}
+		limits[i] = limitMap
+	}
+	return map[string]interface{}{"limits": limits}, nil
+}
+
+// BatchCreate creates new limits. Either all of them are created or none.
+func BatchCreate(client *gophercloud.ServiceClient, opts BatchCreateOptsBuilder) (r CreateResult) {
+	b, err := opts.ToLimitsCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Post(createURL(client), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// UpdateOptsBuilder allows extensions to add additional parameters to
+// the Update request.
+type UpdateOptsBuilder interface {
+	ToLimitUpdateMap() (map[string]interface{}, error)
+}
+
+// UpdateOpts provides options for updating a limit.
+type UpdateOpts struct {
+	// Description of the limit.
+	Description *string `json:"description,omitempty"`
+
+	// ResourceLimit is the override limit.
+	ResourceLimit *int `json:"resource_limit,omitempty"`
+}
+
+// ToLimitUpdateMap formats an UpdateOpts into an update request.
+func (opts UpdateOpts) ToLimitUpdateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "limit")
+}
+
+// Update updates an existing limit.
+func Update(client *gophercloud.ServiceClient, limitID string, opts UpdateOptsBuilder) (r UpdateResult) {
+	b, err := opts.ToLimitUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Patch(updateURL(client, limitID), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// Delete deletes a limit.
+func Delete(client *gophercloud.ServiceClient, limitID string) (r DeleteResult) {
+	resp, err := client.Delete(deleteURL(client, limitID), nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
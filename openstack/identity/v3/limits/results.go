@@ -0,0 +1,142 @@
+package limits
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// EnforcementModel represents the limit enforcement model of a deployment.
+type EnforcementModel struct {
+	// Name is the name of the enforcement model, "flat" or "strict_two_level".
+	Name string `json:"name"`
+
+	// Description is a description of the enforcement model.
+	Description string `json:"description"`
+}
+
+// EnforcementModelResult is the response from a GetEnforcementModel
+// operation. Call its Extract method to interpret it as an EnforcementModel.
+type EnforcementModelResult struct {
+	gophercloud.Result
+}
+
+// Extract interprets an EnforcementModelResult as an EnforcementModel.
+func (r EnforcementModelResult) Extract() (*EnforcementModel, error) {
+	var s struct {
+		Model *EnforcementModel `json:"model"`
+	}
+	err := r.ExtractInto(&s)
+	return s.Model, err
+}
+
+// Limit is a project or domain specific override of a registered limit.
+type Limit struct {
+	// ID is the unique ID of the limit.
+	ID string `json:"id"`
+
+	// RegionID is the ID of the region where the limit is applied.
+	RegionID string `json:"region_id"`
+
+	// ProjectID is the ID of the project to which the limit belongs.
+	ProjectID string `json:"project_id"`
+
+	// DomainID is the ID of the domain to which the limit belongs.
+	DomainID string `json:"domain_id"`
+
+	// ServiceID is the ID of the service where the limit is applied.
+	ServiceID string `json:"service_id"`
+
+	// Description of the limit.
+	Description string `json:"description"`
+
+	// ResourceName is the name of the resource that the limit is applied to.
+	ResourceName string `json:"resource_name"`
+
+	// ResourceLimit is the override limit.
+	ResourceLimit int `json:"resource_limit"`
+
+	// Links contains referencing links to the limit.
+	Links map[string]interface{} `json:"links"`
+}
+
+type limitResult struct {
+	gophercloud.Result
+}
+
+// Extract interprets any limit result as a Limit.
+func (r limitResult) Extract() (*Limit, error) {
+	var s struct {
+		Limit *Limit `json:"limit"`
+	}
+	err := r.ExtractInto(&s)
+	return s.Limit, err
+}
+
+// GetResult is the response from a Get operation. Call its Extract method
+// to interpret it as a Limit.
+type GetResult struct {
+	limitResult
+}
+
+// UpdateResult is the response from an Update operation. Call its Extract
+// method to interpret it as a Limit.
+type UpdateResult struct {
+	limitResult
+}
+
+// CreateResult is the response from a BatchCreate operation. Call its
+// Extract method to interpret it as a slice of Limits.
+type CreateResult struct {
+	gophercloud.Result
+}
+
+// Extract interprets a CreateResult as a slice of Limits.
+func (r CreateResult) Extract() ([]Limit, error) {
+	var s struct {
+		Limits []Limit `json:"limits"`
+	}
+	err := r.ExtractInto(&s)
+	return s.Limits, err
+}
+
+// DeleteResult is the response from a Delete operation. Call its ExtractErr to
+// determine if the request succeeded or failed.
+type DeleteResult struct {
+	gophercloud.ErrResult
+}
+
+// LimitPage is a single page of Limit results.
+type LimitPage struct {
+	pagination.LinkedPageBase
+}
+
+// IsEmpty determines whether or not a page of Limits contains any results.
+func (r LimitPage) IsEmpty() (bool, error) {
+	limits, err := ExtractLimits(r)
+	return len(limits) == 0, err
+}
+
+// NextPageURL extracts the "next" link from the links section of the result.
+func (r LimitPage) NextPageURL() (string, error) {
+	var s struct {
+		Links struct {
+			Next     string `json:"next"`
+			Previous string `json:"previous"`
+		} `json:"links"`
+	}
+	err := r.ExtractInto(&s)
+	if err != nil {
+		return "", err
+	}
+	return s.Links.Next, err
+}
+
+// ExtractLimits returns a slice of Limits contained in a single page of
+// results.
+func ExtractLimits(r pagination.Page) ([]Limit, error) {
+	var s struct {
+		Limits []Limit `json:"limits"`
+	}
+	err := (r.(LimitPage)).ExtractInto(&s)
+	return s.Limits, err
+}
@@ -0,0 +1,263 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/limits"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+// GetEnforcementModelOutput provides a GetEnforcementModel result.
+const GetEnforcementModelOutput = `
+{
+    "model": {
+        "description": "Limit enforcement and validation does not take project hierarchy into consideration.",
+        "name": "flat"
+    }
+}
+`
+
+// ListOutput provides a single page of Limit results.
+const ListOutput = `
+{
+    "links": {
+        "self": "http://10.3.150.25/identity/v3/limits",
+        "previous": null,
+        "next": null
+    },
+    "limits": [
+        {
+            "resource_name": "volume",
+            "region_id": null,
+            "links": {
+                "self": "http://10.3.150.25/identity/v3/limits/25a04c7a065c430590881c646cdcdd58"
+            },
+            "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+            "project_id": "3a705b9f56bb439381b43c4fe59dccce",
+            "domain_id": null,
+            "id": "25a04c7a065c430590881c646cdcdd58",
+            "resource_limit": 11,
+            "description": "Number of volumes for project 3a705b9f56bb439381b43c4fe59dccce"
+        },
+        {
+            "resource_name": "snapshot",
+            "region_id": "RegionOne",
+            "links": {
+                "self": "http://10.3.150.25/identity/v3/limits/3229b3849f584faea483d6851f7aab05"
+            },
+            "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+            "project_id": null,
+            "domain_id": "edbafc92be354ffa977c58aa79c7bdb2",
+            "id": "3229b3849f584faea483d6851f7aab05",
+            "resource_limit": 5,
+            "description": null
+        }
+    ]
+}
+`
+
+// GetOutput provides a Get result.
+const GetOutput = `
+{
+    "limit": {
+        "resource_name": "volume",
+        "region_id": null,
+        "links": {
+            "self": "http://10.3.150.25/identity/v3/limits/25a04c7a065c430590881c646cdcdd58"
+        },
+        "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+        "project_id": "3a705b9f56bb439381b43c4fe59dccce",
+        "domain_id": null,
+        "id": "25a04c7a065c430590881c646cdcdd58",
+        "resource_limit": 11,
+        "description": "Number of volumes for project 3a705b9f56bb439381b43c4fe59dccce"
+    }
+}
+`
+
+// CreateRequest provides the input to a BatchCreate request.
+const CreateRequest = `
+{
+    "limits":[
+        {
+            "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+            "project_id": "3a705b9f56bb439381b43c4fe59dccce",
+            "resource_name": "volume",
+            "resource_limit": 11,
+            "description": "Number of volumes for project 3a705b9f56bb439381b43c4fe59dccce"
+        },
+        {
+            "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+            "domain_id": "edbafc92be354ffa977c58aa79c7bdb2",
+            "region_id": "RegionOne",
+            "resource_name": "snapshot",
+            "resource_limit": 5
+        }
+    ]
+}
+`
+
+// UpdateRequest provides the input to an Update request.
+const UpdateRequest = `
+{
+    "limit": {
+        "resource_limit": 10,
+        "description": "Number of snapshots for domain edbafc92be354ffa977c58aa79c7bdb2"
+    }
+}
+`
+
+// UpdateOutput provides an Update result.
+const UpdateOutput = `
+{
+    "limit": {
+        "resource_name": "snapshot",
+        "region_id": "RegionOne",
+        "links": {
+            "self": "http://10.3.150.25/identity/v3/limits/3229b3849f584faea483d6851f7aab05"
+        },
+        "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+        "project_id": null,
+        "domain_id": "edbafc92be354ffa977c58aa79c7bdb2",
+        "id": "3229b3849f584faea483d6851f7aab05",
+        "resource_limit": 10,
+        "description": "Number of snapshots for domain edbafc92be354ffa977c58aa79c7bdb2"
+    }
+}
+`
+
+// Model is the enforcement model in the GetEnforcementModel request.
+var Model = limits.EnforcementModel{
+	Name:        "flat",
+	Description: "Limit enforcement and validation does not take project hierarchy into consideration.",
+}
+
+// FirstLimit is the first limit in the List request.
+var FirstLimit = limits.Limit{
+	ResourceName: "volume",
+	Links: map[string]interface{}{
+		"self": "http://10.3.150.25/identity/v3/limits/25a04c7a065c430590881c646cdcdd58",
+	},
+	ServiceID:     "9408080f1970482aa0e38bc2d4ea34b7",
+	ProjectID:     "3a705b9f56bb439381b43c4fe59dccce",
+	ID:            "25a04c7a065c430590881c646cdcdd58",
+	ResourceLimit: 11,
+	Description:   "Number of volumes for project 3a705b9f56bb439381b43c4fe59dccce",
+}
+
+// SecondLimit is the second limit in the List request.
+var SecondLimit = limits.Limit{
+	ResourceName: "snapshot",
+	RegionID:     "RegionOne",
+	Links: map[string]interface{}{
+		"self": "http://10.3.150.25/identity/v3/limits/3229b3849f584faea483d6851f7aab05",
+	},
+	ServiceID:     "9408080f1970482aa0e38bc2d4ea34b7",
+	DomainID:      "edbafc92be354ffa977c58aa79c7bdb2",
+	ID:            "3229b3849f584faea483d6851f7aab05",
+	ResourceLimit: 5,
+}
+
+// SecondLimitUpdated is how SecondLimit should look after an Update.
+var SecondLimitUpdated = limits.Limit{
+	ResourceName: "snapshot",
+	RegionID:     "RegionOne",
+	Links: map[string]interface{}{
+		"self": "http://10.3.150.25/identity/v3/limits/3229b3849f584faea483d6851f7aab05",
+	},
+	ServiceID:     "9408080f1970482aa0e38bc2d4ea34b7",
+	DomainID:      "edbafc92be354ffa977c58aa79c7bdb2",
+	ID:            "3229b3849f584faea483d6851f7aab05",
+	ResourceLimit: 10,
+	Description:   "Number of snapshots for domain edbafc92be354ffa977c58aa79c7bdb2",
+}
+
+// ExpectedLimitsSlice is the slice of limits expected to be returned from
+// ListOutput.
+var ExpectedLimitsSlice = []limits.Limit{FirstLimit, SecondLimit}
+
+// HandleGetEnforcementModelSuccessfully creates an HTTP handler at
+// `/limits/model` on the test handler mux that responds with the enforcement
+// model.
+func HandleGetEnforcementModelSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/limits/model", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetEnforcementModelOutput)
+	})
+}
+
+// HandleListLimitsSuccessfully creates an HTTP handler at `/limits` on the
+// test handler mux that responds with a list of two limits.
+func HandleListLimitsSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/limits", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, ListOutput)
+	})
+}
+
+// HandleGetLimitSuccessfully creates an HTTP handler at
+// `/limits/25a04c7a065c430590881c646cdcdd58` on the test handler mux that
+// responds with a single limit.
+func HandleGetLimitSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/limits/25a04c7a065c430590881c646cdcdd58", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetOutput)
+	})
+}
+
+// HandleCreateLimitSuccessfully creates an HTTP handler at `/limits` on the
+// test handler mux that tests limit creation.
+func HandleCreateLimitSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/limits", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, CreateRequest)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, ListOutput)
+	})
+}
+
+// HandleUpdateLimitSuccessfully creates an HTTP handler at
+// `/limits/3229b3849f584faea483d6851f7aab05` on the test handler mux that
+// tests limit update.
+func HandleUpdateLimitSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/limits/3229b3849f584faea483d6851f7aab05", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PATCH")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, UpdateRequest)
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, UpdateOutput)
+	})
+}
+
+// HandleDeleteLimitSuccessfully creates an HTTP handler at
+// `/limits/3229b3849f584faea483d6851f7aab05` on the test handler mux that
+// tests limit deletion.
+func HandleDeleteLimitSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/limits/3229b3849f584faea483d6851f7aab05", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
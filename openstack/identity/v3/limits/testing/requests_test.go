@@ -0,0 +1,141 @@
+package testing
+
+import (
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/limits"
+	"github.com/gophercloud/gophercloud/pagination"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+func TestGetEnforcementModel(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleGetEnforcementModelSuccessfully(t)
+
+	actual, err := limits.GetEnforcementModel(client.ServiceClient()).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, Model, *actual)
+}
+
+func TestListLimits(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleListLimitsSuccessfully(t)
+
+	count := 0
+	err := limits.List(client.ServiceClient(), nil).EachPage(func(page pagination.Page) (bool, error) {
+		count++
+
+		actual, err := limits.ExtractLimits(page)
+		th.AssertNoErr(t, err)
+
+		th.CheckDeepEquals(t, ExpectedLimitsSlice, actual)
+
+		return true, nil
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, count, 1)
+}
+
+func TestListLimitsAllPages(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleListLimitsSuccessfully(t)
+
+	allPages, err := limits.List(client.ServiceClient(), nil).AllPages()
+	th.AssertNoErr(t, err)
+	actual, err := limits.ExtractLimits(allPages)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ExpectedLimitsSlice, actual)
+}
+
+func TestListLimitsOpts(t *testing.T) {
+	opts := limits.ListOpts{
+		ServiceID:    "9408080f1970482aa0e38bc2d4ea34b7",
+		RegionID:     "RegionOne",
+		ResourceName: "snapshot",
+	}
+	query, err := opts.ToLimitListQuery()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "?region_id=RegionOne&resource_name=snapshot&service_id=9408080f1970482aa0e38bc2d4ea34b7", query)
+}
+
+func TestGetLimit(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleGetLimitSuccessfully(t)
+
+	actual, err := limits.Get(client.ServiceClient(), "25a04c7a065c430590881c646cdcdd58").Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, FirstLimit, *actual)
+}
+
+func TestCreateLimits(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleCreateLimitSuccessfully(t)
+
+	createOpts := limits.BatchCreateOpts{
+		limits.CreateOpts{
+			ServiceID:     "9408080f1970482aa0e38bc2d4ea34b7",
+			ProjectID:     "3a705b9f56bb439381b43c4fe59dccce",
+			ResourceName:  "volume",
+			ResourceLimit: 11,
+			Description:   "Number of volumes for project 3a705b9f56bb439381b43c4fe59dccce",
+		},
+		limits.CreateOpts{
+			ServiceID:     "9408080f1970482aa0e38bc2d4ea34b7",
+			DomainID:      "edbafc92be354ffa977c58aa79c7bdb2",
+			RegionID:      "RegionOne",
+			ResourceName:  "snapshot",
+			ResourceLimit: 5,
+		},
+	}
+
+	actual, err := limits.BatchCreate(client.ServiceClient(), createOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ExpectedLimitsSlice, actual)
+}
+
+func TestCreateLimitsMissingInput(t *testing.T) {
+	createOpts := limits.BatchCreateOpts{
+		limits.CreateOpts{
+			ResourceName:  "volume",
+			ResourceLimit: 11,
+		},
+	}
+
+	err := limits.BatchCreate(client.ServiceClient(), createOpts).Err
+	if _, ok := err.(gophercloud.ErrMissingInput); !ok {
+		t.Fatalf("expected an ErrMissingInput, got %T: %v", err, err)
+	}
+}
+
+func TestUpdateLimit(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleUpdateLimitSuccessfully(t)
+
+	description := "Number of snapshots for domain edbafc92be354ffa977c58aa79c7bdb2"
+	resourceLimit := 10
+	updateOpts := limits.UpdateOpts{
+		Description:   &description,
+		ResourceLimit: &resourceLimit,
+	}
+
+	actual, err := limits.Update(client.ServiceClient(), "3229b3849f584faea483d6851f7aab05", updateOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, SecondLimitUpdated, *actual)
+}
+
+func TestDeleteLimit(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	HandleDeleteLimitSuccessfully(t)
+
+	err := limits.Delete(client.ServiceClient(), "3229b3849f584faea483d6851f7aab05").ExtractErr()
+	th.AssertNoErr(t, err)
+}
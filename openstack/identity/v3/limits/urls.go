@@ -0,0 +1,32 @@
+package limits
+
+import "github.com/gophercloud/gophercloud"
+
+const (
+	rootPath             = "limits"
+	enforcementModelPath = "model"
+)
+
+func enforcementModelURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath, enforcementModelPath)
+}
+
+func listURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath)
+}
+
+func getURL(client *gophercloud.ServiceClient, limitID string) string {
+	return client.ServiceURL(rootPath, limitID)
+}
+
+func createURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath)
+}
+
+func updateURL(client *gophercloud.ServiceClient, limitID string) string {
+	return client.ServiceURL(rootPath, limitID)
+}
+
+func deleteURL(client *gophercloud.ServiceClient, limitID string) string {
+	return client.ServiceURL(rootPath, limitID)
+}
@@ -0,0 +1,70 @@
+/*
+Package registeredlimits provides information and interaction with the
+registered limits of the unified limits API in the OpenStack Identity Service.
+A registered limit is the default limit of a resource of a service; it can be
+overridden per project or domain, see the limits package.
+
+Example to List Registered Limits
+
+	listOpts := registeredlimits.ListOpts{
+		ServiceID: "9408080f1970482aa0e38bc2d4ea34b7",
+	}
+
+	allPages, err := registeredlimits.List(identityClient, listOpts).AllPages()
+	if err != nil {
+		panic(err)
+	}
+
+	allLimits, err := registeredlimits.ExtractRegisteredLimits(allPages)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, limit := range allLimits {
+		fmt.Printf("%+v\n", limit)
+	}
+
+Example to Create Registered Limits
+
+	batchCreateOpts := registeredlimits.BatchCreateOpts{
+		registeredlimits.CreateOpts{
+			ServiceID:    "9408080f1970482aa0e38bc2d4ea34b7",
+			RegionID:     "RegionOne",
+			ResourceName: "snapshot",
+			DefaultLimit: 5,
+		},
+		registeredlimits.CreateOpts{
+			ServiceID:    "9408080f1970482aa0e38bc2d4ea34b7",
+			ResourceName: "volume",
+			DefaultLimit: 10,
+		},
+	}
+
+	createdLimits, err := registeredlimits.BatchCreate(identityClient, batchCreateOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Update a Registered Limit
+
+	registeredLimitID := "966b3c7d36a24facaf20b7e458bf2192"
+
+	defaultLimit := 15
+	updateOpts := registeredlimits.UpdateOpts{
+		DefaultLimit: &defaultLimit,
+	}
+
+	limit, err := registeredlimits.Update(identityClient, registeredLimitID, updateOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Delete a Registered Limit
+
+	registeredLimitID := "966b3c7d36a24facaf20b7e458bf2192"
+	err := registeredlimits.Delete(identityClient, registeredLimitID).ExtractErr()
+	if err != nil {
+		panic(err)
+	}
+*/
+package registeredlimits
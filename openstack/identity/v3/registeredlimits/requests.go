@@ -0,0 +1,169 @@
+package registeredlimits
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// ListOptsBuilder allows extensions to add additional parameters to
+// the List request.
+type ListOptsBuilder interface {
+	ToRegisteredLimitListQuery() (string, error)
+}
+
+// ListOpts provides options to filter the List results.
+type ListOpts struct {
+	// ServiceID filters the response by a service ID.
+	ServiceID string `q:"service_id"`
+
+	// RegionID filters the response by a region ID.
+	RegionID string `q:"region_id"`
+
+	// ResourceName filters the response by a resource name.
+	ResourceName string `q:"resource_name"`
+}
+
+// ToRegisteredLimitListQuery formats a ListOpts into a query string.
+func (opts ListOpts) ToRegisteredLimitListQuery() (string, error) {
+	q, err := gophercloud.BuildQueryString(opts)
+	return q.String(), err
+}
+
+// List enumerates the registered limits.
+func List(client *gophercloud.ServiceClient, opts ListOptsBuilder) pagination.Pager {
+	url := listURL(client)
+	if opts != nil {
+		query, err := opts.ToRegisteredLimitListQuery()
+		if err != nil {
+			return pagination.Pager{Err: err}
+		}
+		url += query
+	}
+	return pagination.NewPager(client, url, func(r pagination.PageResult) pagination.Page {
+		return RegisteredLimitPage{pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// Get retrieves details on a single registered limit, by ID.
+func Get(client *gophercloud.ServiceClient, registeredLimitID string) (r GetResult) {
+	resp, err := client.Get(getURL(client, registeredLimitID), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// BatchCreateOptsBuilder allows extensions to add additional parameters to
+// the BatchCreate request.
+type BatchCreateOptsBuilder interface {
+	ToRegisteredLimitsCreateMap() (map[string]interface{}, error)
+}
+
+// CreateOpts provides options used to create a registered limit.
+type CreateOpts struct {
+	// RegionID is the ID of the region where the registered limit is applied.
+	RegionID string `json:"region_id,omitempty"`
+
+	// ServiceID is the ID of the service where the registered limit is
+	// applied.
+	ServiceID string `json:"service_id" required:"true"`
+
+	// Description of the registered limit.
+	Description string `json:"description,omitempty"`
+
+	// ResourceName is the name of the resource that the registered limit is
+	// applied to.
+	ResourceName string `json:"resource_name" required:"true"`
+
+	// DefaultLimit is the default limit for projects and domains without a
+	// limit of their own.
+	DefaultLimit int `json:"default_limit"`
+}
+
+// ToMap formats a CreateOpts into a map.
+func (opts CreateOpts) ToMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "")
+}
+
+// BatchCreateOpts provides options used to create several registered limits
+// at once.
+type BatchCreateOpts []CreateOpts
+
+// ToRegisteredLimitsCreateMap formats a BatchCreateOpts into a create request.
+func (opts BatchCreateOpts) ToRegisteredLimitsCreateMap() (map[string]interface{}, error) {
+	registeredLimits := make([]map[string]interface{}, len(opts))
+	for i, registeredLimit := range opts {
+		registeredLimitMap, err := registeredLimit.ToMap()
+		if err != nil {
+			return nil, err
+		}
+		registeredLimits[i] = registeredLimitMap
+	}
+	return map[string]interface{}{"registered_limits": registeredLimits}, nil
+}
+
+// BatchCreate creates new registered limits. Either all of them are created
+// or none.
+func BatchCreate(client *gophercloud.ServiceClient, opts BatchCreateOptsBuilder) (r CreateResult) {
+	b, err := opts.ToRegisteredLimitsCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Post(createURL(client), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// UpdateOptsBuilder allows extensions to add additional parameters to
+// the Update request.
+type UpdateOptsBuilder interface {
+	ToRegisteredLimitUpdateMap() (map[string]interface{}, error)
+}
+
+// UpdateOpts provides options for updating a registered limit.
+type UpdateOpts struct {
+	// RegionID is the ID of the region where the registered limit is applied.
+	RegionID string `json:"region_id,omitempty"`
+
+	// ServiceID is the ID of the service where the registered limit is
+	// applied.
+	ServiceID string `json:"service_id,omitempty"`
+
+	// Description of the registered limit.
+	Description *string `json:"description,omitempty"`
+
+	// ResourceName is the name of the resource that the registered limit is
+	// applied to.
+	ResourceName string `json:"resource_name,omitempty"`
+
+	// DefaultLimit is the default limit for projects and domains without a
+	// limit of their own.
+	DefaultLimit *int `json:"default_limit,omitempty"`
+}
+
+// ToRegisteredLimitUpdateMap formats an UpdateOpts into an update request.
+func (opts UpdateOpts) ToRegisteredLimitUpdateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "registered_limit")
+}
+
+// Update updates an existing registered limit.
+func Update(client *gophercloud.ServiceClient, registeredLimitID string, opts UpdateOptsBuilder) (r UpdateResult) {
+	b, err := opts.ToRegisteredLimitUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Patch(updateURL(client, registeredLimitID), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// Delete deletes a registered limit.
+func Delete(client *gophercloud.ServiceClient, registeredLimitID string) (r DeleteResult) {
+	resp, err := client.Delete(deleteURL(client, registeredLimitID), nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
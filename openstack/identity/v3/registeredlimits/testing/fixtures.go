@@ -0,0 +1,221 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/registeredlimits"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+// ListOutput provides a single page of RegisteredLimit results.
+const ListOutput = `
+{
+    "links": {
+        "self": "http://10.3.150.25/identity/v3/registered_limits",
+        "previous": null,
+        "next": null
+    },
+    "registered_limits": [
+        {
+            "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+            "region_id": "RegionOne",
+            "resource_name": "snapshot",
+            "default_limit": 5,
+            "description": null,
+            "id": "3229b3849f584faea483d6851f7aab05",
+            "links": {
+                "self": "http://10.3.150.25/identity/v3/registered_limits/3229b3849f584faea483d6851f7aab05"
+            }
+        },
+        {
+            "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+            "region_id": null,
+            "resource_name": "volume",
+            "default_limit": 10,
+            "description": "Number of volumes",
+            "id": "25a04c7a065c430590881c646cdcdd58",
+            "links": {
+                "self": "http://10.3.150.25/identity/v3/registered_limits/25a04c7a065c430590881c646cdcdd58"
+            }
+        }
+    ]
+}
+`
+
+// GetOutput provides a Get result.
+const GetOutput = `
+{
+    "registered_limit": {
+        "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+        "region_id": "RegionOne",
+        "resource_name": "snapshot",
+        "default_limit": 5,
+        "description": null,
+        "id": "3229b3849f584faea483d6851f7aab05",
+        "links": {
+            "self": "http://10.3.150.25/identity/v3/registered_limits/3229b3849f584faea483d6851f7aab05"
+        }
+    }
+}
+`
+
+// CreateRequest provides the input to a BatchCreate request.
+const CreateRequest = `
+{
+    "registered_limits":[
+        {
+            "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+            "region_id": "RegionOne",
+            "resource_name": "snapshot",
+            "default_limit": 5
+        },
+        {
+            "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+            "resource_name": "volume",
+            "default_limit": 10,
+            "description": "Number of volumes"
+        }
+    ]
+}
+`
+
+// UpdateRequest provides the input to an Update request.
+const UpdateRequest = `
+{
+    "registered_limit": {
+        "default_limit": 15,
+        "resource_name": "volumes"
+    }
+}
+`
+
+// UpdateOutput provides an Update result.
+const UpdateOutput = `
+{
+    "registered_limit": {
+        "service_id": "9408080f1970482aa0e38bc2d4ea34b7",
+        "region_id": null,
+        "resource_name": "volumes",
+        "default_limit": 15,
+        "description": "Number of volumes",
+        "id": "25a04c7a065c430590881c646cdcdd58",
+        "links": {
+            "self": "http://10.3.150.25/identity/v3/registered_limits/25a04c7a065c430590881c646cdcdd58"
+        }
+    }
+}
+`
+
+// FirstRegisteredLimit is the first registered limit in the List request.
+var FirstRegisteredLimit = registeredlimits.RegisteredLimit{
+	ServiceID:    "9408080f1970482aa0e38bc2d4ea34b7",
+	RegionID:     "RegionOne",
+	ResourceName: "snapshot",
+	DefaultLimit: 5,
+	ID:           "3229b3849f584faea483d6851f7aab05",
+	Links: map[string]interface{}{
+		"self": "http://10.3.150.25/identity/v3/registered_limits/3229b3849f584faea483d6851f7aab05",
+	},
+}
+
+// SecondRegisteredLimit is the second registered limit in the List request.
+var SecondRegisteredLimit = registeredlimits.RegisteredLimit{
+	ServiceID:    "9408080f1970482aa0e38bc2d4ea34b7",
+	ResourceName: "volume",
+	DefaultLimit: 10,
+	Description:  "Number of volumes",
+	ID:           "25a04c7a065c430590881c646cdcdd58",
+	Links: map[string]interface{}{
+		"self": "http://10.3.150.25/identity/v3/registered_limits/25a04c7a065c430590881c646cdcdd58",
+	},
+}
+
+// SecondRegisteredLimitUpdated is how SecondRegisteredLimit should look after
+// an Update.
+var SecondRegisteredLimitUpdated = registeredlimits.RegisteredLimit{
+	ServiceID:    "9408080f1970482aa0e38bc2d4ea34b7",
+	ResourceName: "volumes",
+	DefaultLimit: 15,
+	Description:  "Number of volumes",
+	ID:           "25a04c7a065c430590881c646cdcdd58",
+	Links: map[string]interface{}{
+		"self": "http://10.3.150.25/identity/v3/registered_limits/25a04c7a065c430590881c646cdcdd58",
+	},
+}
+
+// ExpectedRegisteredLimitsSlice is the slice of registered limits expected to
+// be returned from ListOutput.
+var ExpectedRegisteredLimitsSlice = []registeredlimits.RegisteredLimit{FirstRegisteredLimit, SecondRegisteredLimit}
+
+// HandleListRegisteredLimitsSuccessfully creates an HTTP handler at
+// `/registered_limits` on the test handler mux that responds with a list of
+// two registered limits.
+func HandleListRegisteredLimitsSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/registered_limits", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, ListOutput)
+	})
+}
+
+// HandleGetRegisteredLimitSuccessfully creates an HTTP handler at
+// `/registered_limits/3229b3849f584faea483d6851f7aab05` on the test handler
+// mux that responds with a single registered limit.
+func HandleGetRegisteredLimitSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/registered_limits/3229b3849f584faea483d6851f7aab05", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetOutput)
+	})
+}
+
+// HandleCreateRegisteredLimitSuccessfully creates an HTTP handler at
+// `/registered_limits` on the test handler mux that tests registered limit
+// creation.
+func HandleCreateRegisteredLimitSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/registered_limits", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, CreateRequest)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, ListOutput)
+	})
+}
+
+// HandleUpdateRegisteredLimitSuccessfully creates an HTTP handler at
+// `/registered_limits/25a04c7a065c430590881c646cdcdd58` on the test handler
+// mux that tests registered limit update.
+func HandleUpdateRegisteredLimitSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/registered_limits/25a04c7a065c430590881c646cdcdd58", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PATCH")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, UpdateRequest)
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, UpdateOutput)
+	})
+}
+
+// HandleDeleteRegisteredLimitSuccessfully creates an HTTP handler at
+// `/registered_limits/3229b3849f584faea483d6851f7aab05` on the test handler
+// mux that tests registered limit deletion.
+func HandleDeleteRegisteredLimitSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/registered_limits/3229b3849f584faea483d6851f7aab05", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,25 @@
+package registeredlimits
+
+import "github.com/gophercloud/gophercloud"
+
+const rootPath = "registered_limits"
+
+func listURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath)
+}
+
+func getURL(client *gophercloud.ServiceClient, registeredLimitID string) string {
+	return client.ServiceURL(rootPath, registeredLimitID)
+}
+
+func createURL(client *gophercloud.ServiceClient) string {
+	return client.ServiceURL(rootPath)
+}
+
+func updateURL(client *gophercloud.ServiceClient, registeredLimitID string) string {
+	return client.ServiceURL(rootPath, registeredLimitID)
+}
+
+func deleteURL(client *gophercloud.ServiceClient, registeredLimitID string) string {
+	return client.ServiceURL(rootPath, registeredLimitID)
+}
@@ -104,5 +104,55 @@ Example to Create a Token from a Username and Password with Project Name Scope
 		panic(err)
 	}
 
+Example to Complete a Multi-Factor Authentication with an Auth Receipt
+
+	authOptions := tokens.AuthOptions{
+		UserID:   "username",
+		Password: "password",
+	}
+
+	token, err := tokens.Create(identityClient, &authOptions).ExtractToken()
+	if receipt, ok := err.(tokens.ErrAuthReceipt); ok {
+		fmt.Printf("missing one of %v\n", receipt.MissingMethods())
+
+		authOptions = tokens.AuthOptions{
+			UserID:   "username",
+			Passcode: "123456",
+			Receipt:  receipt.Receipt,
+		}
+		token, err = tokens.Create(identityClient, &authOptions).ExtractToken()
+	}
+	if err != nil {
+		panic(err)
+	}
+
+Example to Create a Token from a Password and a TOTP Secret
+
+	authOptions := tokens.AuthOptions{
+		UserID:      "username",
+		Password:    "password",
+		TOTPSecret:  "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		AllowReauth: true,
+	}
+
+	token, err := tokens.Create(identityClient, &authOptions).ExtractToken()
+	if err != nil {
+		panic(err)
+	}
+
+	// or, to authenticate a ProviderClient
+	err = openstack.AuthenticateV3(providerClient, &authOptions, gophercloud.EndpointOpts{})
+
+Example to Check the Roles and the Scope of Another Token
+
+	info, err := tokens.Get(identityClient, "token_id").ExtractTokenInfo()
+	if err != nil {
+		panic(err)
+	}
+
+	if info.Project != nil && info.HasRole("member") {
+		fmt.Printf("%s is a member of %s\n", info.User.Name, info.Project.Name)
+	}
+
 */
 package tokens
@@ -0,0 +1,95 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// authReceiptHeader is the header holding the auth receipts.
+const authReceiptHeader = "Openstack-Auth-Receipt"
+
+// ErrAuthReceipt is returned by Create when the credentials were valid but
+// don't satisfy the multi-factor authentication rules of the user. Create can
+// be called again with the Receipt and the missing methods to get a token.
+type ErrAuthReceipt struct {
+	gophercloud.BaseError
+
+	// Receipt is the auth receipt, to pass in AuthOptions.Receipt.
+	Receipt string
+
+	// Methods are the authentication methods already satisfied.
+	Methods []string
+
+	// RequiredAuthMethods are the rules of the user; each of them lists the
+	// methods which together allow to authenticate.
+	RequiredAuthMethods [][]string
+
+	// ExpiresAt is the timestamp at which the receipt will no longer be
+	// accepted.
+	ExpiresAt time.Time
+
+	// User is the user the receipt was issued to.
+	User User
+
+	// ErrOriginal is the 401 response carrying the receipt.
+	ErrOriginal gophercloud.ErrDefault401
+}
+
+func newErrAuthReceipt(err401 gophercloud.ErrDefault401) ErrAuthReceipt {
+	e := ErrAuthReceipt{
+		Receipt:     err401.ResponseHeader.Get(authReceiptHeader),
+		ErrOriginal: err401,
+	}
+
+	var s struct {
+		Receipt struct {
+			Methods   []string  `json:"methods"`
+			ExpiresAt time.Time `json:"expires_at"`
+			User      User      `json:"user"`
+		} `json:"receipt"`
+		RequiredAuthMethods [][]string `json:"required_auth_methods"`
+	}
+	// the receipt is usable even if the body can't be decoded
+	if json.Unmarshal(err401.Body, &s) == nil {
+		e.Methods = s.Receipt.Methods
+		e.ExpiresAt = s.Receipt.ExpiresAt
+		e.User = s.Receipt.User
+		e.RequiredAuthMethods = s.RequiredAuthMethods
+	}
+
+	return e
+}
+
+// MissingMethods returns, for each rule of RequiredAuthMethods, the methods
+// which aren't satisfied yet. Satisfying all the methods of one of them
+// completes the authentication.
+func (e ErrAuthReceipt) MissingMethods() [][]string {
+	satisfied := make(map[string]bool, len(e.Methods))
+	for _, method := range e.Methods {
+		satisfied[method] = true
+	}
+
+	missing := make([][]string, 0, len(e.RequiredAuthMethods))
+	for _, rule := range e.RequiredAuthMethods {
+		var methods []string
+		for _, method := range rule {
+			if !satisfied[method] {
+				methods = append(methods, method)
+			}
+		}
+		missing = append(missing, methods)
+	}
+	return missing
+}
+
+func (e ErrAuthReceipt) Error() string {
+	return fmt.Sprintf("Additional authentication methods are required, missing one of %v", e.MissingMethods())
+}
+
+// Unwrap returns the 401 response carrying the receipt.
+func (e ErrAuthReceipt) Unwrap() error {
+	return e.ErrOriginal
+}
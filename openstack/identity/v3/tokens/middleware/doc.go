@@ -0,0 +1,33 @@
+/*
+Package middleware validates the tokens received by an HTTP server against the
+OpenStack Identity Service, and caches the valid ones.
+
+Example to Protect an HTTP Handler
+
+	validator := middleware.NewValidator(identityClient)
+	validator.CacheTTL = time.Minute
+
+	handler := validator.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, _ := middleware.TokenInfoFromContext(r.Context())
+		if !info.HasRole("admin") {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		fmt.Fprintf(w, "Hello %s\n", info.User.Name)
+	}))
+
+	http.ListenAndServe(":8080", handler)
+
+Example to Validate a Token
+
+	info, err := validator.Validate(tokenID)
+	if err != nil {
+		if _, ok := err.(middleware.ErrInvalidToken); ok {
+			// the token was rejected
+		}
+		panic(err)
+	}
+
+	fmt.Printf("%+v\n", info)
+*/
+package middleware
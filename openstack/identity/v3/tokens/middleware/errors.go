@@ -0,0 +1,11 @@
+package middleware
+
+import "github.com/gophercloud/gophercloud"
+
+// ErrInvalidToken is returned by Validate when the token is unknown to
+// Keystone, revoked or expired.
+type ErrInvalidToken struct{ gophercloud.BaseError }
+
+func (e ErrInvalidToken) Error() string {
+	return "The token is invalid or expired."
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+)
+
+// DefaultCacheTTL is the time a Validator caches the result of the validation
+// of a token when its CacheTTL isn't set.
+const DefaultCacheTTL = 5 * time.Minute
+
+// DefaultCacheSize is the number of tokens a Validator caches when its
+// CacheSize isn't set.
+const DefaultCacheSize = 1000
+
+type cacheEntry struct {
+	key     string
+	info    *tokens.TokenInfo
+	expires time.Time
+}
+
+// Validator validates tokens against Keystone, and caches the results.
+type Validator struct {
+	// ServiceClient is the identity v3 client used to validate the tokens. Its
+	// own token must be allowed to validate other tokens.
+	ServiceClient *gophercloud.ServiceClient
+
+	// CacheTTL is the time the result of the validation of a valid token is
+	// cached. Tokens are never cached past their expiration, and invalid
+	// tokens aren't cached. Defaults to DefaultCacheTTL; a negative value
+	// disables the cache.
+	CacheTTL time.Duration
+
+	// CacheSize is the maximum number of cached tokens. The oldest one is
+	// evicted when the cache is full. Defaults to DefaultCacheSize.
+	CacheSize int
+
+	mu sync.Mutex
+	// cache indexes the elements of order, which holds the cacheEntry values
+	// from the oldest to the newest.
+	cache map[string]*list.Element
+	order list.List
+}
+
+// NewValidator returns a Validator which validates the tokens with client and
+// caches the results for DefaultCacheTTL.
+func NewValidator(client *gophercloud.ServiceClient) *Validator {
+	return &Validator{ServiceClient: client}
+}
+
+func (v *Validator) cacheTTL() time.Duration {
+	if v.CacheTTL == 0 {
+		return DefaultCacheTTL
+	}
+	return v.CacheTTL
+}
+
+func (v *Validator) cacheSize() int {
+	if v.CacheSize <= 0 {
+		return DefaultCacheSize
+	}
+	return v.CacheSize
+}
+
+// cacheKey hashes the token so that the cache doesn't hold usable tokens.
+func cacheKey(tokenID string) string {
+	sum := sha256.Sum256([]byte(tokenID))
+	return hex.EncodeToString(sum[:])
+}
+
+func (v *Validator) cached(key string, now time.Time) (*tokens.TokenInfo, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	elem, ok := v.cache[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(cacheEntry)
+	if !now.Before(entry.expires) {
+		v.order.Remove(elem)
+		delete(v.cache, key)
+		return nil, false
+	}
+	return entry.info, true
+}
+
+func (v *Validator) store(entry cacheEntry) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.cache == nil {
+		v.cache = make(map[string]*list.Element)
+	}
+	if elem, ok := v.cache[entry.key]; ok {
+		v.order.Remove(elem)
+		delete(v.cache, entry.key)
+	}
+	for len(v.cache) >= v.cacheSize() {
+		oldest := v.order.Front()
+		v.order.Remove(oldest)
+		delete(v.cache, oldest.Value.(cacheEntry).key)
+	}
+	v.cache[entry.key] = v.order.PushBack(entry)
+}
+
+// copyTokenInfo returns a deep copy of info, so that the callers of Validate
+// can't modify the cached one.
+func copyTokenInfo(info *tokens.TokenInfo) *tokens.TokenInfo {
+	c := *info
+	if info.Project != nil {
+		project := *info.Project
+		c.Project = &project
+	}
+	if info.Domain != nil {
+		domain := *info.Domain
+		c.Domain = &domain
+	}
+	if info.System != nil {
+		system := *info.System
+		c.System = &system
+	}
+	if info.ApplicationCredential != nil {
+		appCred := *info.ApplicationCredential
+		appCred.AccessRules = append([]tokens.ApplicationCredentialAccessRule(nil), appCred.AccessRules...)
+		c.ApplicationCredential = &appCred
+	}
+	c.Roles = append([]tokens.Role(nil), info.Roles...)
+	c.Methods = append([]string(nil), info.Methods...)
+	c.AuditIDs = append([]string(nil), info.AuditIDs...)
+	return &c
+}
+
+// Validate checks tokenID against Keystone and returns its details. It
+// returns an ErrInvalidToken if Keystone rejects the token, and the error of
+// the request if Keystone couldn't be reached. Valid tokens are cached for
+// CacheTTL.
+func (v *Validator) Validate(tokenID string) (*tokens.TokenInfo, error) {
+	if tokenID == "" {
+		return nil, ErrInvalidToken{}
+	}
+
+	ttl := v.cacheTTL()
+	key := cacheKey(tokenID)
+	now := time.Now()
+	if ttl > 0 {
+		if info, ok := v.cached(key, now); ok {
+			return copyTokenInfo(info), nil
+		}
+	}
+
+	info, err := tokens.Get(v.ServiceClient, tokenID).ExtractTokenInfo()
+	if err != nil {
+		if gophercloud.ResponseCodeIs(err, http.StatusNotFound) {
+			return nil, ErrInvalidToken{}
+		}
+		return nil, err
+	}
+	if !now.Before(info.ExpiresAt) {
+		return nil, ErrInvalidToken{}
+	}
+	if info.ID == "" {
+		info.ID = tokenID
+	}
+
+	if ttl > 0 {
+		entry := cacheEntry{key: key, info: info, expires: now.Add(ttl)}
+		if info.ExpiresAt.Before(entry.expires) {
+			entry.expires = info.ExpiresAt
+		}
+		v.store(entry)
+		info = copyTokenInfo(info)
+	}
+
+	return info, nil
+}
+
+type contextKey struct{}
+
+// TokenInfoFromContext returns the details of the token validated by the
+// Handler of a Validator, from the context of the request.
+func TokenInfoFromContext(ctx context.Context) (*tokens.TokenInfo, bool) {
+	info, ok := ctx.Value(contextKey{}).(*tokens.TokenInfo)
+	return info, ok
+}
+
+// Handler returns an http.Handler which validates the X-Auth-Token header of
+// the requests before passing them to next, with the details of the token in
+// their context, see TokenInfoFromContext. It responds 401 Unauthorized to
+// the requests without a valid token, and 503 Service Unavailable when
+// Keystone couldn't be reached.
+func (v *Validator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, err := v.Validate(r.Header.Get("X-Auth-Token"))
+		if err != nil {
+			if _, ok := err.(ErrInvalidToken); ok {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, "The token could not be validated.", http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, info)))
+	})
+}
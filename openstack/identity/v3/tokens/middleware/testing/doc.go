@@ -0,0 +1,2 @@
+// middleware unit tests
+package testing
@@ -0,0 +1,191 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens/middleware"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+const tokenOutput = `
+{
+	"token": {
+		"methods": ["password"],
+		"expires_at": "%s",
+		"issued_at": "2017-06-03T01:19:49.000000Z",
+		"project": {
+			"domain": {"id": "default", "name": "Default"},
+			"id": "a99e9b4e620e4db09a2dfb6e42a01e66",
+			"name": "admin"
+		},
+		"roles": [{"id": "434426788d5a451faf763b0e6db5aefb", "name": "admin"}],
+		"user": {
+			"domain": {"id": "default", "name": "Default"},
+			"id": "0fe36e73809d46aeae6705c39077b1b3",
+			"name": "admin"
+		}
+	}
+}
+`
+
+// handleGetToken registers a fake Keystone which knows the "valid" and "other"
+// tokens, expiring at expiresAt, and the "expired" token. It returns the number of
+// validation requests.
+func handleGetToken(t *testing.T, expiresAt time.Time) *int32 {
+	var requests int32
+	th.Mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		var expires time.Time
+		switch r.Header.Get("X-Subject-Token") {
+		case "valid", "other":
+			expires = expiresAt
+		case "expired":
+			expires = time.Now().Add(-time.Minute)
+		case "error":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("X-Subject-Token", r.Header.Get("X-Subject-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, tokenOutput, expires.UTC().Format(time.RFC3339Nano))
+	})
+	return &requests
+}
+
+func TestValidate(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	requests := handleGetToken(t, time.Now().Add(time.Hour))
+
+	validator := middleware.NewValidator(client.ServiceClient())
+
+	for i := 0; i < 2; i++ {
+		info, err := validator.Validate("valid")
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, "valid", info.ID)
+		th.AssertEquals(t, "a99e9b4e620e4db09a2dfb6e42a01e66", info.Project.ID)
+		th.AssertEquals(t, true, info.HasRole("admin"))
+	}
+	// the second validation is served by the cache
+	th.AssertEquals(t, int32(1), atomic.LoadInt32(requests))
+
+	// the cached details can't be modified by the callers
+	info, err := validator.Validate("valid")
+	th.AssertNoErr(t, err)
+	info.Roles[0].Name = "member"
+	info, err = validator.Validate("valid")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, info.HasRole("admin"))
+	th.AssertEquals(t, int32(1), atomic.LoadInt32(requests))
+
+	// invalid tokens aren't cached
+	for i := 0; i < 2; i++ {
+		_, err := validator.Validate("unknown")
+		if _, ok := err.(middleware.ErrInvalidToken); !ok {
+			t.Fatalf("expected an ErrInvalidToken, got %T: %v", err, err)
+		}
+	}
+	th.AssertEquals(t, int32(3), atomic.LoadInt32(requests))
+
+	_, err = validator.Validate("expired")
+	if _, ok := err.(middleware.ErrInvalidToken); !ok {
+		t.Fatalf("expected an ErrInvalidToken, got %T: %v", err, err)
+	}
+}
+
+func TestValidateCacheExpiration(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	// the token expires before the TTL of the cache
+	requests := handleGetToken(t, time.Now().Add(50*time.Millisecond))
+
+	validator := middleware.NewValidator(client.ServiceClient())
+	validator.CacheTTL = time.Hour
+
+	_, err := validator.Validate("valid")
+	th.AssertNoErr(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = validator.Validate("valid")
+	if _, ok := err.(middleware.ErrInvalidToken); !ok {
+		t.Fatalf("expected an ErrInvalidToken, got %T: %v", err, err)
+	}
+	th.AssertEquals(t, int32(2), atomic.LoadInt32(requests))
+}
+
+func TestValidateCacheSize(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	requests := handleGetToken(t, time.Now().Add(time.Hour))
+
+	validator := middleware.NewValidator(client.ServiceClient())
+	validator.CacheSize = 1
+
+	// "valid" is cached, then evicted by "other"
+	for _, token := range []string{"valid", "valid", "other", "valid"} {
+		_, err := validator.Validate(token)
+		th.AssertNoErr(t, err)
+	}
+	th.AssertEquals(t, int32(3), atomic.LoadInt32(requests))
+}
+
+func TestValidateCacheDisabled(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	requests := handleGetToken(t, time.Now().Add(time.Hour))
+
+	validator := middleware.NewValidator(client.ServiceClient())
+	validator.CacheTTL = -1
+
+	for i := 0; i < 2; i++ {
+		_, err := validator.Validate("valid")
+		th.AssertNoErr(t, err)
+	}
+	th.AssertEquals(t, int32(2), atomic.LoadInt32(requests))
+}
+
+func TestHandler(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	handleGetToken(t, time.Now().Add(time.Hour))
+
+	validator := middleware.NewValidator(client.ServiceClient())
+	handler := validator.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, ok := middleware.TokenInfoFromContext(r.Context())
+		th.AssertEquals(t, true, ok)
+		fmt.Fprint(w, info.User.Name)
+	}))
+
+	for token, status := range map[string]int{
+		"valid":   http.StatusOK,
+		"unknown": http.StatusUnauthorized,
+		"":        http.StatusUnauthorized,
+		"error":   http.StatusServiceUnavailable,
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		if token != "" {
+			r.Header.Set("X-Auth-Token", token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		th.AssertEquals(t, status, w.Code)
+		if status == http.StatusOK {
+			th.AssertEquals(t, "admin", w.Body.String())
+		}
+	}
+}
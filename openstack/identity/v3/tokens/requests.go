@@ -1,6 +1,11 @@
 package tokens
 
-import "github.com/gophercloud/gophercloud"
+import (
+	"errors"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+)
 
 // Scope allows a created token to be limited to a specific domain or project.
 type Scope struct {
@@ -41,6 +46,20 @@ type AuthOptions struct {
 	// Passcode is used in TOTP authentication method
 	Passcode string `json:"passcode,omitempty"`
 
+	// TOTPSecret is the secret of a TOTP credential of the user. A passcode is
+	// generated from it for each authentication, instead of Passcode, which
+	// lets unattended clients reauthenticate.
+	//
+	// TOTPSecret and Receipt have no equivalent in gophercloud.AuthOptions,
+	// nor in clouds.yaml: pass these AuthOptions to openstack.AuthenticateV3
+	// to use them.
+	TOTPSecret string `json:"-"`
+
+	// Receipt is the auth receipt of an ErrAuthReceipt returned by a previous
+	// Create. Keystone combines the methods of the receipt with the ones of
+	// these options, so they only need to hold the missing methods.
+	Receipt string `json:"-"`
+
 	// At most one of DomainID and DomainName must be provided if using Username
 	// with Identity V3. Otherwise, either are optional.
 	DomainID   string `json:"-"`
@@ -68,11 +87,20 @@ type AuthOptions struct {
 
 // ToTokenV3CreateMap builds a request body from AuthOptions.
 func (opts *AuthOptions) ToTokenV3CreateMap(scope map[string]interface{}) (map[string]interface{}, error) {
+	passcode := opts.Passcode
+	if opts.TOTPSecret != "" {
+		var err error
+		passcode, err = GenerateTOTPPasscode(opts.TOTPSecret, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	gophercloudAuthOpts := gophercloud.AuthOptions{
 		Username:                    opts.Username,
 		UserID:                      opts.UserID,
 		Password:                    opts.Password,
-		Passcode:                    opts.Passcode,
+		Passcode:                    passcode,
 		DomainID:                    opts.DomainID,
 		DomainName:                  opts.DomainName,
 		AllowReauth:                 opts.AllowReauth,
@@ -99,17 +127,25 @@ func (opts *AuthOptions) ToTokenV3ScopeMap() (map[string]interface{}, error) {
 }
 
 func (opts *AuthOptions) CanReauth() bool {
-	if opts.Passcode != "" {
+	if opts.Passcode != "" && opts.TOTPSecret == "" {
 		// cannot reauth using TOTP passcode
 		return false
 	}
 
+	if opts.Receipt != "" {
+		// auth receipts expire quickly
+		return false
+	}
+
 	return opts.AllowReauth
 }
 
 // ToTokenV3HeadersMap allows AuthOptions to satisfy the AuthOptionsBuilder
 // interface in the v3 tokens package.
 func (opts *AuthOptions) ToTokenV3HeadersMap(map[string]interface{}) (map[string]string, error) {
+	if opts.Receipt != "" {
+		return map[string]string{authReceiptHeader: opts.Receipt}, nil
+	}
 	return nil, nil
 }
 
@@ -134,9 +170,29 @@ func Create(c *gophercloud.ServiceClient, opts AuthOptionsBuilder) (r CreateResu
 		return
 	}
 
+	headerOpts := map[string]interface{}{
+		"method": "POST",
+		"url":    tokenURL(c),
+	}
+
+	h, err := opts.ToTokenV3HeadersMap(headerOpts)
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	headers := map[string]string{"X-Auth-Token": ""}
+	for k, v := range h {
+		headers[k] = v
+	}
+
 	resp, err := c.Post(tokenURL(c), b, &r.Body, &gophercloud.RequestOpts{
-		MoreHeaders: map[string]string{"X-Auth-Token": ""},
+		MoreHeaders: headers,
 	})
+	var err401 gophercloud.ErrDefault401
+	if errors.As(err, &err401) && err401.ResponseHeader.Get(authReceiptHeader) != "" {
+		err = newErrAuthReceipt(err401)
+	}
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
 	return
 }
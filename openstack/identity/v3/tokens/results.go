@@ -1,6 +1,7 @@
 package tokens
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gophercloud/gophercloud"
@@ -116,6 +117,16 @@ func (r GetResult) ExtractTokenID() (string, error) {
 	return r.Header.Get("X-Subject-Token"), r.Err
 }
 
+// ExtractExpiresAt returns the expiration time of the Token. It is used by the
+// ProviderClient to refresh the token before it expires.
+func (r commonResult) ExtractExpiresAt() (time.Time, error) {
+	var s struct {
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	err := r.ExtractIntoStructPtr(&s, "token")
+	return s.ExpiresAt, err
+}
+
 // ExtractServiceCatalog returns the ServiceCatalog that was generated along
 // with the user's Token.
 func (r commonResult) ExtractServiceCatalog() (*ServiceCatalog, error) {
@@ -189,6 +200,105 @@ type Token struct {
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
+// System provides information about the system to which a system scoped
+// token grants access.
+type System struct {
+	// All is true when the token is scoped to the whole deployment.
+	All bool `json:"all"`
+}
+
+// ApplicationCredentialAccessRule is an API call that a restricted
+// application credential may make.
+type ApplicationCredentialAccessRule struct {
+	ID      string `json:"id"`
+	Path    string `json:"path"`
+	Method  string `json:"method"`
+	Service string `json:"service"`
+}
+
+// ApplicationCredential provides information about the application
+// credential the token was created from.
+type ApplicationCredential struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// Restricted is true when the application credential may not be used to
+	// create or delete other application credentials and trusts.
+	Restricted bool `json:"restricted"`
+
+	// AccessRules, if not empty, restrict the API calls the token may make.
+	AccessRules []ApplicationCredentialAccessRule `json:"access_rules"`
+}
+
+// TokenInfo holds the details of a token needed to authorize the requests
+// made with it, such as the validation of a token received by a service.
+type TokenInfo struct {
+	// ID is the token.
+	ID string `json:"-"`
+
+	// User is the owner of the token.
+	User User `json:"user"`
+
+	// Project is the project the token is scoped to, if any.
+	Project *Project `json:"project"`
+
+	// Domain is the domain the token is scoped to, if any.
+	Domain *Domain `json:"domain"`
+
+	// System is the system the token is scoped to, if any.
+	System *System `json:"system"`
+
+	// Roles are the roles of the user in the scope of the token.
+	Roles []Role `json:"roles"`
+
+	// Methods are the authentication methods used to create the token.
+	Methods []string `json:"methods"`
+
+	// IssuedAt is the timestamp at which the token was issued.
+	IssuedAt time.Time `json:"issued_at"`
+
+	// ExpiresAt is the timestamp at which the token will no longer be accepted.
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// AuditIDs identify the token and the chain of tokens it was created
+	// from, without revealing them.
+	AuditIDs []string `json:"audit_ids"`
+
+	// ApplicationCredential is the application credential the token was
+	// created from, if any.
+	ApplicationCredential *ApplicationCredential `json:"application_credential"`
+
+	// IsAdminProject tells whether the project of the token is the admin
+	// project of the deployment. Like the Keystone middleware, it defaults to
+	// true when Keystone doesn't report it.
+	IsAdminProject bool `json:"is_admin_project"`
+}
+
+// HasRole reports whether the user has the role name in the scope of the
+// token. Role names are compared case-insensitively, as Keystone does.
+func (t *TokenInfo) HasRole(name string) bool {
+	for _, role := range t.Roles {
+		if strings.EqualFold(role.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractTokenInfo interprets a commonResult as a TokenInfo, which gathers the
+// user, the scope and the roles of the token in a single call.
+func (r commonResult) ExtractTokenInfo() (*TokenInfo, error) {
+	s := TokenInfo{IsAdminProject: true}
+	err := r.ExtractInto(&s)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ID = r.Header.Get("X-Subject-Token")
+
+	return &s, nil
+}
+
 func (r commonResult) ExtractInto(v interface{}) error {
 	return r.ExtractIntoStructPtr(v, "token")
 }
@@ -323,3 +323,50 @@ func getGetDomainResult(t *testing.T) tokens.GetResult {
 	testhelper.AssertNoErr(t, err)
 	return result
 }
+
+// SystemAppCredToken is a sample response to a Token call for a system scoped
+// token created from an application credential.
+const SystemAppCredToken = `
+{
+   "token":{
+      "methods":[
+         "application_credential"
+      ],
+      "roles":[
+         {
+            "id":"434426788d5a451faf763b0e6db5aefb",
+            "name":"Reader"
+         }
+      ],
+      "system":{
+         "all":true
+      },
+      "application_credential":{
+         "id":"c2e9bd5f9c7b4fd0b7b0b3b1a1a0f4f2",
+         "name":"monitoring",
+         "restricted":true,
+         "access_rules":[
+            {
+               "id":"abcdef",
+               "path":"/v2.1/servers",
+               "method":"GET",
+               "service":"compute"
+            }
+         ]
+      },
+      "is_admin_project":false,
+      "expires_at":"2017-06-03T02:19:49.000000Z",
+      "user":{
+         "domain":{
+            "id":"default",
+            "name":"Default"
+         },
+         "name":"admin",
+         "id":"0fe36e73809d46aeae6705c39077b1b3"
+      },
+      "audit_ids":[
+         "ysSI0bEWR0Gmrp4LHL9LFw"
+      ],
+      "issued_at":"2017-06-03T01:19:49.000000Z"
+   }
+}`
@@ -1,6 +1,8 @@
 package testing
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -678,3 +680,154 @@ func TestNoTokenInResponse(t *testing.T) {
 	_, err := tokens.Create(&client, &options).Extract()
 	testhelper.AssertNoErr(t, err)
 }
+
+func TestCreateAuthReceipt(t *testing.T) {
+	testhelper.SetupHTTP()
+	defer testhelper.TeardownHTTP()
+
+	client := gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Endpoint:       testhelper.Endpoint(),
+	}
+
+	testhelper.Mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		testhelper.TestMethod(t, r, "POST")
+
+		if r.Header.Get("Openstack-Auth-Receipt") == "" {
+			testhelper.TestJSONRequest(t, r, `{
+				"auth": {
+					"identity": {
+						"methods": ["password"],
+						"password": {
+							"user": {
+								"id": "me",
+								"password": "squirrel!"
+							}
+						}
+					}
+				}
+			}`)
+			w.Header().Set("Openstack-Auth-Receipt", "receipt-1")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintf(w, `{
+				"receipt": {
+					"expires_at": "2018-07-05T01:05:50.000000Z",
+					"issued_at": "2018-07-05T00:05:50.000000Z",
+					"methods": ["password"],
+					"user": {
+						"domain": {"id": "default", "name": "Default"},
+						"id": "me",
+						"name": "admin"
+					}
+				},
+				"required_auth_methods": [["password", "totp"], ["password", "custom-auth-method"]]
+			}`)
+			return
+		}
+
+		testhelper.TestHeader(t, r, "Openstack-Auth-Receipt", "receipt-1")
+		testhelper.TestJSONRequest(t, r, `{
+			"auth": {
+				"identity": {
+					"methods": ["totp"],
+					"totp": {
+						"user": {
+							"id": "me",
+							"passcode": "123456"
+						}
+					}
+				}
+			}
+		}`)
+		w.Header().Set("X-Subject-Token", "token-1")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{
+			"token": {
+				"expires_at": "2014-10-02T13:45:00.000000Z"
+			}
+		}`)
+	})
+
+	err := tokens.Create(&client, &tokens.AuthOptions{UserID: "me", Password: "squirrel!"}).Err
+	receiptErr, ok := err.(tokens.ErrAuthReceipt)
+	if !ok {
+		t.Fatalf("expected an ErrAuthReceipt, got %T: %v", err, err)
+	}
+	testhelper.CheckEquals(t, "receipt-1", receiptErr.Receipt)
+	testhelper.CheckDeepEquals(t, []string{"password"}, receiptErr.Methods)
+	testhelper.CheckDeepEquals(t, [][]string{{"totp"}, {"custom-auth-method"}}, receiptErr.MissingMethods())
+	testhelper.CheckEquals(t, "me", receiptErr.User.ID)
+	testhelper.CheckEquals(t, time.Date(2018, 7, 5, 1, 5, 50, 0, time.UTC), receiptErr.ExpiresAt)
+	var err401 gophercloud.ErrDefault401
+	testhelper.CheckEquals(t, true, errors.As(err, &err401))
+
+	options := tokens.AuthOptions{
+		UserID:   "me",
+		Passcode: "123456",
+		Receipt:  receiptErr.Receipt,
+	}
+	testhelper.CheckEquals(t, false, options.CanReauth())
+	tokenID, err := tokens.Create(&client, &options).ExtractTokenID()
+	testhelper.AssertNoErr(t, err)
+	testhelper.CheckEquals(t, "token-1", tokenID)
+}
+
+func TestCreateTOTPSecret(t *testing.T) {
+	testhelper.SetupHTTP()
+	defer testhelper.TeardownHTTP()
+
+	client := gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Endpoint:       testhelper.Endpoint(),
+	}
+
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	testhelper.Mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Auth struct {
+				Identity struct {
+					Methods []string `json:"methods"`
+					TOTP    struct {
+						User struct {
+							Passcode string `json:"passcode"`
+						} `json:"user"`
+					} `json:"totp"`
+				} `json:"identity"`
+			} `json:"auth"`
+		}
+		testhelper.AssertNoErr(t, json.NewDecoder(r.Body).Decode(&body))
+		testhelper.CheckDeepEquals(t, []string{"password", "totp"}, body.Auth.Identity.Methods)
+
+		// the passcode may have been generated in the previous period
+		now := time.Now()
+		current, err := tokens.GenerateTOTPPasscode(secret, now)
+		testhelper.AssertNoErr(t, err)
+		previous, err := tokens.GenerateTOTPPasscode(secret, now.Add(-30*time.Second))
+		testhelper.AssertNoErr(t, err)
+		passcode := body.Auth.Identity.TOTP.User.Passcode
+		if passcode != current && passcode != previous {
+			t.Errorf("unexpected passcode %s", passcode)
+		}
+
+		w.Header().Set("X-Subject-Token", "token-1")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": {"expires_at": "2014-10-02T13:45:00.000000Z"}}`)
+	})
+
+	options := tokens.AuthOptions{
+		UserID:      "me",
+		Password:    "squirrel!",
+		TOTPSecret:  secret,
+		AllowReauth: true,
+	}
+	testhelper.CheckEquals(t, true, options.CanReauth())
+	_, err := tokens.Create(&client, &options).ExtractTokenID()
+	testhelper.AssertNoErr(t, err)
+
+	// the secret takes precedence over a static passcode
+	options.Passcode = "static"
+	testhelper.CheckEquals(t, true, options.CanReauth())
+	_, err = tokens.Create(&client, &options).ExtractTokenID()
+	testhelper.AssertNoErr(t, err)
+}
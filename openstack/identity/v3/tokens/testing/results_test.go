@@ -1,8 +1,12 @@
 package testing
 
 import (
+	"encoding/json"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
 	"github.com/gophercloud/gophercloud/testhelper"
 )
 
@@ -59,3 +63,54 @@ func TestExtractDomain(t *testing.T) {
 
 	testhelper.CheckDeepEquals(t, &ExpectedDomain, domain)
 }
+
+func TestExtractTokenInfo(t *testing.T) {
+	result := getGetResult(t)
+
+	info, err := result.ExtractTokenInfo()
+	testhelper.AssertNoErr(t, err)
+
+	testhelper.CheckEquals(t, testTokenID, info.ID)
+	testhelper.CheckDeepEquals(t, ExpectedUser, info.User)
+	testhelper.CheckDeepEquals(t, &ExpectedProject, info.Project)
+	testhelper.CheckDeepEquals(t, ExpectedRoles, info.Roles)
+	testhelper.CheckDeepEquals(t, []string{"password"}, info.Methods)
+	testhelper.CheckDeepEquals(t, []string{"ysSI0bEWR0Gmrp4LHL9LFw"}, info.AuditIDs)
+	testhelper.CheckEquals(t, ExpectedToken.ExpiresAt, info.ExpiresAt)
+	testhelper.CheckEquals(t, time.Date(2017, 6, 3, 1, 19, 49, 0, time.UTC), info.IssuedAt)
+	testhelper.CheckEquals(t, true, info.System == nil)
+	testhelper.CheckEquals(t, true, info.ApplicationCredential == nil)
+	// is_admin_project isn't in the response
+	testhelper.CheckEquals(t, true, info.IsAdminProject)
+	testhelper.CheckEquals(t, true, info.HasRole("Admin"))
+	testhelper.CheckEquals(t, false, info.HasRole("member"))
+}
+
+func TestExtractSystemAppCredTokenInfo(t *testing.T) {
+	result := tokens.GetResult{}
+	result.Header = http.Header{
+		"X-Subject-Token": []string{testTokenID},
+	}
+	err := json.Unmarshal([]byte(SystemAppCredToken), &result.Body)
+	testhelper.AssertNoErr(t, err)
+
+	info, err := result.ExtractTokenInfo()
+	testhelper.AssertNoErr(t, err)
+
+	testhelper.CheckDeepEquals(t, &tokens.System{All: true}, info.System)
+	testhelper.CheckEquals(t, true, info.Project == nil)
+	testhelper.CheckEquals(t, false, info.IsAdminProject)
+	testhelper.CheckDeepEquals(t, &tokens.ApplicationCredential{
+		ID:         "c2e9bd5f9c7b4fd0b7b0b3b1a1a0f4f2",
+		Name:       "monitoring",
+		Restricted: true,
+		AccessRules: []tokens.ApplicationCredentialAccessRule{
+			{
+				ID:      "abcdef",
+				Path:    "/v2.1/servers",
+				Method:  "GET",
+				Service: "compute",
+			},
+		},
+	}, info.ApplicationCredential)
+}
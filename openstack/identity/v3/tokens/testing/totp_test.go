@@ -0,0 +1,40 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	th "github.com/gophercloud/gophercloud/testhelper"
+)
+
+func TestGenerateTOTPPasscode(t *testing.T) {
+	// base32 encoding of the SHA1 key of the test vectors of RFC 6238, whose
+	// passcodes are truncated to the 6 digits used by Keystone
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	for unix, expected := range map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	} {
+		passcode, err := tokens.GenerateTOTPPasscode(secret, time.Unix(unix, 0))
+		th.AssertNoErr(t, err)
+		th.CheckEquals(t, expected, passcode)
+	}
+
+	// Keystone accepts unpadded and lowercase secrets
+	passcode, err := tokens.GenerateTOTPPasscode("gezdgnbvgy3tqojq", time.Unix(59, 0))
+	th.AssertNoErr(t, err)
+	expected, err := tokens.GenerateTOTPPasscode("GEZDGNBVGY3TQOJQ", time.Unix(59, 0))
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, expected, passcode)
+
+	_, err = tokens.GenerateTOTPPasscode("not base32!", time.Unix(59, 0))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,43 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// totpPeriod is the time in seconds during which a passcode is valid.
+const totpPeriod = 30
+
+// GenerateTOTPPasscode returns the passcode valid at t for the secret of a
+// Keystone TOTP credential, which is the base32 encoded blob of the
+// credential. Keystone uses RFC 6238 passcodes of 6 digits, renewed every 30
+// seconds, with HMAC-SHA1.
+func GenerateTOTPPasscode(secret string, t time.Time) (string, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+	// Keystone pads the secret as needed
+	if n := len(secret) % 8; n != 0 {
+		secret += strings.Repeat("=", 8-n)
+	}
+	key, err := base32.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: %v", err)
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/totpPeriod))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, see RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0xf
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", code%1000000), nil
+}
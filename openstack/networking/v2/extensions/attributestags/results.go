@@ -1,6 +1,8 @@
 package attributestags
 
 import (
+	"net/http"
+
 	"github.com/gophercloud/gophercloud"
 )
 
@@ -48,7 +50,7 @@ func (r ConfirmResult) Extract() (bool, error) {
 	exists := r.Err == nil
 
 	if r.Err != nil {
-		if _, ok := r.Err.(gophercloud.ErrDefault404); ok {
+		if gophercloud.ResponseCodeIs(r.Err, http.StatusNotFound) {
 			r.Err = nil
 		}
 	}
@@ -0,0 +1,47 @@
+/*
+Package dirsync mirrors a local directory into an Object Storage container.
+
+The files of the directory are compared with the objects of the container by
+size, modification time and checksum. The new and modified files are uploaded
+concurrently and, optionally, the objects without a file are deleted. The
+files larger than Opts.SegmentSize are uploaded as static large objects, and
+compared with them by the checksums of their segments. The segments of the
+static large objects which are replaced or deleted are deleted along with
+them.
+
+Example to Preview the Synchronization of a Directory
+
+	opts := dirsync.Opts{
+		LocalDir: "./public",
+		Prefix:   "assets/",
+		Exclude:  []string{"*.tmp", ".git/*"},
+		Delete:   true,
+		DryRun:   true,
+	}
+
+	plan, err := dirsync.Sync(objectStorageClient, "my_container", opts)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, action := range plan.Actions {
+		fmt.Printf("%s %s (%s)\n", action.Type, action.Name, action.Reason)
+	}
+
+Example to Synchronize a Directory
+
+	opts := dirsync.Opts{
+		LocalDir:    "./public",
+		Prefix:      "assets/",
+		Delete:      true,
+		Concurrency: 8,
+	}
+
+	plan, err := dirsync.Sync(objectStorageClient, "my_container", opts)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%d uploaded, %d deleted, %d unchanged\n", len(plan.Uploads()), len(plan.Deletes()), len(plan.Unchanged))
+*/
+package dirsync
@@ -0,0 +1,21 @@
+package dirsync
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// ErrBulkDelete is the error when some of the extraneous objects couldn't be
+// deleted.
+type ErrBulkDelete struct {
+	gophercloud.BaseError
+
+	// Errors are the names of the objects which couldn't be deleted, along
+	// with the reason.
+	Errors [][]string
+}
+
+func (e ErrBulkDelete) Error() string {
+	return fmt.Sprintf("Unable to delete %d objects: %v", len(e.Errors), e.Errors)
+}
@@ -0,0 +1,349 @@
+package dirsync
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+)
+
+// Opts are options for synchronizing a local directory into a container.
+type Opts struct {
+	// (REQUIRED) LocalDir is the directory whose regular files are uploaded.
+	LocalDir string
+
+	// Prefix is prepended to the paths of the files, relative to LocalDir, to
+	// name their objects. Only the objects starting with Prefix are compared
+	// with the files. Optional.
+	Prefix string
+
+	// Include restricts the synchronization to the files matching one of these
+	// glob patterns, in the syntax of path.Match. A pattern containing a slash
+	// is matched against the slash-separated path of the file relative to
+	// LocalDir, and a pattern without slash against its base name. Optional.
+	Include []string
+
+	// Exclude leaves out the files matching one of these glob patterns, in the
+	// same syntax as Include. The objects of the excluded files are neither
+	// uploaded nor deleted. Optional.
+	Exclude []string
+
+	// Delete removes the objects starting with Prefix which don't have a file
+	// in LocalDir. Optional.
+	Delete bool
+
+	// Checksum compares the checksum of every file with the ETag of its object.
+	// By default, a file with the same size as its object and which wasn't
+	// modified after it is considered unchanged without being read. Optional.
+	Checksum bool
+
+	// SegmentSize is the size above which files are uploaded as static large
+	// objects with objects.Upload, in segments of SegmentSize bytes stored in
+	// the "<container>_segments" container. The checksum of such a file is
+	// compared with the checksum of the checksums of its segments, which is
+	// the ETag of its object. When a static large object is replaced or
+	// deleted, the segments of its previous manifest are deleted too, so they
+	// must not be shared with other objects. Defaults to
+	// objects.DefaultSegmentSize.
+	SegmentSize int64
+
+	// DryRun computes the Plan without uploading or deleting anything.
+	// Optional.
+	DryRun bool
+
+	// Concurrency is the number of files uploaded at the same time. Defaults to
+	// DefaultConcurrency.
+	Concurrency int
+}
+
+// ActionType is the kind of an Action of a Plan.
+type ActionType string
+
+const (
+	// Upload uploads a file which has no object, or whose object differs.
+	Upload ActionType = "upload"
+
+	// Delete deletes an object which has no file.
+	Delete ActionType = "delete"
+)
+
+// Action is an object to upload or delete.
+type Action struct {
+	// Type is the kind of the action.
+	Type ActionType `json:"type"`
+
+	// Name is the name of the object.
+	Name string `json:"name"`
+
+	// Path is the path of the local file, for the uploads.
+	Path string `json:"path,omitempty"`
+
+	// Bytes is the size of the file for the uploads, or of the object for the
+	// deletions.
+	Bytes int64 `json:"bytes"`
+
+	// Reason explains the action: "new" and "modified" for the uploads,
+	// "extraneous" for the deletions.
+	Reason string `json:"reason"`
+
+	// Done is true once the action was executed.
+	Done bool `json:"done"`
+
+	// largeObject is true when the object is a static large object, whose
+	// segments are deleted along with it.
+	largeObject bool
+}
+
+// Plan describes the differences between a local directory and a container,
+// and the actions synchronizing them.
+type Plan struct {
+	// Actions are the uploads, sorted by name, followed by the deletions,
+	// sorted by name.
+	Actions []Action `json:"actions"`
+
+	// Unchanged are the names of the objects which are up to date.
+	Unchanged []string `json:"unchanged"`
+}
+
+// Uploads returns the upload actions of the plan.
+func (p *Plan) Uploads() []Action {
+	return p.filter(Upload)
+}
+
+// Deletes returns the delete actions of the plan.
+func (p *Plan) Deletes() []Action {
+	return p.filter(Delete)
+}
+
+func (p *Plan) filter(t ActionType) []Action {
+	var actions []Action
+	for _, action := range p.Actions {
+		if action.Type == t {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// match reports whether the slash-separated relative path name matches one of
+// patterns.
+func match(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		subject := name
+		if !strings.Contains(pattern, "/") {
+			subject = path.Base(name)
+		}
+		ok, err := path.Match(pattern, subject)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// selected reports whether the slash-separated relative path name is part of
+// the synchronization according to opts.Include and opts.Exclude.
+func selected(opts Opts, name string) (bool, error) {
+	if len(opts.Include) > 0 {
+		ok, err := match(opts.Include, name)
+		if !ok || err != nil {
+			return false, err
+		}
+	}
+	excluded, err := match(opts.Exclude, name)
+	return !excluded, err
+}
+
+// localFile is a file of the local directory.
+type localFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func listLocalFiles(opts Opts) (map[string]localFile, error) {
+	files := make(map[string]localFile)
+	err := filepath.Walk(opts.LocalDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(opts.LocalDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		ok, err := selected(opts, rel)
+		if err != nil || !ok {
+			return err
+		}
+		files[rel] = localFile{path: p, size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return files, err
+}
+
+func listObjects(c *gophercloud.ServiceClient, containerName string, opts Opts) (map[string]objects.Object, error) {
+	allPages, err := objects.List(c, containerName, objects.ListOpts{Full: true, Prefix: opts.Prefix}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allObjects, err := objects.ExtractInfo(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	remote := make(map[string]objects.Object, len(allObjects))
+	for _, object := range allObjects {
+		if object.Subdir != "" {
+			continue
+		}
+		rel := strings.TrimPrefix(object.Name, opts.Prefix)
+		ok, err := selected(opts, rel)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			remote[rel] = object
+		}
+	}
+	return remote, nil
+}
+
+func segmentSize(opts Opts) int64 {
+	if opts.SegmentSize <= 0 {
+		return objects.DefaultSegmentSize
+	}
+	return opts.SegmentSize
+}
+
+// fileChecksum returns the checksum of a file, along with the checksum of the
+// checksums of its segments of segmentSize bytes, which is the ETag of a
+// static large object uploaded by objects.Upload.
+func fileChecksum(p string, segmentSize int64) (string, string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	hash := md5.New()
+	segmentHashes := md5.New()
+	segmentHash := md5.New()
+	for {
+		n, err := io.CopyN(io.MultiWriter(hash, segmentHash), f, segmentSize)
+		if n > 0 {
+			fmt.Fprintf(segmentHashes, "%x", segmentHash.Sum(nil))
+			segmentHash.Reset()
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), fmt.Sprintf("%x", segmentHashes.Sum(nil)), nil
+}
+
+// unchanged reports whether object is up to date with file.
+func unchanged(opts Opts, file localFile, object objects.Object) (bool, error) {
+	if file.size != object.Bytes {
+		return false, nil
+	}
+	if !opts.Checksum && !file.modTime.After(object.LastModified) {
+		return true, nil
+	}
+	checksum, segmentsChecksum, err := fileChecksum(file.path, segmentSize(opts))
+	if err != nil {
+		return false, err
+	}
+	etag := strings.Trim(object.Hash, `"`)
+	if object.SLOEtag != "" {
+		etag = strings.Trim(object.SLOEtag, `"`)
+	}
+	// The large files may also have been uploaded as regular objects.
+	return etag == checksum || (file.size > segmentSize(opts) && etag == segmentsChecksum), nil
+}
+
+// ComputePlan compares the files of opts.LocalDir with the objects of a
+// container starting with opts.Prefix, and returns the actions which would
+// make the container mirror the directory. Nothing is uploaded or deleted.
+func ComputePlan(c *gophercloud.ServiceClient, containerName string, opts Opts) (*Plan, error) {
+	if opts.LocalDir == "" {
+		return nil, gophercloud.ErrMissingInput{Argument: "dirsync.Opts.LocalDir"}
+	}
+
+	files, err := listLocalFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := listObjects(c, containerName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := new(Plan)
+	names := make([]string, 0, len(files))
+	for rel := range files {
+		names = append(names, rel)
+	}
+	sort.Strings(names)
+	for _, rel := range names {
+		file := files[rel]
+		action := Action{
+			Type:   Upload,
+			Name:   opts.Prefix + rel,
+			Path:   file.path,
+			Bytes:  file.size,
+			Reason: "new",
+		}
+		if object, ok := remote[rel]; ok {
+			ok, err := unchanged(opts, file, object)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				plan.Unchanged = append(plan.Unchanged, action.Name)
+				continue
+			}
+			action.Reason = "modified"
+			action.largeObject = object.SLOEtag != ""
+		}
+		plan.Actions = append(plan.Actions, action)
+	}
+
+	if opts.Delete {
+		names = names[:0]
+		for rel := range remote {
+			if _, ok := files[rel]; !ok {
+				names = append(names, rel)
+			}
+		}
+		sort.Strings(names)
+		for _, rel := range names {
+			plan.Actions = append(plan.Actions, Action{
+				Type:        Delete,
+				Name:        opts.Prefix + rel,
+				Bytes:       remote[rel].Bytes,
+				Reason:      "extraneous",
+				largeObject: remote[rel].SLOEtag != "",
+			})
+		}
+	}
+
+	return plan, nil
+}
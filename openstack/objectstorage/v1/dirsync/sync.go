@@ -0,0 +1,231 @@
+package dirsync
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+)
+
+// DefaultConcurrency is the number of files uploaded at the same time when
+// Opts.Concurrency isn't set.
+const DefaultConcurrency = 4
+
+// bulkDeleteSize is the number of objects deleted by a bulk delete request,
+// below the default limit of the Object Storage service.
+const bulkDeleteSize = 1000
+
+// Sync makes a container mirror opts.LocalDir: it computes the Plan with
+// ComputePlan, uploads up to opts.Concurrency files at the same time, the
+// files larger than opts.SegmentSize as static large objects, and deletes the
+// extraneous objects if opts.Delete is set. The segments of the replaced and
+// deleted static large objects are deleted too. With opts.DryRun, it only
+// returns the Plan.
+//
+// The Plan is also returned along with an error, with the executed actions
+// marked as Done.
+func Sync(c *gophercloud.ServiceClient, containerName string, opts Opts) (*Plan, error) {
+	plan, err := ComputePlan(c, containerName, opts)
+	if err != nil || opts.DryRun {
+		return plan, err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		todo    = make(chan int)
+		failure error
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range todo {
+				err := upload(c, containerName, plan.Actions[i], opts)
+
+				mu.Lock()
+				if err != nil && failure == nil {
+					failure = err
+				}
+				plan.Actions[i].Done = err == nil
+				mu.Unlock()
+			}
+		}()
+	}
+	var deletes []int
+	for i, action := range plan.Actions {
+		if action.Type == Delete {
+			deletes = append(deletes, i)
+			continue
+		}
+		mu.Lock()
+		failed := failure != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		todo <- i
+	}
+	close(todo)
+	wg.Wait()
+
+	// Objects are only deleted once all the files are uploaded, so that a
+	// failed synchronization doesn't leave the container emptier.
+	if failure != nil {
+		return plan, failure
+	}
+
+	for len(deletes) > 0 {
+		batch := deletes
+		if len(batch) > bulkDeleteSize {
+			batch = batch[:bulkDeleteSize]
+		}
+		deletes = deletes[len(batch):]
+
+		// The manifests list the segments, so they are read before being
+		// deleted.
+		var segments []string
+		names := make([]string, len(batch))
+		for j, i := range batch {
+			names[j] = plan.Actions[i].Name
+			if plan.Actions[i].largeObject {
+				paths, err := segmentPaths(c, containerName, names[j])
+				if err != nil {
+					return plan, err
+				}
+				segments = append(segments, paths...)
+			}
+		}
+		if err := bulkDelete(c, containerName, names); err != nil {
+			return plan, err
+		}
+		for _, i := range batch {
+			plan.Actions[i].Done = true
+		}
+		if err := deleteSegments(c, segments, nil); err != nil {
+			return plan, err
+		}
+	}
+
+	return plan, nil
+}
+
+func bulkDelete(c *gophercloud.ServiceClient, containerName string, names []string) error {
+	resp, err := objects.BulkDelete(c, containerName, names).Extract()
+	if err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return ErrBulkDelete{Errors: resp.Errors}
+	}
+	return nil
+}
+
+// segmentPaths returns the "<container>/<object>" paths of the segments of a
+// static large object, read from its manifest. A missing object has no
+// segments.
+func segmentPaths(c *gophercloud.ServiceClient, containerName, objectName string) ([]string, error) {
+	res := objects.Download(c, containerName, objectName, objects.DownloadOpts{MultipartManifest: "get"})
+	b, err := res.ExtractContent()
+	if _, ok := err.(gophercloud.ErrDefault404); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var segments []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(b, &segments); err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(segments))
+	for i, segment := range segments {
+		paths[i] = strings.TrimPrefix(segment.Name, "/")
+	}
+	return paths, nil
+}
+
+// deleteSegments bulk deletes the segments at paths which aren't in keep,
+// grouped by container. The segments already deleted are ignored.
+func deleteSegments(c *gophercloud.ServiceClient, paths []string, keep map[string]bool) error {
+	var containers []string
+	names := make(map[string][]string)
+	for _, p := range paths {
+		parts := strings.SplitN(p, "/", 2)
+		if len(parts) != 2 || keep[p] {
+			continue
+		}
+		if _, ok := names[parts[0]]; !ok {
+			containers = append(containers, parts[0])
+		}
+		names[parts[0]] = append(names[parts[0]], parts[1])
+	}
+
+	for _, container := range containers {
+		for batch := names[container]; len(batch) > 0; {
+			n := len(batch)
+			if n > bulkDeleteSize {
+				n = bulkDeleteSize
+			}
+			if err := bulkDelete(c, container, batch[:n]); err != nil {
+				return err
+			}
+			batch = batch[n:]
+		}
+	}
+	return nil
+}
+
+func upload(c *gophercloud.ServiceClient, containerName string, action Action, opts Opts) error {
+	f, err := os.Open(action.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// The segments of a replaced static large object are deleted once it is
+	// replaced, unless the new object still uses them.
+	var previous []string
+	if action.largeObject {
+		previous, err = segmentPaths(c, containerName, action.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	keep := make(map[string]bool)
+	size := segmentSize(opts)
+	if action.Bytes <= size {
+		err = objects.Create(c, containerName, action.Name, objects.CreateOpts{Content: f}).Err
+	} else {
+		// The files are already uploaded concurrently, so their segments are
+		// uploaded one at a time to hold at most one segment per file in
+		// memory. Resume skips the segments which didn't change since the last
+		// upload.
+		res := objects.Upload(c, containerName, action.Name, objects.UploadOpts{
+			Content:     f,
+			SegmentSize: size,
+			Concurrency: 1,
+			Resume:      true,
+		})
+		var segments []objects.Segment
+		segments, err = res.ExtractSegments()
+		for _, segment := range segments {
+			keep[segment.Path] = true
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return deleteSegments(c, previous, keep)
+}
@@ -0,0 +1,267 @@
+package testing
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	th "github.com/gophercloud/gophercloud/testhelper"
+	fake "github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+// FakeObject is an object of a FakeContainer.
+type FakeObject struct {
+	// Content is the content of the object, or the manifest of a static large
+	// object.
+	Content      []byte
+	LastModified time.Time
+
+	// Manifest lists the names of the segments of a static large object in
+	// the segment container.
+	Manifest []string
+}
+
+// FakeContainer is the in-memory container served by HandleContainerSuccessfully.
+type FakeContainer struct {
+	mu sync.Mutex
+
+	// Objects holds the objects of the container, by name.
+	Objects map[string]FakeObject
+
+	// Segments holds the content of the objects of the testContainer_segments
+	// container, by name.
+	Segments map[string][]byte
+
+	// Uploaded and Deleted list the names of the uploaded and deleted objects.
+	Uploaded []string
+	Deleted  []string
+
+	// SegmentUploads lists the names of the uploaded segments.
+	SegmentUploads []string
+}
+
+// content returns the content of an object, which for a static large object
+// is the concatenation of its segments.
+func (fc *FakeContainer) content(object FakeObject) []byte {
+	if object.Manifest == nil {
+		return object.Content
+	}
+	var content []byte
+	for _, name := range object.Manifest {
+		content = append(content, fc.Segments[name]...)
+	}
+	return content
+}
+
+// sloEtag returns the ETag of a static large object, which is the checksum of
+// the checksums of its segments.
+func (fc *FakeContainer) sloEtag(object FakeObject) string {
+	hash := md5.New()
+	for _, name := range object.Manifest {
+		fmt.Fprintf(hash, "%x", md5.Sum(fc.Segments[name]))
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// list writes the listing of the objects starting with the prefix of the
+// request, after its marker.
+func list(w http.ResponseWriter, r *http.Request, names []string, info func(name string) map[string]interface{}) {
+	r.ParseForm()
+	var selected []string
+	for _, name := range names {
+		if strings.HasPrefix(name, r.Form.Get("prefix")) && name > r.Form.Get("marker") {
+			selected = append(selected, name)
+		}
+	}
+	sort.Strings(selected)
+
+	list := make([]map[string]interface{}, 0, len(selected))
+	for _, name := range selected {
+		object := info(name)
+		object["name"] = name
+		object["content_type"] = "application/octet-stream"
+		list = append(list, object)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// HandleContainerSuccessfully creates HTTP handlers at `/testContainer`, `/testContainer_segments` and `/` on the
+// test handler mux that respond to the List, Create, Upload, Download and BulkDelete requests of a Sync from an
+// in-memory container holding objects.
+func HandleContainerSuccessfully(t *testing.T, objects map[string]FakeObject) *FakeContainer {
+	fc := &FakeContainer{Objects: objects, Segments: make(map[string][]byte)}
+
+	th.Mux.HandleFunc("/testContainer", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		th.TestHeader(t, r, "Accept", "application/json")
+		fc.mu.Lock()
+		defer fc.mu.Unlock()
+
+		var names []string
+		for name := range fc.Objects {
+			names = append(names, name)
+		}
+		// As with Swift, the hash of a static large object is the checksum of
+		// its manifest, and its ETag is listed apart.
+		list(w, r, names, func(name string) map[string]interface{} {
+			object := fc.Objects[name]
+			info := map[string]interface{}{
+				"bytes":         len(fc.content(object)),
+				"hash":          fmt.Sprintf("%x", md5.Sum(object.Content)),
+				"last_modified": object.LastModified.UTC().Format("2006-01-02T15:04:05.000000"),
+			}
+			if object.Manifest != nil {
+				info["slo_etag"] = `"` + fc.sloEtag(object) + `"`
+			}
+			return info
+		})
+	})
+
+	th.Mux.HandleFunc("/testContainer/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		name := strings.TrimPrefix(r.URL.Path, "/testContainer/")
+		fc.mu.Lock()
+		defer fc.mu.Unlock()
+
+		switch r.Method {
+		case "PUT":
+			data, err := ioutil.ReadAll(r.Body)
+			th.AssertNoErr(t, err)
+			object := FakeObject{Content: data, LastModified: time.Now()}
+			checksum := fmt.Sprintf("%x", md5.Sum(data))
+			if r.URL.Query().Get("multipart-manifest") == "put" {
+				var manifest []struct {
+					Path string `json:"path"`
+				}
+				th.AssertNoErr(t, json.Unmarshal(data, &manifest))
+				object.Manifest = []string{}
+				for _, segment := range manifest {
+					object.Manifest = append(object.Manifest, strings.TrimPrefix(segment.Path, "testContainer_segments/"))
+				}
+				checksum = fc.sloEtag(object)
+			}
+			th.TestHeader(t, r, "ETag", checksum)
+
+			fc.Objects[name] = object
+			fc.Uploaded = append(fc.Uploaded, name)
+			w.Header().Set("ETag", checksum)
+			w.WriteHeader(http.StatusCreated)
+		case "GET":
+			th.TestFormValues(t, r, map[string]string{"multipart-manifest": "get"})
+			object, ok := fc.Objects[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			manifest := []map[string]interface{}{}
+			for _, segment := range object.Manifest {
+				manifest = append(manifest, map[string]interface{}{
+					"name":  "/testContainer_segments/" + segment,
+					"hash":  fmt.Sprintf("%x", md5.Sum(fc.Segments[segment])),
+					"bytes": len(fc.Segments[segment]),
+				})
+			}
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			json.NewEncoder(w).Encode(manifest)
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	})
+
+	th.Mux.HandleFunc("/testContainer_segments", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		fc.mu.Lock()
+		defer fc.mu.Unlock()
+
+		switch r.Method {
+		case "PUT":
+			w.WriteHeader(http.StatusAccepted)
+		case "GET":
+			var names []string
+			for name := range fc.Segments {
+				names = append(names, name)
+			}
+			list(w, r, names, func(name string) map[string]interface{} {
+				return map[string]interface{}{
+					"bytes":         len(fc.Segments[name]),
+					"hash":          fmt.Sprintf("%x", md5.Sum(fc.Segments[name])),
+					"last_modified": "2016-08-17T22:11:58.602650",
+				}
+			})
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	})
+
+	th.Mux.HandleFunc("/testContainer_segments/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		name := strings.TrimPrefix(r.URL.Path, "/testContainer_segments/")
+		fc.mu.Lock()
+		defer fc.mu.Unlock()
+
+		switch r.Method {
+		case "PUT":
+			data, err := ioutil.ReadAll(r.Body)
+			th.AssertNoErr(t, err)
+			checksum := fmt.Sprintf("%x", md5.Sum(data))
+			th.TestHeader(t, r, "ETag", checksum)
+			fc.Segments[name] = data
+			fc.SegmentUploads = append(fc.SegmentUploads, name)
+			w.Header().Set("ETag", checksum)
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE":
+			if _, ok := fc.Segments[name]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(fc.Segments, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	})
+
+	th.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		th.TestFormValues(t, r, map[string]string{"bulk-delete": "true"})
+		data, err := ioutil.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+
+		fc.mu.Lock()
+		defer fc.mu.Unlock()
+		deleted, notFound := 0, 0
+		for _, path := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if name := strings.TrimPrefix(path, "testContainer_segments/"); name != path {
+				if _, ok := fc.Segments[name]; ok {
+					delete(fc.Segments, name)
+					deleted++
+				} else {
+					notFound++
+				}
+				continue
+			}
+			name := strings.TrimPrefix(path, "testContainer/")
+			if _, ok := fc.Objects[name]; ok {
+				delete(fc.Objects, name)
+				fc.Deleted = append(fc.Deleted, name)
+				deleted++
+			} else {
+				notFound++
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"Number Not Found": %d, "Response Status": "200 OK", "Errors": [], "Number Deleted": %d, "Response Body": ""}`, notFound, deleted)
+	})
+
+	return fc
+}
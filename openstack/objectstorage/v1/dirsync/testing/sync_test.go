@@ -0,0 +1,232 @@
+package testing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/dirsync"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	fake "github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+// setupLocalDir creates a directory holding files, by slash-separated path.
+func setupLocalDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "dirsync")
+	th.AssertNoErr(t, err)
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		th.AssertNoErr(t, os.MkdirAll(filepath.Dir(p), 0755))
+		th.AssertNoErr(t, ioutil.WriteFile(p, []byte(content), 0644))
+	}
+	return dir
+}
+
+func setupSync(t *testing.T) (string, *FakeContainer) {
+	dir := setupLocalDir(t, map[string]string{
+		"index.html":    "<html></html>",
+		"unchanged.txt": "unchanged",
+		"css/site.css":  "body {}",
+		"tmp/draft.tmp": "draft",
+	})
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	fc := HandleContainerSuccessfully(t, map[string]FakeObject{
+		"assets/unchanged.txt": {Content: []byte("unchanged"), LastModified: future},
+		// same size, older than the file
+		"assets/css/site.css": {Content: []byte("body{ }"), LastModified: past},
+		"assets/stale.js":     {Content: []byte("alert()"), LastModified: past},
+		// excluded, so left alone
+		"assets/keep.tmp": {Content: []byte("keep"), LastModified: past},
+		// outside of the prefix
+		"other/file.txt": {Content: []byte("other"), LastModified: past},
+	})
+	return dir, fc
+}
+
+func TestComputePlan(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	dir, fc := setupSync(t)
+	defer os.RemoveAll(dir)
+
+	opts := dirsync.Opts{
+		LocalDir: dir,
+		Prefix:   "assets/",
+		Exclude:  []string{"*.tmp"},
+		Delete:   true,
+	}
+	plan, err := dirsync.ComputePlan(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+
+	expected := &dirsync.Plan{
+		Actions: []dirsync.Action{
+			{Type: dirsync.Upload, Name: "assets/css/site.css", Path: filepath.Join(dir, "css", "site.css"), Bytes: 7, Reason: "modified"},
+			{Type: dirsync.Upload, Name: "assets/index.html", Path: filepath.Join(dir, "index.html"), Bytes: 13, Reason: "new"},
+			{Type: dirsync.Delete, Name: "assets/stale.js", Bytes: 7, Reason: "extraneous"},
+		},
+		Unchanged: []string{"assets/unchanged.txt"},
+	}
+	th.CheckDeepEquals(t, expected, plan)
+
+	// dry runs don't modify the container
+	opts.DryRun = true
+	plan, err = dirsync.Sync(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, expected, plan)
+	th.CheckEquals(t, 0, len(fc.Uploaded))
+	th.CheckEquals(t, 0, len(fc.Deleted))
+
+	// only the included files are compared
+	opts.Include = []string{"css/*"}
+	plan, err = dirsync.ComputePlan(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, expected.Actions[:1], plan.Actions)
+}
+
+func TestComputePlanChecksum(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	dir := setupLocalDir(t, map[string]string{"unchanged.txt": "unchanged"})
+	defer os.RemoveAll(dir)
+
+	// the object was modified after the file, but its content differs
+	HandleContainerSuccessfully(t, map[string]FakeObject{
+		"unchanged.txt": {Content: []byte("different"), LastModified: time.Now().Add(time.Hour)},
+	})
+
+	opts := dirsync.Opts{LocalDir: dir}
+	plan, err := dirsync.ComputePlan(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, 0, len(plan.Actions))
+
+	opts.Checksum = true
+	plan, err = dirsync.ComputePlan(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(plan.Actions))
+	th.CheckEquals(t, "modified", plan.Actions[0].Reason)
+}
+
+func TestSync(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	dir, fc := setupSync(t)
+	defer os.RemoveAll(dir)
+
+	opts := dirsync.Opts{
+		LocalDir:    dir,
+		Prefix:      "assets/",
+		Exclude:     []string{"*.tmp"},
+		Delete:      true,
+		Concurrency: 2,
+	}
+	plan, err := dirsync.Sync(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+
+	th.AssertEquals(t, 3, len(plan.Actions))
+	for _, action := range plan.Actions {
+		th.CheckEquals(t, true, action.Done)
+	}
+	th.CheckEquals(t, 2, len(plan.Uploads()))
+	th.CheckEquals(t, 1, len(plan.Deletes()))
+	th.CheckEquals(t, "body {}", string(fc.Objects["assets/css/site.css"].Content))
+	th.CheckDeepEquals(t, []string{"assets/stale.js"}, fc.Deleted)
+	_, ok := fc.Objects["assets/keep.tmp"]
+	th.CheckEquals(t, true, ok)
+
+	// the container now mirrors the directory
+	plan, err = dirsync.ComputePlan(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, 0, len(plan.Actions))
+	th.CheckEquals(t, 3, len(plan.Unchanged))
+}
+
+func TestSyncLargeFiles(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	dir := setupLocalDir(t, map[string]string{
+		"large.bin": "abcdefghij",
+		"small.txt": "abcd",
+	})
+	defer os.RemoveAll(dir)
+	fc := HandleContainerSuccessfully(t, map[string]FakeObject{})
+
+	opts := dirsync.Opts{LocalDir: dir, SegmentSize: 4}
+	_, err := dirsync.Sync(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, []string{
+		"large.bin/slo/4/00000000",
+		"large.bin/slo/4/00000001",
+		"large.bin/slo/4/00000002",
+	}, fc.Objects["large.bin"].Manifest)
+	th.CheckEquals(t, "abcdefghij", string(fc.content(fc.Objects["large.bin"])))
+	th.CheckEquals(t, "abcd", string(fc.Objects["small.txt"].Content))
+
+	// the large object is compared by the checksums of its segments
+	opts.Checksum = true
+	plan, err := dirsync.ComputePlan(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, 0, len(plan.Actions))
+	th.CheckDeepEquals(t, []string{"large.bin", "small.txt"}, plan.Unchanged)
+
+	// only the modified segment is uploaded again
+	th.AssertNoErr(t, ioutil.WriteFile(filepath.Join(dir, "large.bin"), []byte("abcdXfghij"), 0644))
+	fc.SegmentUploads = nil
+	plan, err = dirsync.Sync(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(plan.Actions))
+	th.CheckEquals(t, "modified", plan.Actions[0].Reason)
+	th.CheckEquals(t, "abcdXfghij", string(fc.content(fc.Objects["large.bin"])))
+	th.CheckDeepEquals(t, []string{"large.bin/slo/4/00000001"}, fc.SegmentUploads)
+}
+
+func TestSyncLargeFilesSegments(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	dir := setupLocalDir(t, map[string]string{
+		"resized.bin": "abcdefghij",
+		"shrunk.bin":  "abcdefghij",
+		"removed.bin": "abcdefghij",
+	})
+	defer os.RemoveAll(dir)
+	fc := HandleContainerSuccessfully(t, map[string]FakeObject{})
+
+	opts := dirsync.Opts{LocalDir: dir, SegmentSize: 4, Delete: true}
+	_, err := dirsync.Sync(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, 9, len(fc.Segments))
+
+	// the segments of the previous manifests are deleted
+	th.AssertNoErr(t, ioutil.WriteFile(filepath.Join(dir, "resized.bin"), []byte("abcdefghijk"), 0644))
+	th.AssertNoErr(t, ioutil.WriteFile(filepath.Join(dir, "shrunk.bin"), []byte("abc"), 0644))
+	th.AssertNoErr(t, os.Remove(filepath.Join(dir, "removed.bin")))
+	opts.SegmentSize = 6
+	plan, err := dirsync.Sync(fake.ServiceClient(), "testContainer", opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(plan.Actions))
+	th.CheckDeepEquals(t, []string{"removed.bin"}, fc.Deleted)
+	th.CheckEquals(t, "abc", string(fc.Objects["shrunk.bin"].Content))
+	th.CheckEquals(t, "abcdefghijk", string(fc.content(fc.Objects["resized.bin"])))
+
+	var segments []string
+	for name := range fc.Segments {
+		segments = append(segments, name)
+	}
+	sort.Strings(segments)
+	th.CheckDeepEquals(t, []string{
+		"resized.bin/slo/6/00000000",
+		"resized.bin/slo/6/00000001",
+	}, segments)
+}
+
+func TestSyncMissingLocalDir(t *testing.T) {
+	_, err := dirsync.Sync(fake.ServiceClient(), "testContainer", dirsync.Opts{})
+	if _, ok := err.(gophercloud.ErrMissingInput); !ok {
+		t.Fatalf("expected an ErrMissingInput, got %T: %v", err, err)
+	}
+}
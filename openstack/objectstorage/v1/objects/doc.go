@@ -68,6 +68,26 @@ Example to Create an Object
 		panic(err)
 	}
 
+Example to Upload a Large Object in Segments
+
+	file, err := os.Open("backup.tar")
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	uploadOpts := objects.UploadOpts{
+		Content:     file,
+		SegmentSize: 512 * 1024 * 1024,
+		Concurrency: 4,
+		Resume:      true,
+	}
+
+	segments, err := objects.Upload(objectStorageClient, "my_container", "backup.tar", uploadOpts).ExtractSegments()
+	if err != nil {
+		panic(err)
+	}
+
 Example to Copy an Object
 
 	objectName := "my_object"
@@ -106,5 +126,65 @@ Example to Download an Object's Data
 	if err != nil {
 		panic(err)
 	}
+
+Example to Download an Object with Concurrent Range Requests
+
+	file, err := os.Create("backup.tar")
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	downloadOpts := objects.DownloadToOpts{
+		Concurrency: 8,
+	}
+
+	state, err := objects.DownloadTo(objectStorageClient, "my_container", "backup.tar", file, downloadOpts).ExtractState()
+	if err != nil {
+		// the download can be resumed later with state as downloadOpts.Resume
+		panic(err)
+	}
+
+Example to Create a Temporary URL for the Objects Starting with a Prefix
+
+	tempURL, err := objects.CreateTempURL(objectStorageClient, "my_container", "backups/2020-07-01.tar", objects.CreateTempURLOpts{
+		Method:     objects.PUT,
+		TTL:        3600,
+		TempURLKey: "my_temp_url_key",
+		Digest:     "sha256",
+		Prefix:     "backups/",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+Example to Validate a Temporary URL
+
+	err := objects.ValidateTempURL(tempURL, objects.ValidateTempURLOpts{
+		Method:      objects.GET,
+		TempURLKeys: []string{"my_temp_url_key"},
+		RemoteIP:    "10.0.0.42",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+Example to Create the Fields of a Form Uploading Files to a Container
+
+	formPost, err := objects.CreateFormPost(objectStorageClient, "my_container", objects.CreateFormPostOpts{
+		Prefix:       "uploads/",
+		RedirectURL:  "https://example.com/uploaded",
+		MaxFileSize:  100 * 1024 * 1024,
+		MaxFileCount: 10,
+		TTL:          3600,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("<form action=%q method=\"POST\" enctype=\"multipart/form-data\">\n", formPost.URL)
+	for name, value := range formPost.Fields {
+		fmt.Printf("<input type=\"hidden\" name=%q value=%q />\n", name, value)
+	}
 */
 package objects
@@ -0,0 +1,346 @@
+package objects
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+const (
+	// DefaultPartSize is the size of the ranges downloaded by DownloadTo when
+	// DownloadToOpts.PartSize isn't set.
+	DefaultPartSize = 64 * 1024 * 1024
+
+	// DefaultDownloadConcurrency is the number of ranges downloaded at the same
+	// time when DownloadToOpts.Concurrency isn't set.
+	DefaultDownloadConcurrency = 4
+
+	// DefaultMaxPartAttempts is the number of times the download of a range is
+	// attempted when DownloadToOpts.MaxPartAttempts isn't set.
+	DefaultMaxPartAttempts = 3
+)
+
+// DownloadToOpts is a structure that holds parameters for downloading an
+// object with concurrent range requests.
+type DownloadToOpts struct {
+	// PartSize is the size of the ranges of regular objects. The segments of
+	// static large objects are downloaded with one request each, whatever
+	// their size. Defaults to DefaultPartSize.
+	PartSize int64
+
+	// Concurrency is the number of ranges downloaded at the same time. Defaults
+	// to DefaultDownloadConcurrency.
+	Concurrency int
+
+	// MaxPartAttempts is the number of times the download of a range is
+	// attempted before DownloadTo fails, including when its checksum doesn't
+	// match. Defaults to DefaultMaxPartAttempts.
+	MaxPartAttempts uint
+
+	// Resume is the state returned by a previous DownloadTo of the object into
+	// the same destination. Its parts aren't downloaded again, unless the
+	// object was modified since.
+	Resume *DownloadState
+}
+
+// DownloadPart is a range of an object downloaded by DownloadTo.
+type DownloadPart struct {
+	// Offset is the position of the range in the object.
+	Offset int64 `json:"offset"`
+
+	// Length is the size of the range.
+	Length int64 `json:"length"`
+
+	// Path is the container and name of the segment holding the range, for the
+	// static large objects.
+	Path string `json:"path,omitempty"`
+
+	// ETag is the MD5 checksum of the range, when it is known.
+	ETag string `json:"etag,omitempty"`
+}
+
+// DownloadState describes the parts of an object already downloaded by
+// DownloadTo. It can be persisted as JSON to resume the download later, see
+// DownloadToOpts.Resume.
+type DownloadState struct {
+	// ETag is the ETag of the object.
+	ETag string `json:"etag"`
+
+	// Parts are the completed parts, in order.
+	Parts []DownloadPart `json:"parts"`
+}
+
+// DownloadToResult represents the result of a DownloadTo. Its embedded
+// GetResult holds the headers of the object.
+type DownloadToResult struct {
+	GetResult
+	state DownloadState
+}
+
+// ExtractState returns the parts of the object which were downloaded. It is
+// returned along with the error of an interrupted download, to resume it.
+func (r DownloadToResult) ExtractState() (*DownloadState, error) {
+	return &r.state, r.Err
+}
+
+// sloSegment is a segment of the manifest of a static large object.
+type sloSegment struct {
+	Name   string `json:"name"`
+	Hash   string `json:"hash"`
+	Bytes  int64  `json:"bytes"`
+	Range  string `json:"range"`
+	SubSLO bool   `json:"sub_slo"`
+}
+
+// offsetWriter writes sequentially into an io.WriterAt from an offset.
+type offsetWriter struct {
+	w      io.WriterAt
+	offset int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.w.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	return n, err
+}
+
+// downloadPart downloads a part of an object into w, and verifies its
+// checksum if it is known.
+func downloadPart(c *gophercloud.ServiceClient, containerName, objectName string, w io.WriterAt, part DownloadPart, size int64, ifMatch string) error {
+	if part.Path != "" {
+		pathParts := strings.SplitN(part.Path, "/", 2)
+		containerName, objectName = pathParts[0], pathParts[1]
+		ifMatch = part.ETag
+	}
+
+	opts := DownloadOpts{IfMatch: ifMatch}
+	partial := part.Path == "" && part.Length != size
+	if partial {
+		opts.Range = fmt.Sprintf("bytes=%d-%d", part.Offset, part.Offset+part.Length-1)
+	}
+	res := Download(c, containerName, objectName, opts)
+	if res.Err != nil {
+		return res.Err
+	}
+	defer res.Body.Close()
+
+	if partial && res.Header.Get("Content-Range") == "" {
+		return fmt.Errorf("The range %s of the object was not honored", opts.Range)
+	}
+
+	var h hash.Hash
+	dst := io.Writer(&offsetWriter{w: w, offset: part.Offset})
+	if part.ETag != "" {
+		h = md5.New()
+		dst = io.MultiWriter(dst, h)
+	}
+	if _, err := io.CopyN(dst, res.Body, part.Length); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if h != nil && fmt.Sprintf("%x", h.Sum(nil)) != part.ETag {
+		return ErrWrongChecksum{}
+	}
+	return nil
+}
+
+// DownloadTo downloads an object into w with up to opts.Concurrency range
+// requests at the same time. Regular objects are split in ranges of
+// opts.PartSize bytes, while the segments of static large objects are
+// downloaded from their segment container, and verified against the
+// checksums of the manifest.
+//
+// The checksum of a regular object split in several ranges can only be
+// verified once all of them are written, by reading w back: it is verified if
+// w implements io.ReaderAt, like *os.File. The content of dynamic large
+// objects isn't verified.
+//
+// A failed download can be resumed by passing the state returned by
+// ExtractState in opts.Resume.
+func DownloadTo(c *gophercloud.ServiceClient, containerName, objectName string, w io.WriterAt, opts DownloadToOpts) (r DownloadToResult) {
+	if opts.PartSize < 0 {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "objects.DownloadToOpts.PartSize"
+		err.Value = opts.PartSize
+		r.Err = err
+		return
+	}
+	if opts.PartSize == 0 {
+		opts.PartSize = DefaultPartSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultDownloadConcurrency
+	}
+	if opts.MaxPartAttempts == 0 {
+		opts.MaxPartAttempts = DefaultMaxPartAttempts
+	}
+
+	r.GetResult = Get(c, containerName, objectName, nil)
+	header, err := r.GetResult.Extract()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	etag := strings.Trim(header.ETag, `"`)
+	r.state.ETag = etag
+
+	var parts []DownloadPart
+	if header.StaticLargeObject {
+		parts, err = sloParts(c, containerName, objectName, etag)
+		if err != nil {
+			r.Err = err
+			return
+		}
+	}
+	// Static large objects with ranges of segments or nested manifests are
+	// downloaded as regular objects, without checksums.
+	verify := !header.StaticLargeObject && header.ObjectManifest == ""
+	ifMatch := ""
+	if verify {
+		ifMatch = etag
+	}
+	if parts == nil {
+		for offset := int64(0); offset < header.ContentLength; offset += opts.PartSize {
+			part := DownloadPart{Offset: offset, Length: opts.PartSize}
+			if offset+part.Length > header.ContentLength {
+				part.Length = header.ContentLength - offset
+			}
+			parts = append(parts, part)
+		}
+		// the checksum of a single range is the checksum of the object
+		if verify && len(parts) == 1 {
+			parts[0].ETag = etag
+			verify = false
+		}
+	}
+
+	var completed map[DownloadPart]bool
+	if opts.Resume != nil && opts.Resume.ETag == etag {
+		completed = make(map[DownloadPart]bool, len(opts.Resume.Parts))
+		for _, part := range opts.Resume.Parts {
+			completed[part] = true
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		todo    = make(chan DownloadPart)
+		failure error
+	)
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for part := range todo {
+				var err error
+				for attempt := uint(0); attempt < opts.MaxPartAttempts; attempt++ {
+					err = downloadPart(c, containerName, objectName, w, part, header.ContentLength, ifMatch)
+					if err == nil {
+						break
+					}
+					if gophercloud.ResponseCodeIs(err, http.StatusPreconditionFailed) {
+						// the object was modified
+						break
+					}
+				}
+
+				mu.Lock()
+				if err != nil {
+					if failure == nil {
+						failure = err
+					}
+				} else {
+					r.state.Parts = append(r.state.Parts, part)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, part := range parts {
+		mu.Lock()
+		failed := failure != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		if completed[part] {
+			mu.Lock()
+			r.state.Parts = append(r.state.Parts, part)
+			mu.Unlock()
+			continue
+		}
+		todo <- part
+	}
+	close(todo)
+	wg.Wait()
+
+	sort.Slice(r.state.Parts, func(i, j int) bool {
+		return r.state.Parts[i].Offset < r.state.Parts[j].Offset
+	})
+	if failure != nil {
+		r.Err = failure
+		return
+	}
+
+	if ra, ok := w.(io.ReaderAt); ok && verify {
+		h := md5.New()
+		if _, err := io.Copy(h, io.NewSectionReader(ra, 0, header.ContentLength)); err != nil {
+			r.Err = err
+			return
+		}
+		if fmt.Sprintf("%x", h.Sum(nil)) != etag {
+			r.Err = ErrWrongChecksum{}
+		}
+	}
+
+	return
+}
+
+// sloParts returns the parts of a static large object from its manifest, or
+// nil if some of its segments can't be downloaded on their own.
+func sloParts(c *gophercloud.ServiceClient, containerName, objectName, etag string) ([]DownloadPart, error) {
+	res := Download(c, containerName, objectName, DownloadOpts{MultipartManifest: "get"})
+	b, err := res.ExtractContent()
+	if err != nil {
+		return nil, err
+	}
+	var segments []sloSegment
+	if err := json.Unmarshal(b, &segments); err != nil {
+		return nil, err
+	}
+
+	parts := make([]DownloadPart, 0, len(segments))
+	h := md5.New()
+	offset := int64(0)
+	for _, segment := range segments {
+		if segment.Range != "" || segment.SubSLO {
+			return nil, nil
+		}
+		parts = append(parts, DownloadPart{
+			Offset: offset,
+			Length: segment.Bytes,
+			Path:   strings.TrimPrefix(segment.Name, "/"),
+			ETag:   segment.Hash,
+		})
+		io.WriteString(h, segment.Hash)
+		offset += segment.Bytes
+	}
+
+	// The ETag of a static large object is the checksum of the checksums of
+	// its segments.
+	if fmt.Sprintf("%x", h.Sum(nil)) != etag {
+		return nil, ErrWrongChecksum{}
+	}
+	return parts, nil
+}
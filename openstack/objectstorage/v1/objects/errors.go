@@ -11,3 +11,14 @@ type ErrWrongChecksum struct {
 func (e ErrWrongChecksum) Error() string {
 	return "Local checksum does not match API ETag header"
 }
+
+// ErrInvalidTempURL is the error when a temporary URL is malformed, expired,
+// or not allowed for a request.
+type ErrInvalidTempURL struct {
+	gophercloud.BaseError
+	Reason string
+}
+
+func (e ErrInvalidTempURL) Error() string {
+	return "Invalid temporary URL: " + e.Reason
+}
@@ -0,0 +1,123 @@
+package objects
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// CreateFormPostOpts are options for creating the form fields of an upload
+// through the FormPOST middleware.
+type CreateFormPostOpts struct {
+	// Prefix is the prefix of the names of the uploaded objects. Optional.
+	Prefix string
+
+	// RedirectURL is the URL the browser is redirected to once the upload is
+	// done. Optional. If empty, the status of the upload is returned as text.
+	RedirectURL string
+
+	// (REQUIRED) MaxFileSize is the maximum size of an uploaded file, in bytes.
+	MaxFileSize int64
+
+	// (REQUIRED) MaxFileCount is the maximum number of files of an upload.
+	MaxFileCount int
+
+	// (REQUIRED) TTL is the number of seconds the form should be valid.
+	TTL int
+
+	// Timestamp is a timestamp to calculate the expiry of the form. Optional.
+	Timestamp time.Time
+
+	// TempURLKey is the key used to sign the form. Optional. If empty, the key
+	// of the container, or else the key of the account, is fetched.
+	TempURLKey string
+
+	// Digest is the digest algorithm of the signature: "sha1", "sha256" or
+	// "sha512". Optional. Defaults to "sha1".
+	Digest string
+
+	// (Optional) Split is the string on which to split the container URL, as
+	// in CreateTempURLOpts. Defaults to "/v1/".
+	Split string
+}
+
+// FormPost holds the action and the hidden fields of an HTML form uploading
+// files to a container.
+type FormPost struct {
+	// URL is the action of the form, to which the form is POSTed as
+	// multipart/form-data.
+	URL string
+
+	// Fields are the hidden fields of the form. They must precede the file
+	// fields in the request.
+	Fields map[string]string
+
+	// Signature is the signature of the form, also in Fields.
+	Signature string
+
+	// Expires is the Unix timestamp at which the form expires, also in Fields.
+	Expires int64
+}
+
+// CreateFormPost is a function for creating the fields of an HTML form which
+// allows browsers to upload files directly to a container, under
+// opts.Prefix, until the form expires.
+func CreateFormPost(c *gophercloud.ServiceClient, containerName string, opts CreateFormPostOpts) (*FormPost, error) {
+	if opts.Split == "" {
+		opts.Split = "/v1/"
+	}
+	if opts.MaxFileSize <= 0 {
+		return nil, gophercloud.ErrMissingInput{Argument: "objects.CreateFormPostOpts.MaxFileSize"}
+	}
+	if opts.MaxFileCount <= 0 {
+		return nil, gophercloud.ErrMissingInput{Argument: "objects.CreateFormPostOpts.MaxFileCount"}
+	}
+	newHash, err := tempURLDigest("objects.CreateFormPostOpts.Digest", opts.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	date := opts.Timestamp
+	if date.IsZero() {
+		date = time.Now().UTC()
+	}
+	expiry := date.Add(time.Duration(opts.TTL) * time.Second).Unix()
+
+	tempURLKey, err := getTempURLKey(c, containerName, opts.TempURLKey)
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.ServiceURL(containerName, opts.Prefix)
+	splitPath := strings.SplitN(url, opts.Split, 2)
+	if len(splitPath) != 2 {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "objects.CreateFormPostOpts.Split"
+		err.Value = opts.Split
+		err.Info = "Split must be part of the container URL"
+		return nil, err
+	}
+	path := opts.Split + splitPath[1]
+
+	body := fmt.Sprintf("%s\n%s\n%d\n%d\n%d", path, opts.RedirectURL, opts.MaxFileSize, opts.MaxFileCount, expiry)
+	sig := signTempURL(newHash, opts.Digest, tempURLKey, body)
+
+	fields := map[string]string{
+		"max_file_size":  fmt.Sprintf("%d", opts.MaxFileSize),
+		"max_file_count": fmt.Sprintf("%d", opts.MaxFileCount),
+		"expires":        fmt.Sprintf("%d", expiry),
+		"signature":      sig,
+	}
+	if opts.RedirectURL != "" {
+		fields["redirect"] = opts.RedirectURL
+	}
+
+	return &FormPost{
+		URL:       url,
+		Fields:    fields,
+		Signature: sig,
+		Expires:   expiry,
+	}, nil
+}
@@ -2,18 +2,15 @@ package objects
 
 import (
 	"bytes"
-	"crypto/hmac"
 	"crypto/md5"
-	"crypto/sha1"
 	"fmt"
 	"io"
 	"io/ioutil"
+	neturl "net/url"
 	"strings"
 	"time"
 
 	"github.com/gophercloud/gophercloud"
-	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/accounts"
-	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
 	"github.com/gophercloud/gophercloud/pagination"
 )
 
@@ -448,14 +445,24 @@ var (
 	// GET represents an HTTP "GET" method.
 	GET HTTPMethod = "GET"
 
+	// HEAD represents an HTTP "HEAD" method.
+	HEAD HTTPMethod = "HEAD"
+
+	// PUT represents an HTTP "PUT" method.
+	PUT HTTPMethod = "PUT"
+
 	// POST represents an HTTP "POST" method.
 	POST HTTPMethod = "POST"
+
+	// DELETE represents an HTTP "DELETE" method.
+	DELETE HTTPMethod = "DELETE"
 )
 
 // CreateTempURLOpts are options for creating a temporary URL for an object.
 type CreateTempURLOpts struct {
 	// (REQUIRED) Method is the HTTP method to allow for users of the temp URL.
-	// Valid values are "GET" and "POST".
+	// Valid values are "GET", "HEAD", "PUT", "POST" and "DELETE". A temp URL
+	// allowing "GET", "PUT" or "POST" also allows "HEAD".
 	Method HTTPMethod
 
 	// (REQUIRED) TTL is the number of seconds the temp URL should be active.
@@ -468,16 +475,62 @@ type CreateTempURLOpts struct {
 
 	// Timestamp is a timestamp to calculate Temp URL signature. Optional.
 	Timestamp time.Time
+
+	// TempURLKey is the key used to sign the temp URL. Optional. If empty, the
+	// key of the container, or else the key of the account, is fetched.
+	TempURLKey string
+
+	// Digest is the digest algorithm of the signature: "sha1", "sha256" or
+	// "sha512". Optional. Defaults to "sha1". The SHA-2 signatures are encoded
+	// as "<digest>:<base64>".
+	Digest string
+
+	// Prefix makes the temp URL valid for all the objects of the container
+	// whose name starts with Prefix, which must be a prefix of the name of the
+	// object. Optional.
+	Prefix string
+
+	// IPRange restricts the temp URL to the clients whose address is IPRange,
+	// or belongs to it if it is a CIDR. Optional.
+	IPRange string
+
+	// ISO8601 formats the expiry of the temp URL as an ISO 8601 UTC date
+	// instead of a Unix timestamp. Optional.
+	ISO8601 bool
 }
 
 // CreateTempURL is a function for creating a temporary URL for an object. It
-// allows users to have "GET" or "POST" access to a particular tenant's object
-// for a limited amount of time.
+// allows users to access a particular tenant's object, or the objects of a
+// container starting with a prefix, with a given HTTP method for a limited
+// amount of time.
 func CreateTempURL(c *gophercloud.ServiceClient, containerName, objectName string, opts CreateTempURLOpts) (string, error) {
 	if opts.Split == "" {
 		opts.Split = "/v1/"
 	}
 
+	if opts.Prefix != "" && !strings.HasPrefix(objectName, opts.Prefix) {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "objects.CreateTempURLOpts.Prefix"
+		err.Value = opts.Prefix
+		err.Info = "Prefix must be a prefix of the object name"
+		return "", err
+	}
+	newHash, err := tempURLDigest("objects.CreateTempURLOpts.Digest", opts.Digest)
+	if err != nil {
+		return "", err
+	}
+
+	url := getURL(c, containerName, objectName)
+	splitPath := strings.SplitN(url, opts.Split, 2)
+	if len(splitPath) != 2 {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "objects.CreateTempURLOpts.Split"
+		err.Value = opts.Split
+		err.Info = "Split must be part of the object URL"
+		return "", err
+	}
+	baseURL, objectPath := splitPath[0], opts.Split+splitPath[1]
+
 	// Initialize time if it was not passed as opts
 	var date time.Time
 	if opts.Timestamp.IsZero() {
@@ -488,29 +541,31 @@ func CreateTempURL(c *gophercloud.ServiceClient, containerName, objectName strin
 
 	duration := time.Duration(opts.TTL) * time.Second
 	expiry := date.Add(duration).Unix()
-	getHeader, err := containers.Get(c, containerName, nil).Extract()
+	tempURLKey, err := getTempURLKey(c, containerName, opts.TempURLKey)
 	if err != nil {
 		return "", err
 	}
-	tempURLKey := getHeader.TempURLKey
-	if tempURLKey == "" {
-		// fallback to an account TempURL key
-		getHeader, err := accounts.Get(c, nil).Extract()
-		if err != nil {
-			return "", err
-		}
-		tempURLKey = getHeader.TempURLKey
+
+	signedPath := objectPath
+	if opts.Prefix != "" {
+		signedPath = "prefix:" + strings.TrimSuffix(objectPath, objectName) + opts.Prefix
 	}
-	secretKey := []byte(tempURLKey)
-	url := getURL(c, containerName, objectName)
-	splitPath := strings.Split(url, opts.Split)
-	baseURL, objectPath := splitPath[0], splitPath[1]
-	objectPath = opts.Split + objectPath
-	body := fmt.Sprintf("%s\n%d\n%s", opts.Method, expiry, objectPath)
-	hash := hmac.New(sha1.New, secretKey)
-	hash.Write([]byte(body))
-	hexsum := fmt.Sprintf("%x", hash.Sum(nil))
-	return fmt.Sprintf("%s%s?temp_url_sig=%s&temp_url_expires=%d", baseURL, objectPath, hexsum, expiry), nil
+	body := tempURLBody(opts.Method, expiry, signedPath, opts.IPRange)
+	sig := signTempURL(newHash, opts.Digest, tempURLKey, body)
+
+	expires := fmt.Sprintf("%d", expiry)
+	if opts.ISO8601 {
+		expires = time.Unix(expiry, 0).UTC().Format(tempURLISO8601)
+	}
+
+	tempURL := fmt.Sprintf("%s%s?temp_url_sig=%s&temp_url_expires=%s", baseURL, objectPath, neturl.QueryEscape(sig), neturl.QueryEscape(expires))
+	if opts.Prefix != "" {
+		tempURL += "&temp_url_prefix=" + neturl.QueryEscape(opts.Prefix)
+	}
+	if opts.IPRange != "" {
+		tempURL += "&temp_url_ip_range=" + neturl.QueryEscape(opts.IPRange)
+	}
+	return tempURL, nil
 }
 
 // BulkDelete is a function that bulk deletes objects.
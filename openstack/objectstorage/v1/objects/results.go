@@ -24,6 +24,11 @@ type Object struct {
 	// Hash represents the MD5 checksum value of the object's content.
 	Hash string `json:"hash"`
 
+	// SLOEtag is the quoted ETag of a static large object, which is the MD5
+	// checksum of the checksums of its segments. Hash is then the checksum of
+	// its manifest. It is empty for the other objects.
+	SLOEtag string `json:"slo_etag"`
+
 	// LastModified is the time the object was last modified.
 	LastModified time.Time `json:"-"`
 
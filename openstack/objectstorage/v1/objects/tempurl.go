@@ -0,0 +1,238 @@
+package objects
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/accounts"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+)
+
+// tempURLISO8601 is the ISO 8601 format of the expiry of the temp URLs.
+const tempURLISO8601 = "2006-01-02T15:04:05Z"
+
+// tempURLDigest returns the hash function of a temp URL digest algorithm.
+// argument names the option holding digest in the returned error.
+func tempURLDigest(argument, digest string) (func() hash.Hash, error) {
+	switch digest {
+	case "", "sha1":
+		return sha1.New, nil
+	case "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	}
+	err := gophercloud.ErrInvalidInput{}
+	err.Argument = argument
+	err.Value = digest
+	err.Info = `Digest must be "sha1", "sha256" or "sha512"`
+	return nil, err
+}
+
+// getTempURLKey returns key if it isn't empty, or else the temp URL key of the
+// container, or else the temp URL key of the account.
+func getTempURLKey(c *gophercloud.ServiceClient, containerName, key string) (string, error) {
+	if key != "" {
+		return key, nil
+	}
+
+	getHeader, err := containers.Get(c, containerName, nil).Extract()
+	if err != nil {
+		return "", err
+	}
+	if getHeader.TempURLKey != "" {
+		return getHeader.TempURLKey, nil
+	}
+
+	// fallback to an account TempURL key
+	accountHeader, err := accounts.Get(c, nil).Extract()
+	if err != nil {
+		return "", err
+	}
+	return accountHeader.TempURLKey, nil
+}
+
+// signTempURL returns the signature of body with key, the way the tempurl and
+// formpost middlewares expect it: hexadecimal for SHA-1, and
+// "<digest>:<base64>" for SHA-2. newHash is the hash function of digest, see
+// tempURLDigest.
+func signTempURL(newHash func() hash.Hash, digest, key, body string) string {
+	hash := hmac.New(newHash, []byte(key))
+	hash.Write([]byte(body))
+	if digest == "" || digest == "sha1" {
+		return fmt.Sprintf("%x", hash.Sum(nil))
+	}
+	return digest + ":" + base64.URLEncoding.EncodeToString(hash.Sum(nil))
+}
+
+// tempURLBody returns the message signed by a temp URL.
+func tempURLBody(method HTTPMethod, expiry int64, path, ipRange string) string {
+	body := fmt.Sprintf("%s\n%d\n%s", method, expiry, path)
+	if ipRange != "" {
+		body = "ip=" + ipRange + "\n" + body
+	}
+	return body
+}
+
+// ValidateTempURLOpts are options for validating a temporary URL.
+type ValidateTempURLOpts struct {
+	// (REQUIRED) Method is the HTTP method of the request using the temp URL.
+	Method HTTPMethod
+
+	// (REQUIRED) TempURLKeys are the keys which may have signed the temp URL.
+	TempURLKeys []string
+
+	// (Optional) Split is the string on which the path of the temp URL is split
+	// to find the object path, as in CreateTempURLOpts. Defaults to "/v1/".
+	Split string
+
+	// Timestamp is the time at which the temp URL is used. Optional. Defaults
+	// to the current time.
+	Timestamp time.Time
+
+	// RemoteIP is the address of the client using the temp URL. It is required
+	// if the temp URL is restricted to an IP range.
+	RemoteIP string
+}
+
+// ValidateTempURL checks that a temporary URL created by CreateTempURL, or by
+// another client, allows a request the way the Object Storage service would.
+// It returns an ErrInvalidTempURL if the temp URL is malformed, expired,
+// restricted to another IP range, or if its signature doesn't match any of the
+// keys for the method of the request.
+func ValidateTempURL(tempURL string, opts ValidateTempURLOpts) error {
+	if opts.Split == "" {
+		opts.Split = "/v1/"
+	}
+	if opts.Timestamp.IsZero() {
+		opts.Timestamp = time.Now()
+	}
+
+	invalid := func(format string, args ...interface{}) error {
+		return ErrInvalidTempURL{Reason: fmt.Sprintf(format, args...)}
+	}
+
+	u, err := url.Parse(tempURL)
+	if err != nil {
+		return invalid("%v", err)
+	}
+	query := u.Query()
+
+	sig := query.Get("temp_url_sig")
+	if sig == "" {
+		return invalid("missing temp_url_sig")
+	}
+
+	expires := query.Get("temp_url_expires")
+	expiry, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		t, err := time.Parse(tempURLISO8601, expires)
+		if err != nil {
+			return invalid("malformed temp_url_expires %q", expires)
+		}
+		expiry = t.Unix()
+	}
+	if opts.Timestamp.Unix() >= expiry {
+		return invalid("expired at %s", time.Unix(expiry, 0).UTC().Format(tempURLISO8601))
+	}
+
+	splitPath := strings.SplitN(u.Path, opts.Split, 2)
+	if len(splitPath) != 2 {
+		return invalid("the path doesn't contain %q", opts.Split)
+	}
+	objectPath := opts.Split + splitPath[1]
+	// account, container and object
+	pathParts := strings.SplitN(splitPath[1], "/", 3)
+	if len(pathParts) != 3 || pathParts[2] == "" {
+		return invalid("the path isn't the path of an object")
+	}
+
+	signedPath := objectPath
+	if query["temp_url_prefix"] != nil {
+		prefix := query.Get("temp_url_prefix")
+		if !strings.HasPrefix(pathParts[2], prefix) {
+			return invalid("the object doesn't start with the prefix %q", prefix)
+		}
+		signedPath = "prefix:" + strings.TrimSuffix(objectPath, pathParts[2]) + prefix
+	}
+
+	ipRange := query.Get("temp_url_ip_range")
+	if ipRange != "" {
+		ip := net.ParseIP(opts.RemoteIP)
+		if ip == nil {
+			return invalid("restricted to %s, and the address of the client is unknown", ipRange)
+		}
+		allowed := ip.Equal(net.ParseIP(ipRange))
+		if _, ipNet, err := net.ParseCIDR(ipRange); err == nil {
+			allowed = ipNet.Contains(ip)
+		}
+		if !allowed {
+			return invalid("restricted to %s", ipRange)
+		}
+	}
+
+	digest, mac, err := decodeTempURLSignature(sig)
+	if err != nil {
+		return invalid("malformed temp_url_sig: %v", err)
+	}
+	newHash, err := tempURLDigest("temp_url_sig", digest)
+	if err != nil {
+		return invalid("unsupported digest %q", digest)
+	}
+
+	// a temp URL allowing GET, PUT or POST also allows HEAD
+	methods := []HTTPMethod{opts.Method}
+	if opts.Method == HEAD {
+		methods = append(methods, GET, PUT, POST)
+	}
+	for _, key := range opts.TempURLKeys {
+		for _, method := range methods {
+			hash := hmac.New(newHash, []byte(key))
+			hash.Write([]byte(tempURLBody(method, expiry, signedPath, ipRange)))
+			if hmac.Equal(hash.Sum(nil), mac) {
+				return nil
+			}
+		}
+	}
+	return invalid("the signature doesn't match")
+}
+
+// decodeTempURLSignature returns the digest algorithm and the HMAC of a temp
+// URL signature, which is either "<digest>:<base64>" or the hexadecimal HMAC,
+// whose length gives the digest algorithm.
+func decodeTempURLSignature(sig string) (string, []byte, error) {
+	if i := strings.Index(sig, ":"); i >= 0 {
+		digest, encoded := sig[:i], sig[i+1:]
+		// both the standard and the URL-safe encodings are accepted, with or
+		// without padding
+		encoded = strings.NewReplacer("-", "+", "_", "/").Replace(strings.TrimRight(encoded, "="))
+		mac, err := base64.RawStdEncoding.DecodeString(encoded)
+		return digest, mac, err
+	}
+
+	mac, err := hex.DecodeString(sig)
+	if err != nil {
+		return "", nil, err
+	}
+	switch len(mac) {
+	case sha1.Size:
+		return "sha1", mac, nil
+	case sha256.Size:
+		return "sha256", mac, nil
+	case sha512.Size:
+		return "sha512", mac, nil
+	}
+	return "", nil, fmt.Errorf("unexpected length %d", len(sig))
+}
@@ -0,0 +1,141 @@
+package testing
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	fake "github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+// memFile is an in-memory io.WriterAt and io.ReaderAt.
+type memFile struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (f *memFile) WriteAt(p []byte, off int64) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for int64(len(f.data)) < off+int64(len(p)) {
+		f.data = append(f.data, 0)
+	}
+	return copy(f.data[off:], p), nil
+}
+
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return copy(p, f.data[off:]), nil
+}
+
+func TestDownloadToRanges(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	store := HandleDownloadToSuccessfully(t, map[string][]byte{
+		"/testContainer/testObject": []byte("abcdefghij"),
+	}, nil)
+
+	f := &memFile{}
+	res := objects.DownloadTo(fake.ServiceClient(), "testContainer", "testObject", f, objects.DownloadToOpts{
+		PartSize:    4,
+		Concurrency: 2,
+	})
+	th.AssertNoErr(t, res.Err)
+	th.CheckEquals(t, "abcdefghij", string(f.data))
+	th.CheckEquals(t, 3, len(store.Downloads["/testContainer/testObject"]))
+
+	state, err := res.ExtractState()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, &objects.DownloadState{
+		ETag: checksum("abcdefghij"),
+		Parts: []objects.DownloadPart{
+			{Offset: 0, Length: 4},
+			{Offset: 4, Length: 4},
+			{Offset: 8, Length: 2},
+		},
+	}, state)
+
+	// the checksum of the object is verified by reading it back
+	store.CorruptDownloads = 1
+	res = objects.DownloadTo(fake.ServiceClient(), "testContainer", "testObject", &memFile{}, objects.DownloadToOpts{
+		PartSize: 4,
+	})
+	if _, ok := res.Err.(objects.ErrWrongChecksum); !ok {
+		t.Fatalf("expected an ErrWrongChecksum, got %T: %v", res.Err, res.Err)
+	}
+}
+
+func TestDownloadToStaticLargeObject(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	store := HandleDownloadToSuccessfully(t, map[string][]byte{
+		"/testContainer_segments/testSLO/00000000": []byte("abcd"),
+		"/testContainer_segments/testSLO/00000001": []byte("efgh"),
+		"/testContainer_segments/testSLO/00000002": []byte("ij"),
+	}, map[string][]string{
+		"/testContainer/testSLO": {
+			"/testContainer_segments/testSLO/00000000",
+			"/testContainer_segments/testSLO/00000001",
+			"/testContainer_segments/testSLO/00000002",
+		},
+	})
+	// the corrupted segment is downloaded again
+	store.CorruptDownloads = 1
+
+	f := &memFile{}
+	res := objects.DownloadTo(fake.ServiceClient(), "testContainer", "testSLO", f, objects.DownloadToOpts{})
+	th.AssertNoErr(t, res.Err)
+	th.CheckEquals(t, "abcdefghij", string(f.data))
+
+	state, err := res.ExtractState()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(state.Parts))
+	th.CheckDeepEquals(t, objects.DownloadPart{
+		Offset: 8,
+		Length: 2,
+		Path:   "testContainer_segments/testSLO/00000002",
+		ETag:   checksum("ij"),
+	}, state.Parts[2])
+
+	downloads := 0
+	for _, ranges := range store.Downloads {
+		downloads += len(ranges)
+	}
+	th.CheckEquals(t, 4, downloads)
+	th.CheckEquals(t, 0, len(store.Downloads["/testContainer/testSLO"]))
+}
+
+func TestDownloadToResume(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	store := HandleDownloadToSuccessfully(t, map[string][]byte{
+		"/testContainer/testObject": []byte("abcdefghij"),
+	}, nil)
+
+	f := &memFile{data: []byte("abcd")}
+	resume := &objects.DownloadState{
+		ETag:  checksum("abcdefghij"),
+		Parts: []objects.DownloadPart{{Offset: 0, Length: 4}},
+	}
+	res := objects.DownloadTo(fake.ServiceClient(), "testContainer", "testObject", f, objects.DownloadToOpts{
+		PartSize:    4,
+		Concurrency: 1,
+		Resume:      resume,
+	})
+	th.AssertNoErr(t, res.Err)
+	th.CheckEquals(t, "abcdefghij", string(f.data))
+	th.CheckDeepEquals(t, []string{"bytes=4-7", "bytes=8-9"}, store.Downloads["/testContainer/testObject"])
+
+	// the state of another version of the object is ignored
+	store.Downloads = make(map[string][]string)
+	resume.ETag = checksum("jihgfedcba")
+	res = objects.DownloadTo(fake.ServiceClient(), "testContainer", "testObject", &memFile{}, objects.DownloadToOpts{
+		PartSize:    4,
+		Concurrency: 1,
+		Resume:      resume,
+	})
+	th.AssertNoErr(t, res.Err)
+	th.CheckDeepEquals(t, []string{"bytes=0-3", "bytes=4-7", "bytes=8-9"}, store.Downloads["/testContainer/testObject"])
+}
@@ -2,9 +2,14 @@ package testing
 
 import (
 	"crypto/md5"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -306,3 +311,236 @@ func HandleGetObjectSuccessfully(t *testing.T) {
 		w.WriteHeader(http.StatusNoContent)
 	})
 }
+
+// FakeSegmentContainer records the requests handled by
+// HandleUploadSuccessfully.
+type FakeSegmentContainer struct {
+	mu sync.Mutex
+
+	// Segments holds the content of the segments, by name.
+	Segments map[string][]byte
+
+	// Uploads counts the PUT requests of segments.
+	Uploads int
+
+	// WrongETags is the number of segment uploads which are answered with a
+	// wrong ETag.
+	WrongETags int
+
+	// Deleted lists the names of the deleted segments.
+	Deleted []string
+
+	// ManifestHeader and ManifestBody are the headers and the body of the
+	// request creating the manifest.
+	ManifestHeader http.Header
+	ManifestBody   []byte
+}
+
+// HandleUploadSuccessfully creates HTTP handlers at `/testContainer_segments` and `/testContainer/testObject` on the
+// test handler mux that respond to the requests of an `Upload` from an in-memory segment container holding segments.
+func HandleUploadSuccessfully(t *testing.T, segments map[string][]byte) *FakeSegmentContainer {
+	fsc := &FakeSegmentContainer{Segments: segments}
+	if fsc.Segments == nil {
+		fsc.Segments = make(map[string][]byte)
+	}
+
+	th.Mux.HandleFunc("/testContainer_segments", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		fsc.mu.Lock()
+		defer fsc.mu.Unlock()
+
+		switch r.Method {
+		case "PUT":
+			w.WriteHeader(http.StatusAccepted)
+		case "GET":
+			th.TestHeader(t, r, "Accept", "application/json")
+			r.ParseForm()
+			var names []string
+			for name := range fsc.Segments {
+				if strings.HasPrefix(name, r.Form.Get("prefix")) && name > r.Form.Get("marker") {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+
+			list := make([]map[string]interface{}, 0, len(names))
+			for _, name := range names {
+				list = append(list, map[string]interface{}{
+					"name":          name,
+					"bytes":         len(fsc.Segments[name]),
+					"hash":          fmt.Sprintf("%x", md5.Sum(fsc.Segments[name])),
+					"last_modified": "2016-08-17T22:11:58.602650",
+					"content_type":  "application/octet-stream",
+				})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(list)
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	})
+
+	th.Mux.HandleFunc("/testContainer_segments/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		name := strings.TrimPrefix(r.URL.Path, "/testContainer_segments/")
+		fsc.mu.Lock()
+		defer fsc.mu.Unlock()
+
+		switch r.Method {
+		case "PUT":
+			data, err := ioutil.ReadAll(r.Body)
+			th.AssertNoErr(t, err)
+			checksum := fmt.Sprintf("%x", md5.Sum(data))
+			th.TestHeader(t, r, "ETag", checksum)
+
+			fsc.Uploads++
+			if fsc.WrongETags > 0 {
+				fsc.WrongETags--
+				checksum = "d41d8cd98f00b204e9800998ecf8427e"
+			}
+			fsc.Segments[name] = data
+			w.Header().Set("ETag", checksum)
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE":
+			delete(fsc.Segments, name)
+			fsc.Deleted = append(fsc.Deleted, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	})
+
+	th.Mux.HandleFunc("/testContainer/testObject", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		data, err := ioutil.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+
+		fsc.mu.Lock()
+		defer fsc.mu.Unlock()
+		fsc.ManifestHeader = r.Header
+		fsc.ManifestBody = data
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	return fsc
+}
+
+// FakeObjectStore records the requests handled by HandleDownloadToSuccessfully.
+type FakeObjectStore struct {
+	mu sync.Mutex
+
+	// Objects holds the content of the objects, by path.
+	Objects map[string][]byte
+
+	// Manifests holds the paths of the segments of the static large objects,
+	// by path.
+	Manifests map[string][]string
+
+	// Downloads lists the Range headers of the GET requests of each path.
+	Downloads map[string][]string
+
+	// CorruptDownloads is the number of GET requests answered with corrupted
+	// content.
+	CorruptDownloads int
+}
+
+func (s *FakeObjectStore) content(path string) ([]byte, bool) {
+	if segments, ok := s.Manifests[path]; ok {
+		var content []byte
+		for _, segment := range segments {
+			content = append(content, s.Objects[segment]...)
+		}
+		return content, true
+	}
+	content, ok := s.Objects[path]
+	return content, ok
+}
+
+func (s *FakeObjectStore) etag(path string) string {
+	if segments, ok := s.Manifests[path]; ok {
+		hash := md5.New()
+		for _, segment := range segments {
+			fmt.Fprintf(hash, "%x", md5.Sum(s.Objects[segment]))
+		}
+		return fmt.Sprintf(`"%x"`, hash.Sum(nil))
+	}
+	return fmt.Sprintf("%x", md5.Sum(s.Objects[path]))
+}
+
+// HandleDownloadToSuccessfully creates an HTTP handler at `/` on the test handler mux that responds to the requests
+// of a `DownloadTo` from an in-memory store of objects and static large objects.
+func HandleDownloadToSuccessfully(t *testing.T, objects map[string][]byte, manifests map[string][]string) *FakeObjectStore {
+	store := &FakeObjectStore{
+		Objects:   objects,
+		Manifests: manifests,
+		Downloads: make(map[string][]string),
+	}
+
+	th.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		content, ok := store.content(r.URL.Path)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		etag := store.etag(r.URL.Path)
+		w.Header().Set("ETag", etag)
+		if _, ok := store.Manifests[r.URL.Path]; ok {
+			w.Header().Set("X-Static-Large-Object", "True")
+		}
+
+		switch r.Method {
+		case "HEAD":
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+			w.WriteHeader(http.StatusOK)
+			return
+		case "GET":
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+			return
+		}
+
+		if r.URL.Query().Get("multipart-manifest") == "get" {
+			var manifest []map[string]interface{}
+			for _, segment := range store.Manifests[r.URL.Path] {
+				manifest = append(manifest, map[string]interface{}{
+					"name":  segment,
+					"hash":  fmt.Sprintf("%x", md5.Sum(store.Objects[segment])),
+					"bytes": len(store.Objects[segment]),
+				})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(manifest)
+			return
+		}
+
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != strings.Trim(etag, `"`) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+
+		rangeHeader := r.Header.Get("Range")
+		store.Downloads[r.URL.Path] = append(store.Downloads[r.URL.Path], rangeHeader)
+		if store.CorruptDownloads > 0 {
+			store.CorruptDownloads--
+			content = []byte(strings.Repeat("x", len(content)))
+		}
+
+		if rangeHeader == "" {
+			w.WriteHeader(http.StatusOK)
+			w.Write(content)
+			return
+		}
+		var start, end int
+		fmt.Sscanf(rangeHeader, "bytes=%d-%d", &start, &end)
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(content[start : end+1])
+	})
+
+	return store
+}
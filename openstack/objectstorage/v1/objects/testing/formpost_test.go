@@ -0,0 +1,96 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	accountTesting "github.com/gophercloud/gophercloud/openstack/objectstorage/v1/accounts/testing"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	fake "github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+func TestCreateFormPost(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// Handle fetching of the account secret key inside of CreateFormPost
+	accountTesting.HandleGetAccountSuccessfully(t)
+	client := fake.ServiceClient()
+	client.Endpoint = client.Endpoint + "v1/"
+
+	formPost, err := objects.CreateFormPost(client, "testContainer", objects.CreateFormPostOpts{
+		MaxFileSize:  1024,
+		MaxFileCount: 1,
+		TTL:          60,
+		Timestamp:    time.Date(2020, 07, 01, 01, 12, 00, 00, time.UTC),
+	})
+	th.AssertNoErr(t, err)
+
+	sig := "ee61ca89efa62f382f7b19a91b01aafe88578218"
+	th.CheckDeepEquals(t, &objects.FormPost{
+		URL: th.Endpoint() + "v1/testContainer/",
+		Fields: map[string]string{
+			"max_file_size":  "1024",
+			"max_file_count": "1",
+			"expires":        "1593565980",
+			"signature":      sig,
+		},
+		Signature: sig,
+		Expires:   1593565980,
+	}, formPost)
+}
+
+func TestCreateFormPostWithKey(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// no request is sent when the key is given
+	client := fake.ServiceClient()
+	client.Endpoint = client.Endpoint + "v1/AUTH_test/"
+
+	formPost, err := objects.CreateFormPost(client, "testContainer", objects.CreateFormPostOpts{
+		Prefix:       "uploads/",
+		RedirectURL:  "https://example.com/done",
+		MaxFileSize:  100 * 1024 * 1024,
+		MaxFileCount: 10,
+		TTL:          60,
+		Timestamp:    time.Date(2020, 07, 01, 01, 12, 00, 00, time.UTC),
+		TempURLKey:   "secret",
+		Digest:       "sha512",
+	})
+	th.AssertNoErr(t, err)
+
+	sig := "sha512:zMO7ydTcqzXTi68ydol1XXJnDC4jYqGjkmZ0AgngIVG5jnk_A5TPgmKMQazNSOZFuo0aBQEhiGdI_xk_P9PUpw=="
+	th.CheckEquals(t, th.Endpoint()+"v1/AUTH_test/testContainer/uploads/", formPost.URL)
+	th.CheckDeepEquals(t, map[string]string{
+		"redirect":       "https://example.com/done",
+		"max_file_size":  "104857600",
+		"max_file_count": "10",
+		"expires":        "1593565980",
+		"signature":      sig,
+	}, formPost.Fields)
+
+	_, err = objects.CreateFormPost(client, "testContainer", objects.CreateFormPostOpts{
+		MaxFileCount: 10,
+		TTL:          60,
+		TempURLKey:   "secret",
+	})
+	if _, ok := err.(gophercloud.ErrMissingInput); !ok {
+		t.Fatalf("expected an ErrMissingInput, got %T: %v", err, err)
+	}
+
+	_, err = objects.CreateFormPost(client, "testContainer", objects.CreateFormPostOpts{
+		MaxFileSize:  1024,
+		MaxFileCount: 10,
+		TTL:          60,
+		TempURLKey:   "secret",
+		Digest:       "md5",
+	})
+	invalid, ok := err.(gophercloud.ErrInvalidInput)
+	if !ok {
+		t.Fatalf("expected an ErrInvalidInput, got %T: %v", err, err)
+	}
+	th.CheckEquals(t, "objects.CreateFormPostOpts.Digest", invalid.Argument)
+}
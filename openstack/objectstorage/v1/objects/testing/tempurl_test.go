@@ -0,0 +1,133 @@
+package testing
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	fake "github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+func TestCreateTempURLWithKey(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// no request is sent when the key is given
+	client := fake.ServiceClient()
+	client.Endpoint = client.Endpoint + "v1/AUTH_test/"
+
+	tempURL, err := objects.CreateTempURL(client, "testContainer", "testObject/testFile.txt", objects.CreateTempURLOpts{
+		Method:     objects.PUT,
+		TTL:        60,
+		Timestamp:  time.Date(2020, 07, 01, 01, 12, 00, 00, time.UTC),
+		TempURLKey: "secret",
+		Digest:     "sha256",
+		Prefix:     "testObject/",
+		IPRange:    "10.0.0.0/24",
+		ISO8601:    true,
+	})
+	th.AssertNoErr(t, err)
+
+	sig := "sha256%3A8Rkft_0KexDkJ2kL4X-_hmf-JwV5M6rXmY3GUIICgtI%3D"
+	expectedURL := fmt.Sprintf("%sv1/AUTH_test/testContainer/testObject/testFile.txt?temp_url_sig=%s&temp_url_expires=2020-07-01T01%%3A13%%3A00Z&temp_url_prefix=testObject%%2F&temp_url_ip_range=10.0.0.0%%2F24", th.Endpoint(), sig)
+	th.CheckEquals(t, expectedURL, tempURL)
+
+	_, err = objects.CreateTempURL(client, "testContainer", "testObject/testFile.txt", objects.CreateTempURLOpts{
+		Method:     objects.GET,
+		TTL:        60,
+		TempURLKey: "secret",
+		Prefix:     "otherObject/",
+	})
+	if _, ok := err.(gophercloud.ErrInvalidInput); !ok {
+		t.Fatalf("expected an ErrInvalidInput, got %T: %v", err, err)
+	}
+
+	_, err = objects.CreateTempURL(client, "testContainer", "testObject/testFile.txt", objects.CreateTempURLOpts{
+		Method:     objects.GET,
+		TTL:        60,
+		TempURLKey: "secret",
+		Digest:     "md5",
+	})
+	invalid, ok := err.(gophercloud.ErrInvalidInput)
+	if !ok {
+		t.Fatalf("expected an ErrInvalidInput, got %T: %v", err, err)
+	}
+	th.CheckEquals(t, "objects.CreateTempURLOpts.Digest", invalid.Argument)
+
+	// the split must be part of the object URL
+	_, err = objects.CreateTempURL(client, "testContainer", "testObject/testFile.txt", objects.CreateTempURLOpts{
+		Method:     objects.GET,
+		TTL:        60,
+		TempURLKey: "secret",
+		Split:      "/v2/",
+	})
+	invalid, ok = err.(gophercloud.ErrInvalidInput)
+	if !ok {
+		t.Fatalf("expected an ErrInvalidInput, got %T: %v", err, err)
+	}
+	th.CheckEquals(t, "objects.CreateTempURLOpts.Split", invalid.Argument)
+}
+
+func TestValidateTempURL(t *testing.T) {
+	now := time.Date(2020, 07, 01, 01, 12, 00, 00, time.UTC)
+	tempURL := "https://swift.example.com/v1/AUTH_test/testContainer/testObject/testFile.txt?temp_url_sig=sha256%3A8Rkft_0KexDkJ2kL4X-_hmf-JwV5M6rXmY3GUIICgtI%3D&temp_url_expires=2020-07-01T01%3A13%3A00Z&temp_url_prefix=testObject%2F&temp_url_ip_range=10.0.0.0%2F24"
+	opts := objects.ValidateTempURLOpts{
+		Method:      objects.PUT,
+		TempURLKeys: []string{"other", "secret"},
+		Timestamp:   now,
+		RemoteIP:    "10.0.0.42",
+	}
+	th.AssertNoErr(t, objects.ValidateTempURL(tempURL, opts))
+
+	// the prefix allows the other objects starting with it
+	otherURL := "https://swift.example.com/v1/AUTH_test/testContainer/testObject/other.txt?temp_url_sig=sha256:8Rkft%2F0KexDkJ2kL4X%2B%2Fhmf%2BJwV5M6rXmY3GUIICgtI&temp_url_expires=1593565980&temp_url_prefix=testObject/&temp_url_ip_range=10.0.0.0/24"
+	th.AssertNoErr(t, objects.ValidateTempURL(otherURL, opts))
+
+	opts.Method = objects.HEAD
+	th.AssertNoErr(t, objects.ValidateTempURL(tempURL, opts))
+
+	sha512URL := "https://swift.example.com/v1/AUTH_test/testContainer/testObject/testFile.txt?temp_url_sig=8e0bb3028f7eaa2f34559acd2b21adbedfdb764197318877a1c85f9435d1db1cc74c7bc73becec76b8cec344717dcf516c17d059787a51dfc09fa90c8936aeef&temp_url_expires=1593565980"
+	th.AssertNoErr(t, objects.ValidateTempURL(sha512URL, objects.ValidateTempURLOpts{
+		Method:      objects.GET,
+		TempURLKeys: []string{"secret"},
+		Timestamp:   now,
+	}))
+
+	for name, invalid := range map[string]struct {
+		tempURL string
+		opts    objects.ValidateTempURLOpts
+	}{
+		"method": {tempURL, objects.ValidateTempURLOpts{
+			Method: objects.DELETE, TempURLKeys: []string{"secret"}, Timestamp: now, RemoteIP: "10.0.0.42",
+		}},
+		"key": {tempURL, objects.ValidateTempURLOpts{
+			Method: objects.PUT, TempURLKeys: []string{"other"}, Timestamp: now, RemoteIP: "10.0.0.42",
+		}},
+		"expired": {tempURL, objects.ValidateTempURLOpts{
+			Method: objects.PUT, TempURLKeys: []string{"secret"}, Timestamp: now.Add(time.Minute), RemoteIP: "10.0.0.42",
+		}},
+		"IP range": {tempURL, objects.ValidateTempURLOpts{
+			Method: objects.PUT, TempURLKeys: []string{"secret"}, Timestamp: now, RemoteIP: "10.0.1.42",
+		}},
+		"unknown IP": {tempURL, objects.ValidateTempURLOpts{
+			Method: objects.PUT, TempURLKeys: []string{"secret"}, Timestamp: now,
+		}},
+		"prefix": {"https://swift.example.com/v1/AUTH_test/testContainer/otherObject/testFile.txt?temp_url_sig=sha256%3A8Rkft_0KexDkJ2kL4X-_hmf-JwV5M6rXmY3GUIICgtI%3D&temp_url_expires=1593565980&temp_url_prefix=testObject%2F&temp_url_ip_range=10.0.0.0%2F24", objects.ValidateTempURLOpts{
+			Method: objects.PUT, TempURLKeys: []string{"secret"}, Timestamp: now, RemoteIP: "10.0.0.42",
+		}},
+		"object": {sha512URL, objects.ValidateTempURLOpts{
+			Method: objects.GET, TempURLKeys: []string{"secret"}, Timestamp: now, Split: "/testObject/",
+		}},
+		"signature": {"https://swift.example.com/v1/AUTH_test/testContainer/testObject?temp_url_sig=abc&temp_url_expires=1593565980", objects.ValidateTempURLOpts{
+			Method: objects.GET, TempURLKeys: []string{"secret"}, Timestamp: now,
+		}},
+	} {
+		err := objects.ValidateTempURL(invalid.tempURL, invalid.opts)
+		if _, ok := err.(objects.ErrInvalidTempURL); !ok {
+			t.Errorf("%s: expected an ErrInvalidTempURL, got %T: %v", name, err, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package testing
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	fake "github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+func checksum(s string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(s)))
+}
+
+func TestUploadStaticLargeObject(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	fsc := HandleUploadSuccessfully(t, map[string][]byte{
+		// left by a previous upload of a longer content
+		"testObject/slo/4/00000003": []byte("mnop"),
+	})
+
+	opts := objects.UploadOpts{
+		Content:     strings.NewReader("abcdefghij"),
+		SegmentSize: 4,
+		Concurrency: 2,
+		ContentType: "text/plain",
+		Metadata:    map[string]string{"Gophercloud-Test": "objects"},
+	}
+	res := objects.Upload(fake.ServiceClient(), "testContainer", "testObject", opts)
+	th.AssertNoErr(t, res.Err)
+
+	segments, err := res.ExtractSegments()
+	th.AssertNoErr(t, err)
+	expected := []objects.Segment{
+		{Path: "testContainer_segments/testObject/slo/4/00000000", ETag: checksum("abcd"), SizeBytes: 4},
+		{Path: "testContainer_segments/testObject/slo/4/00000001", ETag: checksum("efgh"), SizeBytes: 4},
+		{Path: "testContainer_segments/testObject/slo/4/00000002", ETag: checksum("ij"), SizeBytes: 2},
+	}
+	th.CheckDeepEquals(t, expected, segments)
+	th.CheckEquals(t, 3, fsc.Uploads)
+	th.CheckEquals(t, "ij", string(fsc.Segments["testObject/slo/4/00000002"]))
+	th.CheckDeepEquals(t, []string{"testObject/slo/4/00000003"}, fsc.Deleted)
+
+	var manifest []objects.Segment
+	th.AssertNoErr(t, json.Unmarshal(fsc.ManifestBody, &manifest))
+	th.CheckDeepEquals(t, expected, manifest)
+	th.CheckEquals(t, checksum(checksum("abcd")+checksum("efgh")+checksum("ij")), fsc.ManifestHeader.Get("ETag"))
+	th.CheckEquals(t, "text/plain", fsc.ManifestHeader.Get("Content-Type"))
+	th.CheckEquals(t, "objects", fsc.ManifestHeader.Get("X-Object-Meta-Gophercloud-Test"))
+}
+
+func TestUploadDynamicLargeObject(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	fsc := HandleUploadSuccessfully(t, nil)
+
+	opts := objects.UploadOpts{
+		Content:      strings.NewReader("abcdefgh"),
+		SegmentSize:  4,
+		ManifestType: objects.DynamicLargeObject,
+	}
+	res := objects.Upload(fake.ServiceClient(), "testContainer", "testObject", opts)
+	th.AssertNoErr(t, res.Err)
+
+	th.CheckEquals(t, 2, fsc.Uploads)
+	th.CheckEquals(t, "efgh", string(fsc.Segments["testObject/dlo/4/00000001"]))
+	th.CheckEquals(t, "testContainer_segments/testObject/dlo/4/", fsc.ManifestHeader.Get("X-Object-Manifest"))
+	th.CheckEquals(t, 0, len(fsc.ManifestBody))
+}
+
+func TestUploadResume(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	fsc := HandleUploadSuccessfully(t, map[string][]byte{
+		"testObject/slo/4/00000000": []byte("abcd"),
+		// interrupted upload
+		"testObject/slo/4/00000001": []byte("ef"),
+	})
+
+	opts := objects.UploadOpts{
+		Content:     strings.NewReader("abcdefghij"),
+		SegmentSize: 4,
+		Resume:      true,
+	}
+	res := objects.Upload(fake.ServiceClient(), "testContainer", "testObject", opts)
+	th.AssertNoErr(t, res.Err)
+
+	segments, err := res.ExtractSegments()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(segments))
+	th.CheckEquals(t, true, segments[0].Skipped)
+	th.CheckEquals(t, false, segments[1].Skipped)
+	th.CheckEquals(t, 2, fsc.Uploads)
+	th.CheckEquals(t, "efgh", string(fsc.Segments["testObject/slo/4/00000001"]))
+	th.CheckEquals(t, 0, len(fsc.Deleted))
+}
+
+func TestUploadRetriesWrongETag(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	fsc := HandleUploadSuccessfully(t, nil)
+	fsc.WrongETags = 2
+
+	opts := objects.UploadOpts{
+		Content:     strings.NewReader("abcd"),
+		SegmentSize: 4,
+	}
+	res := objects.Upload(fake.ServiceClient(), "testContainer", "testObject", opts)
+	th.AssertNoErr(t, res.Err)
+	th.CheckEquals(t, 3, fsc.Uploads)
+
+	th.TeardownHTTP()
+	th.SetupHTTP()
+	fsc = HandleUploadSuccessfully(t, nil)
+	fsc.WrongETags = 3
+
+	opts.Content = strings.NewReader("abcd")
+	res = objects.Upload(fake.ServiceClient(), "testContainer", "testObject", opts)
+	if _, ok := res.Err.(objects.ErrWrongChecksum); !ok {
+		t.Fatalf("expected an ErrWrongChecksum, got %T: %v", res.Err, res.Err)
+	}
+	th.CheckEquals(t, 3, fsc.Uploads)
+	th.CheckEquals(t, 0, len(fsc.ManifestBody))
+}
+
+func TestUploadEmptyContent(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	fsc := HandleUploadSuccessfully(t, nil)
+
+	res := objects.Upload(fake.ServiceClient(), "testContainer", "testObject", objects.UploadOpts{
+		Content: strings.NewReader(""),
+	})
+	th.AssertNoErr(t, res.Err)
+	th.CheckEquals(t, 0, fsc.Uploads)
+	th.CheckEquals(t, "", fsc.ManifestHeader.Get("X-Object-Manifest"))
+}
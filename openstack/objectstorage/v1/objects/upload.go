@@ -0,0 +1,377 @@
+package objects
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+)
+
+const (
+	// DefaultSegmentSize is the size of the segments of an Upload when
+	// UploadOpts.SegmentSize isn't set.
+	DefaultSegmentSize = 100 * 1024 * 1024
+
+	// DefaultUploadConcurrency is the number of segments uploaded at the same
+	// time when UploadOpts.Concurrency isn't set.
+	DefaultUploadConcurrency = 4
+
+	// DefaultMaxSegmentAttempts is the number of times the upload of a segment
+	// is attempted when UploadOpts.MaxSegmentAttempts isn't set.
+	DefaultMaxSegmentAttempts = 3
+)
+
+// ManifestType is the kind of large object written by Upload.
+type ManifestType string
+
+const (
+	// StaticLargeObject writes a manifest listing the segments with their
+	// checksums. It is the default.
+	StaticLargeObject ManifestType = "slo"
+
+	// DynamicLargeObject writes a manifest which serves all the objects of the
+	// segment container starting with the segment prefix.
+	DynamicLargeObject ManifestType = "dlo"
+)
+
+// UploadOpts is a structure that holds parameters for uploading a large
+// object in segments.
+type UploadOpts struct {
+	// Content is the content of the object. It is read sequentially, one segment
+	// at a time, so it doesn't need to fit in memory.
+	Content io.Reader
+
+	// SegmentSize is the maximum size of a segment. Each segment being uploaded
+	// is held in memory, so an Upload uses up to Concurrency*SegmentSize bytes.
+	// Defaults to DefaultSegmentSize.
+	SegmentSize int64
+
+	// Concurrency is the number of segments uploaded at the same time. Defaults
+	// to DefaultUploadConcurrency.
+	Concurrency int
+
+	// MaxSegmentAttempts is the number of times the upload of a segment is
+	// attempted before Upload fails, including when the ETag returned by the
+	// server doesn't match the local checksum. Defaults to
+	// DefaultMaxSegmentAttempts.
+	MaxSegmentAttempts uint
+
+	// ManifestType is the kind of manifest written once all the segments are
+	// uploaded. Defaults to StaticLargeObject.
+	ManifestType ManifestType
+
+	// SegmentContainer is the container holding the segments. It is created if
+	// needed. Defaults to the name of the container of the object followed by
+	// "_segments".
+	SegmentContainer string
+
+	// SegmentPrefix is the prefix of the names of the segments, which are
+	// followed by their 8 digits index. It must be dedicated to this object:
+	// the objects starting with it which aren't part of the upload are deleted.
+	// Defaults to the name of the object followed by the manifest type and the
+	// segment size, for example "my_object/slo/104857600/".
+	SegmentPrefix string
+
+	// Resume skips the upload of the segments already present in the segment
+	// container with the same size and checksum, to continue an interrupted
+	// Upload of the same content.
+	Resume bool
+
+	// ContentType is the content type of the object.
+	ContentType string
+
+	// Metadata is the custom metadata of the object.
+	Metadata map[string]string
+}
+
+// Segment is a segment of an object uploaded by Upload.
+type Segment struct {
+	// Path is the container and name of the segment, separated by a slash.
+	Path string `json:"path"`
+
+	// ETag is the MD5 checksum of the segment.
+	ETag string `json:"etag"`
+
+	// SizeBytes is the size of the segment.
+	SizeBytes int64 `json:"size_bytes"`
+
+	// Skipped is true if the segment was already present in the segment
+	// container, see UploadOpts.Resume.
+	Skipped bool `json:"-"`
+}
+
+// UploadResult represents the result of an Upload. Its embedded CreateResult
+// is the result of the creation of the manifest.
+type UploadResult struct {
+	CreateResult
+	segments []Segment
+}
+
+// ExtractSegments returns the segments of the uploaded object, in order.
+func (r UploadResult) ExtractSegments() ([]Segment, error) {
+	return r.segments, r.Err
+}
+
+// uploader holds the state of an Upload.
+type uploader struct {
+	client           *gophercloud.ServiceClient
+	opts             UploadOpts
+	segmentContainer string
+	segmentPrefix    string
+	existing         map[string]Object
+
+	mu       sync.Mutex
+	err      error
+	segments []Segment
+}
+
+func (u *uploader) failed() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.err != nil
+}
+
+func (u *uploader) done(index int, segment Segment, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if err != nil {
+		if u.err == nil {
+			u.err = err
+		}
+		return
+	}
+	for len(u.segments) <= index {
+		u.segments = append(u.segments, Segment{})
+	}
+	u.segments[index] = segment
+}
+
+// uploadSegment uploads the segment number index unless an identical one
+// already exists, and verifies the ETag returned by the server.
+func (u *uploader) uploadSegment(index int, data []byte, checksum string) (Segment, error) {
+	name := fmt.Sprintf("%s%08d", u.segmentPrefix, index)
+	segment := Segment{
+		Path:      u.segmentContainer + "/" + name,
+		ETag:      checksum,
+		SizeBytes: int64(len(data)),
+	}
+
+	if existing, ok := u.existing[name]; ok && u.opts.Resume {
+		if existing.Hash == checksum && existing.Bytes == segment.SizeBytes {
+			segment.Skipped = true
+			return segment, nil
+		}
+	}
+
+	maxAttempts := u.opts.MaxSegmentAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxSegmentAttempts
+	}
+
+	var err error
+	for attempt := uint(0); attempt < maxAttempts; attempt++ {
+		createOpts := CreateOpts{
+			Content: bytes.NewReader(data),
+			ETag:    checksum,
+		}
+		var header *CreateHeader
+		header, err = Create(u.client, u.segmentContainer, name, createOpts).Extract()
+		if err == nil && strings.Trim(header.ETag, `"`) != checksum {
+			err = ErrWrongChecksum{}
+		}
+		if err == nil {
+			return segment, nil
+		}
+	}
+	return segment, err
+}
+
+// Upload uploads the content of a large object in segments, at most
+// opts.Concurrency of them at the same time, then writes the manifest of the
+// object. The segments are verified against their checksum and retried up to
+// opts.MaxSegmentAttempts times. Content which fits in a single segment is
+// still uploaded as a segment; empty content is uploaded as a regular object.
+func Upload(c *gophercloud.ServiceClient, containerName, objectName string, opts UploadOpts) (r UploadResult) {
+	if opts.Content == nil {
+		r.Err = gophercloud.ErrMissingInput{Argument: "objects.UploadOpts.Content"}
+		return
+	}
+	if opts.SegmentSize < 0 {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "objects.UploadOpts.SegmentSize"
+		err.Value = opts.SegmentSize
+		r.Err = err
+		return
+	}
+	if opts.SegmentSize == 0 {
+		opts.SegmentSize = DefaultSegmentSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultUploadConcurrency
+	}
+	switch opts.ManifestType {
+	case "":
+		opts.ManifestType = StaticLargeObject
+	case StaticLargeObject, DynamicLargeObject:
+	default:
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "objects.UploadOpts.ManifestType"
+		err.Value = opts.ManifestType
+		r.Err = err
+		return
+	}
+
+	u := uploader{
+		client:           c,
+		opts:             opts,
+		segmentContainer: opts.SegmentContainer,
+		segmentPrefix:    opts.SegmentPrefix,
+		existing:         make(map[string]Object),
+	}
+	if u.segmentContainer == "" {
+		u.segmentContainer = containerName + "_segments"
+	}
+	if u.segmentPrefix == "" {
+		u.segmentPrefix = fmt.Sprintf("%s/%s/%d/", objectName, opts.ManifestType, opts.SegmentSize)
+	}
+
+	// Read the first segment before touching the segment container, so that
+	// empty content can be uploaded as a regular object.
+	first := make([]byte, opts.SegmentSize)
+	n, err := io.ReadFull(opts.Content, first)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		r.Err = err
+		return
+	}
+	if n == 0 {
+		r.CreateResult = Create(c, containerName, objectName, CreateOpts{
+			Content:     bytes.NewReader(nil),
+			ContentType: opts.ContentType,
+			Metadata:    opts.Metadata,
+		})
+		return
+	}
+	last := err != nil
+
+	r.Err = containers.Create(c, u.segmentContainer, nil).Err
+	if r.Err != nil {
+		return
+	}
+
+	allPages, err := List(c, u.segmentContainer, ListOpts{Full: true, Prefix: u.segmentPrefix}).AllPages()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	existing, err := ExtractInfo(allPages)
+	if err != nil {
+		r.Err = err
+		return
+	}
+	for _, object := range existing {
+		u.existing[object.Name] = object
+	}
+
+	buffers := make(chan []byte, opts.Concurrency)
+	buffers <- first
+	for i := 1; i < opts.Concurrency; i++ {
+		buffers <- nil
+	}
+
+	var wg sync.WaitGroup
+	count := 0
+	for !u.failed() {
+		buf := <-buffers
+		data := buf
+		if count == 0 {
+			data = buf[:n]
+		} else {
+			if buf == nil {
+				buf = make([]byte, opts.SegmentSize)
+			}
+			n, err := io.ReadFull(opts.Content, buf)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				u.done(count, Segment{}, err)
+				buffers <- buf
+				break
+			}
+			if n == 0 {
+				buffers <- buf
+				break
+			}
+			data = buf[:n]
+			last = err != nil
+		}
+
+		wg.Add(1)
+		go func(index int, buf, data []byte) {
+			defer wg.Done()
+			defer func() { buffers <- buf }()
+			segment, err := u.uploadSegment(index, data, fmt.Sprintf("%x", md5.Sum(data)))
+			u.done(index, segment, err)
+		}(count, buf, data)
+		count++
+
+		if last {
+			break
+		}
+	}
+	wg.Wait()
+
+	if u.err != nil {
+		r.Err = u.err
+		return
+	}
+	r.segments = u.segments
+
+	createOpts := CreateOpts{
+		ContentType: opts.ContentType,
+		Metadata:    opts.Metadata,
+	}
+	if opts.ManifestType == StaticLargeObject {
+		b, err := json.Marshal(u.segments)
+		if err != nil {
+			r.Err = err
+			return
+		}
+		// The ETag of a manifest is the checksum of the checksums of its
+		// segments.
+		hash := md5.New()
+		for _, segment := range u.segments {
+			io.WriteString(hash, segment.ETag)
+		}
+		createOpts.Content = bytes.NewReader(b)
+		createOpts.ETag = fmt.Sprintf("%x", hash.Sum(nil))
+		createOpts.MultipartManifest = "put"
+	} else {
+		createOpts.Content = bytes.NewReader(nil)
+		createOpts.NoETag = true
+		createOpts.ObjectManifest = u.segmentContainer + "/" + u.segmentPrefix
+	}
+	r.CreateResult = Create(c, containerName, objectName, createOpts)
+	if r.Err != nil {
+		return
+	}
+
+	// Remove the segments left by a previous upload, which would otherwise
+	// waste space or, with a dynamic manifest, be part of the object.
+	for name := range u.existing {
+		var index int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(name, u.segmentPrefix), "%08d", &index); err == nil && index < count {
+			continue
+		}
+		err := Delete(c, u.segmentContainer, name, nil).Err
+		if _, ok := err.(gophercloud.ErrDefault404); err != nil && !ok {
+			r.Err = err
+			return
+		}
+	}
+
+	return
+}
@@ -313,3 +313,98 @@ func TestAuthenticatedClientV3Fails(t *testing.T) {
 func TestAuthenticatedClientV2Fails(t *testing.T) {
 	testAuthenticatedClientFails(t, "http://bad-address.example.com/v2.0")
 }
+
+func TestEndpointOverrides(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Subject-Token", ID)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `
+	{
+    "token": {
+        "catalog": [
+            {
+                "endpoints": [
+                    {
+                        "id": "39dc322ce86c4111b4f06c2eeae0841b",
+                        "interface": "public",
+                        "region": "RegionOne",
+                        "url": "https://catalog.example.com/compute/v2.1"
+                    }
+                ],
+                "id": "4363ae44bdf34a3981fde3b823cb9aa2",
+                "type": "compute",
+                "name": "nova"
+            },
+            {
+                "endpoints": [
+                    {
+                        "id": "ec642f27474842e78bf059f6c48f4e99",
+                        "interface": "public",
+                        "region": "RegionOne",
+                        "url": "https://catalog.example.com/image"
+                    }
+                ],
+                "id": "c609fc430175452290b62a4242e8a7e8",
+                "type": "image",
+                "name": "glance"
+            }
+        ],
+        "expires_at": "2013-02-27T18:30:59.999999Z",
+        "methods": [
+            "password"
+        ],
+        "project": {
+            "domain": {
+                "id": "1789d1",
+                "name": "example.com"
+            },
+            "id": "263fd9",
+            "name": "project-x"
+        }
+    }
+}
+	`)
+	})
+
+	pc, err := openstack.NewClient(th.Endpoint() + "v3/")
+	th.AssertNoErr(t, err)
+	pc.EndpointOverrides = map[string]string{
+		"compute":       "https://compute.example.com/v2.1/%(project_id)s",
+		"block-storage": "https://volume.example.com/v3",
+		"identity":      "https://keystone.example.com/v3",
+	}
+	err = openstack.Authenticate(pc, gophercloud.AuthOptions{
+		Username:         "me",
+		Password:         "secret",
+		DomainID:         "12345",
+		IdentityEndpoint: th.Endpoint() + "v3/",
+	})
+	th.AssertNoErr(t, err)
+
+	sc, err := openstack.NewComputeV2(pc, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://compute.example.com/v2.1/263fd9/", sc.Endpoint)
+
+	// the override of block-storage applies to its volumev3 alias
+	sc, err = openstack.NewBlockStorageV3(pc, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://volume.example.com/v3/", sc.Endpoint)
+
+	// the services without an override still use the service catalog
+	sc, err = openstack.NewImageServiceV2(pc, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://catalog.example.com/image/", sc.Endpoint)
+
+	sc, err = openstack.NewIdentityV3(pc, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://keystone.example.com/v3/", sc.Endpoint)
+
+	pc.EndpointOverrides["identity"] = "https://keystone.example.com/v2.0"
+	sc, err = openstack.NewIdentityV2(pc, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://keystone.example.com/v2.0/", sc.Endpoint)
+}
@@ -0,0 +1,172 @@
+package testing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/fakecloud"
+)
+
+const cloudsYAML = `
+cache:
+  expiration_time: 3600
+  class: dogpile.cache.memory
+client:
+  force_ipv4: true
+clouds:
+  mycloud:
+    profile: myprofile
+    auth:
+      username: admin
+      project_name: demo
+      user_domain_name: Default
+      project_domain_name: Default
+    region_name: RegionTwo
+    interface: internal
+    compute_endpoint_override: https://compute.example.com/v2.1
+  appcred:
+    auth_type: v3applicationcredential
+    auth:
+      auth_url: https://keystone.example.com/v3
+      application_credential_id: abc
+      application_credential_secret: s3cr3t
+  unsupported:
+    auth_type: v3oidcpassword
+    auth:
+      auth_url: https://keystone.example.com/v3
+`
+
+const secureYAML = `
+clouds:
+  mycloud:
+    auth:
+      password: s3cr3t
+`
+
+const cloudsPublicYAML = `
+public-clouds:
+  myprofile:
+    auth:
+      auth_url: https://keystone.example.com
+    identity_api_version: 3
+    region_name: RegionOne
+`
+
+// setupCloudsYAML writes the clouds.yaml, secure.yaml and clouds-public.yaml
+// files to a temporary directory, which becomes the current directory.
+func setupCloudsYAML(t *testing.T, files map[string]string) func() {
+	dir, err := ioutil.TempDir("", "clouds-yaml")
+	th.AssertNoErr(t, err)
+	for name, content := range files {
+		th.AssertNoErr(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	wd, err := os.Getwd()
+	th.AssertNoErr(t, err)
+	th.AssertNoErr(t, os.Chdir(dir))
+	os.Unsetenv("OS_CLIENT_CONFIG_FILE")
+	os.Unsetenv("OS_CLIENT_SECURE_FILE")
+	os.Unsetenv("OS_CLOUD")
+
+	return func() {
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestClientConfigFromCloudsYAML(t *testing.T) {
+	defer setupCloudsYAML(t, map[string]string{
+		"clouds.yaml":        cloudsYAML,
+		"secure.yaml":        secureYAML,
+		"clouds-public.yaml": cloudsPublicYAML,
+	})()
+
+	config, err := openstack.ClientConfigFromCloudsYAML("mycloud")
+	th.AssertNoErr(t, err)
+
+	th.AssertDeepEquals(t, gophercloud.AuthOptions{
+		IdentityEndpoint: "https://keystone.example.com/v3/",
+		Username:         "admin",
+		Password:         "s3cr3t",
+		DomainName:       "Default",
+		TenantName:       "demo",
+		AllowReauth:      true,
+		Scope: &gophercloud.AuthScope{
+			ProjectName: "demo",
+			DomainName:  "Default",
+		},
+	}, config.AuthOptions)
+	th.AssertEquals(t, "RegionTwo", config.EndpointOpts.Region)
+	th.AssertEquals(t, gophercloud.AvailabilityInternal, config.EndpointOpts.Availability)
+	th.AssertEquals(t, "https://compute.example.com/v2.1", config.EndpointOverrides["compute"])
+}
+
+func TestAuthOptionsFromCloudsYAMLApplicationCredential(t *testing.T) {
+	defer setupCloudsYAML(t, map[string]string{"clouds.yaml": cloudsYAML})()
+	os.Setenv("OS_CLOUD", "appcred")
+	defer os.Unsetenv("OS_CLOUD")
+
+	ao, err := openstack.AuthOptionsFromCloudsYAML("")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://keystone.example.com/v3", ao.IdentityEndpoint)
+	th.AssertEquals(t, "abc", ao.ApplicationCredentialID)
+	th.AssertEquals(t, "s3cr3t", ao.ApplicationCredentialSecret)
+	th.AssertEquals(t, true, ao.Scope == nil)
+}
+
+func TestAuthOptionsFromCloudsYAMLErrors(t *testing.T) {
+	defer setupCloudsYAML(t, map[string]string{"clouds.yaml": cloudsYAML})()
+
+	_, err := openstack.AuthOptionsFromCloudsYAML("unsupported")
+	if _, ok := err.(openstack.ErrUnsupportedAuthType); !ok {
+		t.Fatalf("expected an ErrUnsupportedAuthType, got %T: %v", err, err)
+	}
+
+	_, err = openstack.AuthOptionsFromCloudsYAML("missing")
+	if _, ok := err.(openstack.ErrCloudNotFound); !ok {
+		t.Fatalf("expected an ErrCloudNotFound, got %T: %v", err, err)
+	}
+
+	// the profile can't be found without clouds-public.yaml
+	_, err = openstack.AuthOptionsFromCloudsYAML("mycloud")
+	if _, ok := err.(openstack.ErrCloudNotFound); !ok {
+		t.Fatalf("expected an ErrCloudNotFound, got %T: %v", err, err)
+	}
+}
+
+func TestClientConfigAuthenticatedClient(t *testing.T) {
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	defer setupCloudsYAML(t, map[string]string{
+		"clouds.yaml": `
+clouds:
+  fake:
+    auth:
+      auth_url: ` + cloud.IdentityEndpoint() + `
+      username: ` + fakecloud.Username + `
+      password: ` + fakecloud.Password + `
+      project_name: ` + fakecloud.ProjectName + `
+      domain_name: ` + fakecloud.DomainName + `
+    image_endpoint_override: https://image.example.com
+`,
+	})()
+
+	config, err := openstack.ClientConfigFromCloudsYAML("fake")
+	th.AssertNoErr(t, err)
+	provider, err := config.AuthenticatedClient()
+	th.AssertNoErr(t, err)
+
+	compute, err := openstack.NewComputeV2(provider, config.EndpointOpts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, cloud.Server.URL+"/compute/v2.1/", compute.Endpoint)
+
+	image, err := openstack.NewImageServiceV2(provider, config.EndpointOpts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://image.example.com/v2/", image.ResourceBaseURL())
+}
@@ -1,6 +1,7 @@
 package testing
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/gophercloud/gophercloud"
@@ -271,3 +272,118 @@ func TestV3EndpointWithRegionID(t *testing.T) {
 		th.CheckEquals(t, expected, actual)
 	}
 }
+
+var catalogAliases3 = tokens3.ServiceCatalog{
+	Entries: []tokens3.CatalogEntry{
+		{
+			Type: "block-storage",
+			Name: "cinder",
+			Endpoints: []tokens3.Endpoint{
+				{
+					ID:        "1",
+					Region:    "RegionOne",
+					Interface: "public",
+					URL:       "https://volume.example.com/v3",
+				},
+			},
+		},
+		{
+			Type: "compute",
+			Name: "nova",
+			Endpoints: []tokens3.Endpoint{
+				{
+					ID:        "2",
+					Region:    "RegionOne",
+					Interface: "public",
+					URL:       "https://compute.example.com/",
+				},
+				{
+					ID:        "3",
+					Region:    "RegionTwo",
+					Interface: "public",
+					URL:       "https://compute.example.org/",
+				},
+			},
+		},
+	},
+}
+
+func TestV3EndpointServiceTypeAlias(t *testing.T) {
+	actual, err := openstack.V3EndpointURL(&catalogAliases3, gophercloud.EndpointOpts{
+		Type:         "volumev3",
+		Availability: gophercloud.AvailabilityPublic,
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://volume.example.com/v3/", actual)
+
+	// older versions are not aliases
+	_, err = openstack.V3EndpointURL(&catalogAliases3, gophercloud.EndpointOpts{
+		Type:         "volumev2",
+		Availability: gophercloud.AvailabilityPublic,
+	})
+	th.CheckEquals(t, (&gophercloud.ErrEndpointNotFound{}).Error(), err.Error())
+}
+
+func TestV3EndpointAvailabilityFallback(t *testing.T) {
+	actual, err := openstack.V3EndpointURL(&catalogAliases3, gophercloud.EndpointOpts{
+		Type:                 "compute",
+		Region:               "RegionOne",
+		Availability:         gophercloud.AvailabilityInternal,
+		AvailabilityFallback: []gophercloud.Availability{gophercloud.AvailabilityPublic},
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://compute.example.com/", actual)
+
+	// the internal endpoint is preferred when there is one
+	actual, err = openstack.V3EndpointURL(&catalog3, gophercloud.EndpointOpts{
+		Type:                 "same",
+		Name:                 "same",
+		Region:               "same",
+		Availability:         gophercloud.AvailabilityInternal,
+		AvailabilityFallback: []gophercloud.Availability{gophercloud.AvailabilityPublic},
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://internal.correct.com/", actual)
+
+	_, err = openstack.V3EndpointURL(&catalog3, gophercloud.EndpointOpts{
+		Type:                 "same",
+		Availability:         gophercloud.AvailabilityInternal,
+		AvailabilityFallback: []gophercloud.Availability{"wat"},
+	})
+	th.CheckEquals(t, "Unexpected availability in endpoint query: wat", err.Error())
+}
+
+func TestV2EndpointAvailabilityFallback(t *testing.T) {
+	actual, err := openstack.V2EndpointURL(&catalog2, gophercloud.EndpointOpts{
+		Type:                 "same",
+		Name:                 "same",
+		Region:               "different",
+		Availability:         gophercloud.AvailabilityInternal,
+		AvailabilityFallback: []gophercloud.Availability{gophercloud.AvailabilityPublic},
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://badregion.com/", actual)
+}
+
+func TestV3EndpointRequireUnique(t *testing.T) {
+	_, err := openstack.V3EndpointURL(&catalogAliases3, gophercloud.EndpointOpts{
+		Type:          "compute",
+		Availability:  gophercloud.AvailabilityPublic,
+		RequireUnique: true,
+	})
+	var multipleErr *gophercloud.ErrMultipleMatchingEndpoints
+	if !errors.As(err, &multipleErr) {
+		t.Fatalf("expected an ErrMultipleMatchingEndpoints, got %T: %v", err, err)
+	}
+	th.CheckDeepEquals(t, []string{"https://compute.example.com/", "https://compute.example.org/"}, multipleErr.Candidates)
+	th.CheckEquals(t, "Found 2 endpoints of service type compute matching the criteria: https://compute.example.com/, https://compute.example.org/", err.Error())
+
+	actual, err := openstack.V3EndpointURL(&catalogAliases3, gophercloud.EndpointOpts{
+		Type:          "compute",
+		Region:        "RegionTwo",
+		Availability:  gophercloud.AvailabilityPublic,
+		RequireUnique: true,
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "https://compute.example.org/", actual)
+}
@@ -0,0 +1,110 @@
+package testing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/fakecloud"
+)
+
+func newCachedClient(t *testing.T, cloud *fakecloud.Cloud, cache gophercloud.TokenCache) *gophercloud.ProviderClient {
+	ao := cloud.AuthOptions()
+	ao.AllowReauth = true
+
+	provider, err := openstack.NewClient(ao.IdentityEndpoint)
+	th.AssertNoErr(t, err)
+	provider.TokenCache = cache
+	th.AssertNoErr(t, openstack.Authenticate(provider, ao))
+	return provider
+}
+
+func TestTokenCache(t *testing.T) {
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	dir, err := ioutil.TempDir("", "token-cache")
+	th.AssertNoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	cache, err := gophercloud.NewFileTokenCache(dir)
+	th.AssertNoErr(t, err)
+
+	first := newCachedClient(t, cloud, cache)
+	second := newCachedClient(t, cloud, cache)
+	th.AssertEquals(t, first.Token(), second.Token())
+
+	// the cached catalog is usable
+	compute, err := openstack.NewComputeV2(second, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, cloud.Server.URL+"/compute/v2.1/", compute.Endpoint)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(files))
+	fi, err := os.Stat(files[0])
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, os.FileMode(0600), fi.Mode().Perm())
+
+	// a rejected token is evicted from the cache when reauthenticating
+	cloud.RevokeTokens()
+	revoked := second.Token()
+	_, err = servers.List(compute, nil).AllPages()
+	th.AssertNoErr(t, err)
+	if second.Token() == revoked {
+		t.Fatalf("expected a new token after the reauthentication")
+	}
+
+	third := newCachedClient(t, cloud, cache)
+	th.AssertEquals(t, second.Token(), third.Token())
+}
+
+type memoryTokenCache map[string]*gophercloud.CachedToken
+
+func (c memoryTokenCache) Get(key string) (*gophercloud.CachedToken, error) {
+	return c[key], nil
+}
+
+func (c memoryTokenCache) Set(key string, token *gophercloud.CachedToken) error {
+	c[key] = token
+	return nil
+}
+
+func (c memoryTokenCache) Delete(key string) error {
+	delete(c, key)
+	return nil
+}
+
+func TestTokenCacheExpiringToken(t *testing.T) {
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	cache := memoryTokenCache{}
+	first := newCachedClient(t, cloud, cache)
+	th.AssertEquals(t, 1, len(cache))
+
+	// the keys don't hold the credentials
+	for key := range cache {
+		th.AssertEquals(t, false, strings.Contains(key, fakecloud.Password))
+		th.AssertEquals(t, 64, len(key))
+	}
+
+	// a token expiring within the margin isn't reused
+	for _, token := range cache {
+		token.ExpiresAt = time.Now().Add(gophercloud.DefaultTokenCacheMargin / 2)
+	}
+	second := newCachedClient(t, cloud, cache)
+	if first.Token() == second.Token() {
+		t.Fatalf("expected a new token instead of the expiring one")
+	}
+	for _, token := range cache {
+		th.AssertEquals(t, second.Token(), token.ID)
+	}
+}
@@ -0,0 +1,116 @@
+package openstack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	tokens3 "github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+)
+
+// tokenCacheKey returns the key of the tokens created with opts in a
+// gophercloud.TokenCache, or an empty string if they must not be cached. The
+// key is the hex SHA-256 of the auth URL and of the token creation request,
+// which holds the user, the credentials and the scope, so that the caches never
+// see the credentials.
+func tokenCacheKey(endpoint string, opts tokens3.AuthOptionsBuilder) string {
+	switch o := opts.(type) {
+	case *gophercloud.AuthOptions:
+		// TOTP passcodes can only be used once
+		if o.Passcode != "" {
+			return ""
+		}
+	case *tokens3.AuthOptions:
+		if o.Passcode != "" || o.TOTPSecret != "" || o.Receipt != "" {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	scope, err := opts.ToTokenV3ScopeMap()
+	if err != nil {
+		return ""
+	}
+	body, err := opts.ToTokenV3CreateMap(scope)
+	if err != nil {
+		return ""
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(endpoint + " " + string(b)))
+	return hex.EncodeToString(sum[:])
+}
+
+// createV3Token returns a token of the TokenCache of the client if there is a
+// valid one for key, or creates a new one and stores it in the cache. Errors
+// of the cache are ignored, since it's only an optimization.
+func createV3Token(client *gophercloud.ProviderClient, v3Client *gophercloud.ServiceClient, key string, opts tokens3.AuthOptionsBuilder) tokens3.CreateResult {
+	cache := client.TokenCache
+	if cache == nil || key == "" {
+		return tokens3.Create(v3Client, opts)
+	}
+
+	// serialize the authentications of concurrent clients, so that the
+	// ones waiting for the lock find the token created by the first one
+	if locker, ok := cache.(gophercloud.TokenCacheLocker); ok {
+		if unlock, err := locker.Lock(key); err == nil {
+			defer unlock()
+		}
+	}
+
+	// a cached token must outlive the refresh margin of the client, or it
+	// would be refreshed again right away
+	margin := gophercloud.DefaultTokenCacheMargin
+	if client.TokenRefreshMargin > margin {
+		margin = client.TokenRefreshMargin
+	}
+
+	if token, err := cache.Get(key); err == nil && token != nil && time.Until(token.ExpiresAt) > margin {
+		var body interface{}
+		if err := json.Unmarshal(token.Body, &body); err == nil {
+			var r tokens3.CreateResult
+			r.Body = body
+			r.Header = http.Header{}
+			r.Header.Set("X-Subject-Token", token.ID)
+			return r
+		}
+	}
+
+	r := tokens3.Create(v3Client, opts)
+	if r.Err != nil {
+		return r
+	}
+	token, err := r.ExtractToken()
+	if err != nil {
+		return r
+	}
+	body, err := json.Marshal(r.Body)
+	if err != nil {
+		return r
+	}
+	cache.Set(key, &gophercloud.CachedToken{
+		ID:        token.ID,
+		ExpiresAt: token.ExpiresAt,
+		Body:      body,
+	})
+	return r
+}
+
+// evictCachedToken removes the token of the client from its TokenCache, if
+// it's the one stored for key. It's called before reauthenticating, since the
+// token was rejected.
+func evictCachedToken(client *gophercloud.ProviderClient, key string) {
+	cache := client.TokenCache
+	if cache == nil || key == "" {
+		return
+	}
+	if token, err := cache.Get(key); err == nil && token != nil && token.ID == client.Token() {
+		cache.Delete(key)
+	}
+}
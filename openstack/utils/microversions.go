@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// SupportedMicroversions is the range of microversions supported by a service.
+type SupportedMicroversions struct {
+	MinMajor int
+	MinMinor int
+	MaxMajor int
+	MaxMinor int
+}
+
+// Min returns the minimum microversion, e.g. "2.1".
+func (s SupportedMicroversions) Min() string {
+	return fmt.Sprintf("%d.%d", s.MinMajor, s.MinMinor)
+}
+
+// Max returns the maximum microversion, e.g. "2.90".
+func (s SupportedMicroversions) Max() string {
+	return fmt.Sprintf("%d.%d", s.MaxMajor, s.MaxMinor)
+}
+
+// IsSupported reports whether the microversion is within the range.
+func (s SupportedMicroversions) IsSupported(version string) (bool, error) {
+	major, minor, err := gophercloud.ParseMicroversion(version)
+	if err != nil {
+		return false, err
+	}
+	return compareMicroversions(major, minor, s.MinMajor, s.MinMinor) >= 0 &&
+		compareMicroversions(major, minor, s.MaxMajor, s.MaxMinor) <= 0, nil
+}
+
+func compareMicroversions(major1, minor1, major2, minor2 int) int {
+	switch {
+	case major1 != major2:
+		return major1 - major2
+	default:
+		return minor1 - minor2
+	}
+}
+
+var versionSegmentRe = regexp.MustCompile(`/v[0-9.]+/?`)
+
+// versionedRoot returns the root of the versioned API of an endpoint, e.g.
+// https://cinder.example.com/v3/ for https://cinder.example.com/v3/{project_id}/.
+func versionedRoot(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	u.RawQuery, u.Fragment = "", ""
+	if loc := versionSegmentRe.FindStringIndex(u.Path); loc != nil {
+		u.Path = strings.TrimSuffix(u.Path[:loc[1]], "/") + "/"
+	}
+	return u.String(), nil
+}
+
+/*
+GetSupportedMicroversions queries the root of the versioned API of the service,
+and returns the range of microversions it supports. It understands the version
+documents of Nova, Cinder, Manila and Ironic.
+
+	supported, err := utils.GetSupportedMicroversions(computeClient)
+	fmt.Println(supported.Max())
+*/
+func GetSupportedMicroversions(client *gophercloud.ServiceClient) (SupportedMicroversions, error) {
+	type valueResp struct {
+		ID         string `json:"id"`
+		Status     string `json:"status"`
+		Version    string `json:"version"`
+		MinVersion string `json:"min_version"`
+	}
+
+	type response struct {
+		Version        valueResp   `json:"version"`
+		Versions       []valueResp `json:"versions"`
+		DefaultVersion valueResp   `json:"default_version"`
+	}
+
+	var supported SupportedMicroversions
+
+	root, err := versionedRoot(client.Endpoint)
+	if err != nil {
+		return supported, err
+	}
+
+	var resp response
+	r, err := client.Get(root, &resp, &gophercloud.RequestOpts{
+		OkCodes: []int{200, 300},
+	})
+	if err != nil {
+		return supported, err
+	}
+
+	minVersion, maxVersion := resp.Version.MinVersion, resp.Version.Version
+	if maxVersion == "" {
+		// pick the document matching the major version of the endpoint, or the
+		// current one
+		major := strings.Trim(versionSegmentRe.FindString(root), "/")
+		for _, v := range append([]valueResp{resp.DefaultVersion}, resp.Versions...) {
+			if v.Version == "" {
+				continue
+			}
+			if v.ID == major || strings.HasPrefix(v.ID, major+".") || strings.EqualFold(v.Status, "CURRENT") {
+				minVersion, maxVersion = v.MinVersion, v.Version
+				break
+			}
+		}
+	}
+
+	// Ironic also exposes the range in headers
+	if maxVersion == "" && r != nil {
+		minVersion = r.Header.Get("X-OpenStack-Ironic-API-Minimum-Version")
+		maxVersion = r.Header.Get("X-OpenStack-Ironic-API-Maximum-Version")
+	}
+
+	if maxVersion == "" {
+		return supported, ErrMicroversionsNotSupported{Endpoint: root}
+	}
+	if minVersion == "" {
+		minVersion = maxVersion
+	}
+
+	if supported.MinMajor, supported.MinMinor, err = gophercloud.ParseMicroversion(minVersion); err != nil {
+		return supported, err
+	}
+	if supported.MaxMajor, supported.MaxMinor, err = gophercloud.ParseMicroversion(maxVersion); err != nil {
+		return supported, err
+	}
+
+	return supported, nil
+}
+
+/*
+NegotiateMicroversion picks the highest microversion supported by both the
+service and the client, whose maximum is clientMax, and sets it as the
+Microversion of the ServiceClient. An empty clientMax, or "latest", selects the
+maximum microversion of the service.
+
+	computeClient, err := openstack.NewComputeV2(provider, gophercloud.EndpointOpts{})
+	version, err := utils.NegotiateMicroversion(computeClient, "2.90")
+
+	if computeClient.SupportsMicroversion("2.79") {
+		// boot from volume with delete_on_termination
+	}
+*/
+func NegotiateMicroversion(client *gophercloud.ServiceClient, clientMax string) (string, error) {
+	supported, err := GetSupportedMicroversions(client)
+	if err != nil {
+		return "", err
+	}
+
+	version := supported.Max()
+	if clientMax != "" && clientMax != "latest" {
+		major, minor, err := gophercloud.ParseMicroversion(clientMax)
+		if err != nil {
+			return "", err
+		}
+		if compareMicroversions(major, minor, supported.MinMajor, supported.MinMinor) < 0 {
+			return "", ErrNoCommonMicroversion{ClientMax: clientMax, Supported: supported}
+		}
+		if compareMicroversions(major, minor, supported.MaxMajor, supported.MaxMinor) < 0 {
+			version = fmt.Sprintf("%d.%d", major, minor)
+		}
+	}
+
+	client.Microversion = version
+	return version, nil
+}
+
+// ErrMicroversionsNotSupported is the error when a service doesn't advertise
+// microversions.
+type ErrMicroversionsNotSupported struct {
+	gophercloud.BaseError
+	Endpoint string
+}
+
+func (e ErrMicroversionsNotSupported) Error() string {
+	return fmt.Sprintf("No microversion range found in the version document of %s", e.Endpoint)
+}
+
+// ErrNoCommonMicroversion is the error when the client and the service don't
+// support any common microversion.
+type ErrNoCommonMicroversion struct {
+	gophercloud.BaseError
+	ClientMax string
+	Supported SupportedMicroversions
+}
+
+func (e ErrNoCommonMicroversion) Error() string {
+	return fmt.Sprintf("The client maximum microversion %s is lower than the minimum microversion %s of the service",
+		e.ClientMax, e.Supported.Min())
+}
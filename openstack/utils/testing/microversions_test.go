@@ -0,0 +1,131 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/utils"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+func setupComputeVersionHandler(t *testing.T) {
+	th.Mux.HandleFunc("/v2.1/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `
+			{
+				"version": {
+					"id": "v2.1",
+					"status": "CURRENT",
+					"version": "2.90",
+					"min_version": "2.1"
+				}
+			}
+		`)
+	})
+}
+
+func setupBlockStorageVersionHandler(t *testing.T) {
+	th.Mux.HandleFunc("/v3/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultipleChoices)
+		fmt.Fprint(w, `
+			{
+				"versions": [
+					{
+						"id": "v3.0",
+						"status": "CURRENT",
+						"version": "3.64",
+						"min_version": "3.0"
+					}
+				]
+			}
+		`)
+	})
+}
+
+func TestGetSupportedMicroversions(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	setupBlockStorageVersionHandler(t)
+
+	c := client.ServiceClient()
+	c.Endpoint = th.Endpoint() + "v3/0123456789abcdef/"
+
+	supported, err := utils.GetSupportedMicroversions(c)
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, utils.SupportedMicroversions{
+		MinMajor: 3,
+		MinMinor: 0,
+		MaxMajor: 3,
+		MaxMinor: 64,
+	}, supported)
+
+	ok, err := supported.IsSupported("3.27")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, ok)
+
+	ok, err = supported.IsSupported("3.65")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, false, ok)
+
+	_, err = supported.IsSupported("3")
+	th.AssertEquals(t, true, err != nil)
+}
+
+func TestNegotiateMicroversion(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	setupComputeVersionHandler(t)
+
+	testCases := []struct {
+		clientMax string
+		expected  string
+	}{
+		{"", "2.90"},
+		{"latest", "2.90"},
+		{"2.79", "2.79"},
+		{"2.100", "2.90"},
+	}
+
+	for _, tc := range testCases {
+		c := client.ServiceClient()
+		c.Endpoint = th.Endpoint() + "v2.1/"
+
+		version, err := utils.NegotiateMicroversion(c, tc.clientMax)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, tc.expected, version)
+		th.AssertEquals(t, tc.expected, c.Microversion)
+	}
+
+	c := client.ServiceClient()
+	c.Endpoint = th.Endpoint() + "v2.1/"
+	_, err := utils.NegotiateMicroversion(c, "1.0")
+	if _, ok := err.(utils.ErrNoCommonMicroversion); !ok {
+		t.Fatalf("expected an ErrNoCommonMicroversion, got %T: %v", err, err)
+	}
+	th.AssertEquals(t, "", c.Microversion)
+}
+
+func TestGetSupportedMicroversionsNotSupported(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"resources": []}`)
+	})
+
+	c := &gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{TokenID: client.TokenID},
+		Endpoint:       th.Endpoint() + "v2.0/",
+	}
+	_, err := utils.GetSupportedMicroversions(c)
+	if _, ok := err.(utils.ErrMicroversionsNotSupported); !ok {
+		t.Fatalf("expected an ErrMicroversionsNotSupported, got %T: %v", err, err)
+	}
+}
@@ -0,0 +1,235 @@
+package pagination
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// ExtractItemsFunc extracts the items of a single page as a slice. It is called
+// by the prefetching goroutine when IteratorOpts.Prefetch is set. It usually
+// wraps the Extract function of a resource package, e.g.:
+//
+//	func(page pagination.Page) (interface{}, error) {
+//		return servers.ExtractServers(page)
+//	}
+type ExtractItemsFunc func(Page) (interface{}, error)
+
+// IteratorOpts customizes the behavior of an Iterator.
+type IteratorOpts struct {
+	// Prefetch, if true, fetches the next page in a separate goroutine while
+	// the caller consumes the items of the current page. At most one page is
+	// fetched ahead, and never a page past Limit.
+	Prefetch bool
+
+	// Limit is the maximum number of items returned by the Iterator. Once the
+	// pages hold that many items, no further pages are requested, with or
+	// without Prefetch. Zero means no limit.
+	Limit int
+}
+
+// Iterator is a cursor over the individual items of a paginated collection.
+// Unlike AllPages, it only keeps the current page (and, when prefetching, the
+// next one) in memory.
+//
+// Iterators are not safe for concurrent use. Call Close when stopping before
+// Next has returned false to release a prefetching goroutine.
+//
+//	it := servers.List(client, nil).Iterator(func(page pagination.Page) (interface{}, error) {
+//		return servers.ExtractServers(page)
+//	}, &pagination.IteratorOpts{Prefetch: true})
+//	defer it.Close()
+//
+//	for it.Next() {
+//		server := it.Item().(servers.Server)
+//	}
+//	if err := it.Err(); err != nil {
+//		panic(err)
+//	}
+type Iterator struct {
+	opts IteratorOpts
+
+	// next returns the items of the next page, or an invalid Value when there
+	// are no more pages.
+	next func() (reflect.Value, error)
+
+	items reflect.Value
+	index int
+	count int
+	item  interface{}
+	err   error
+	done  bool
+
+	// pages and stop are only used when prefetching.
+	pages chan fetchedPage
+	stop  chan struct{}
+}
+
+type fetchedPage struct {
+	items reflect.Value
+	err   error
+}
+
+// Iterator returns an Iterator over the items of the collection, as returned
+// by extract for each page. opts may be nil.
+func (p Pager) Iterator(extract ExtractItemsFunc, opts *IteratorOpts) *Iterator {
+	it := &Iterator{}
+	if opts != nil {
+		it.opts = *opts
+	}
+
+	if p.Err != nil {
+		it.err = p.Err
+		it.done = true
+		return it
+	}
+
+	fetch := p.pageFetcher()
+	next := func() (reflect.Value, error) {
+		page, err := fetch()
+		if page == nil || err != nil {
+			return reflect.Value{}, err
+		}
+		return extractItems(extract, page)
+	}
+	if !it.opts.Prefetch {
+		it.next = next
+		return it
+	}
+
+	it.pages = make(chan fetchedPage)
+	it.stop = make(chan struct{})
+	go func() {
+		defer close(it.pages)
+		fetched := 0
+		for {
+			items, err := next()
+			select {
+			case it.pages <- fetchedPage{items, err}:
+			case <-it.stop:
+				return
+			}
+			if !items.IsValid() || err != nil {
+				return
+			}
+			// the next page would be past the limit
+			fetched += items.Len()
+			if it.opts.Limit > 0 && fetched >= it.opts.Limit {
+				return
+			}
+		}
+	}()
+	it.next = func() (reflect.Value, error) {
+		f, ok := <-it.pages
+		if !ok {
+			return reflect.Value{}, nil
+		}
+		return f.items, f.err
+	}
+	return it
+}
+
+// extractItems returns the items of page, as returned by extract.
+func extractItems(extract ExtractItemsFunc, page Page) (reflect.Value, error) {
+	items, err := extract(page)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		err := gophercloud.ErrUnexpectedType{}
+		err.Expected = "slice"
+		err.Actual = fmt.Sprintf("%T", items)
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
+
+// pageFetcher returns a function which fetches the pages of the collection
+// one after the other, and returns a nil page after the last one.
+func (p Pager) pageFetcher() func() (Page, error) {
+	currentURL := p.initialURL
+	return func() (Page, error) {
+		if currentURL == "" {
+			return nil, nil
+		}
+		page, err := p.fetchNextPage(currentURL)
+		if err != nil {
+			currentURL = ""
+			return nil, err
+		}
+
+		empty, err := page.IsEmpty()
+		if err != nil || empty {
+			currentURL = ""
+			return nil, err
+		}
+
+		currentURL, err = page.NextPageURL()
+		if err != nil {
+			currentURL = ""
+			return nil, err
+		}
+		return page, nil
+	}
+}
+
+// Next advances the Iterator to the next item, fetching the next page if
+// needed. It returns false when the collection is exhausted, the limit is
+// reached, or an error occurred. Check Err to tell them apart.
+func (it *Iterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if it.opts.Limit > 0 && it.count >= it.opts.Limit {
+		it.Close()
+		return false
+	}
+
+	for !it.items.IsValid() || it.index >= it.items.Len() {
+		items, err := it.next()
+		if err != nil {
+			it.err = err
+			it.Close()
+			return false
+		}
+		if !items.IsValid() {
+			it.Close()
+			return false
+		}
+		it.items = items
+		it.index = 0
+	}
+
+	it.item = it.items.Index(it.index).Interface()
+	it.index++
+	it.count++
+	return true
+}
+
+// Item returns the current item. It is only valid after a call to Next
+// returned true.
+func (it *Iterator) Item() interface{} {
+	return it.item
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close stops the Iterator. Subsequent calls to Next return false. If a page
+// is being prefetched, its result is discarded. It is safe to call Close more
+// than once.
+func (it *Iterator) Close() {
+	if it.done {
+		return
+	}
+	it.done = true
+	it.items = reflect.Value{}
+	if it.stop != nil {
+		close(it.stop)
+	}
+}
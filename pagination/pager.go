@@ -1,6 +1,7 @@
 package pagination
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -69,6 +70,17 @@ func (p Pager) WithPageCreator(createPage func(r PageResult) Page) Pager {
 	}
 }
 
+// WithContext returns a copy of the Pager whose page requests are bound to ctx.
+// Cancelling ctx aborts the iteration with the context's error. Pagers holding
+// an error are returned unchanged.
+func (p Pager) WithContext(ctx context.Context) Pager {
+	if p.Err != nil || p.client == nil {
+		return p
+	}
+	p.client = p.client.WithContext(ctx)
+	return p
+}
+
 func (p Pager) fetchNextPage(url string) (Page, error) {
 	resp, err := Request(p.client, p.Headers, url)
 	if err != nil {
@@ -0,0 +1,110 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/pagination"
+	"github.com/gophercloud/gophercloud/testhelper"
+)
+
+func extractLinkedInts(page pagination.Page) (interface{}, error) {
+	return ExtractLinkedInts(page)
+}
+
+func TestIteratorLinked(t *testing.T) {
+	pager := createLinked(t)
+	defer testhelper.TeardownHTTP()
+
+	var actual []int
+	it := pager.Iterator(extractLinkedInts, nil)
+	for it.Next() {
+		actual = append(actual, it.Item().(int))
+	}
+	testhelper.AssertNoErr(t, it.Err())
+
+	expected := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	testhelper.CheckDeepEquals(t, expected, actual)
+}
+
+func TestIteratorLinkedPrefetch(t *testing.T) {
+	pager := createLinked(t)
+	defer testhelper.TeardownHTTP()
+
+	var actual []int
+	it := pager.Iterator(extractLinkedInts, &pagination.IteratorOpts{Prefetch: true})
+	defer it.Close()
+	for it.Next() {
+		actual = append(actual, it.Item().(int))
+	}
+	testhelper.AssertNoErr(t, it.Err())
+
+	expected := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	testhelper.CheckDeepEquals(t, expected, actual)
+}
+
+func TestIteratorLinkedLimit(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		testIteratorLinkedLimit(t, prefetch)
+	}
+}
+
+func testIteratorLinkedLimit(t *testing.T, prefetch bool) {
+	testhelper.SetupHTTP()
+	defer testhelper.TeardownHTTP()
+
+	var requests int32
+	testhelper.Mux.HandleFunc("/page1", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprintf(w, `{ "ints": [1, 2, 3], "links": { "next": "%s/page2" } }`, testhelper.Server.URL)
+	})
+	testhelper.Mux.HandleFunc("/page2", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprintf(w, `{ "ints": [4, 5, 6], "links": { "next": null } }`)
+	})
+
+	createPage := func(r pagination.PageResult) pagination.Page {
+		return LinkedPageResult{pagination.LinkedPageBase{PageResult: r}}
+	}
+	pager := pagination.NewPager(createClient(), testhelper.Server.URL+"/page1", createPage)
+
+	var actual []int
+	it := pager.Iterator(extractLinkedInts, &pagination.IteratorOpts{Limit: 3, Prefetch: prefetch})
+	for it.Next() {
+		actual = append(actual, it.Item().(int))
+	}
+	testhelper.AssertNoErr(t, it.Err())
+	testhelper.CheckDeepEquals(t, []int{1, 2, 3}, actual)
+
+	// the second page is never requested, even when prefetching
+	if prefetch {
+		time.Sleep(50 * time.Millisecond)
+	}
+	testhelper.CheckEquals(t, int32(1), atomic.LoadInt32(&requests))
+
+	// the iterator is exhausted once closed
+	testhelper.CheckEquals(t, false, it.Next())
+}
+
+func TestIteratorError(t *testing.T) {
+	testhelper.SetupHTTP()
+	defer testhelper.TeardownHTTP()
+
+	testhelper.Mux.HandleFunc("/page1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	createPage := func(r pagination.PageResult) pagination.Page {
+		return LinkedPageResult{pagination.LinkedPageBase{PageResult: r}}
+	}
+	pager := pagination.NewPager(createClient(), testhelper.Server.URL+"/page1", createPage)
+
+	it := pager.Iterator(extractLinkedInts, &pagination.IteratorOpts{Prefetch: true})
+	testhelper.CheckEquals(t, false, it.Next())
+	testhelper.AssertErr(t, it.Err())
+}
@@ -1,6 +1,8 @@
 package testing
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -110,3 +112,30 @@ func TestAllPagesLinked(t *testing.T) {
 	testhelper.AssertNoErr(t, err)
 	testhelper.CheckDeepEquals(t, expected, actual)
 }
+
+func TestEnumerateLinkedWithContext(t *testing.T) {
+	pager := createLinked(t)
+	defer testhelper.TeardownHTTP()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	callCount := 0
+	err := pager.WithContext(ctx).EachPage(func(page pagination.Page) (bool, error) {
+		callCount++
+		// cancelling while handling the first page aborts the next request
+		cancel()
+		return true, nil
+	})
+	testhelper.AssertErr(t, err)
+	testhelper.CheckEquals(t, 1, callCount)
+}
+
+func TestWithContextErrorPager(t *testing.T) {
+	pager := pagination.Pager{Err: errors.New("invalid options")}
+
+	err := pager.WithContext(context.Background()).EachPage(func(page pagination.Page) (bool, error) {
+		t.Fatal("the handler of an error pager should not be called")
+		return false, nil
+	})
+	testhelper.CheckEquals(t, "invalid options", err.Error())
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 )
 
 // DefaultUserAgent is the default User-Agent string set in the request header.
@@ -73,6 +74,14 @@ type ProviderClient struct {
 	// its constituent services.
 	EndpointLocator EndpointLocator
 
+	// EndpointOverrides maps service types to the endpoints used by the service
+	// client constructors instead of the ones of the service catalog, e.g. to
+	// pin the compute service to a given URL. The endpoints may contain the
+	// %(project_id)s placeholder, which is replaced with the project ID of the
+	// token. An override also applies to the aliases of its service type, see
+	// ServiceTypeAliases.
+	EndpointOverrides map[string]string
+
 	// HTTPClient allows users to interject arbitrary http, https, or other transit behaviors.
 	HTTPClient http.Client
 
@@ -84,6 +93,11 @@ type ProviderClient struct {
 	// authentication functions for different Identity service versions.
 	ReauthFunc func() error
 
+	// ReauthContextFunc is like ReauthFunc, but receives the context of the
+	// request that triggered the reauthentication. When set, it takes
+	// precedence over ReauthFunc.
+	ReauthContextFunc func(context.Context) error
+
 	// Throwaway determines whether if this client is a throw-away client. It's a copy of user's provider client
 	// with the token and reauth func zeroed. Such client can be used to perform reauthorization.
 	Throwaway bool
@@ -97,6 +111,32 @@ type ProviderClient struct {
 	// MaxBackoffRetries set the maximum number of backoffs. When not set, defaults to DefaultMaxBackoffRetries
 	MaxBackoffRetries uint
 
+	// RetryPolicy, if set, retries requests that failed because of a transient
+	// server or network error. It can be overridden by RequestOpts.RetryPolicy.
+	RetryPolicy *RetryPolicy
+
+	// Logger, if set, receives a RequestLog for every HTTP request sent by the client.
+	// Credentials, tokens and secrets are redacted from the logged URLs, headers and bodies.
+	Logger Logger
+
+	// LogBodies specifies whether the JSON request and response bodies are passed to the Logger.
+	LogBodies bool
+
+	// Instrumentation, if set, holds callbacks invoked around every request, e.g. to record metrics
+	// or tracing spans.
+	Instrumentation *Instrumentation
+
+	// TokenCache, if set, is consulted by openstack.Authenticate before creating a Keystone v3
+	// token, and stores the created tokens, so that they can be reused by other clients until
+	// they are about to expire.
+	TokenCache TokenCache
+
+	// TokenRefreshMargin, if set, makes the client reauthenticate before sending a request when
+	// its token expires within the margin, instead of waiting for a 401 response. This matters for
+	// requests whose RawBody can't be sent again, such as large uploads. The expiration time is
+	// known when the token was set with SetTokenAndAuthResult.
+	TokenRefreshMargin time.Duration
+
 	// A general failed request handler method - this is always called in the end if a request failed. Leave as nil
 	// to abort when an error is encountered.
 	RetryFunc RetryFunc
@@ -110,6 +150,9 @@ type ProviderClient struct {
 	reauthmut *reauthlock
 
 	authResult AuthResult
+
+	// expiresAt is the expiration time of the token, it is zero when unknown.
+	expiresAt time.Time
 }
 
 // reauthlock represents a set of attributes used to help in the reauthentication process.
@@ -196,6 +239,16 @@ func (client *ProviderClient) Token() string {
 	return client.TokenID
 }
 
+// TokenExpiresAt returns the expiration time of the token, or the zero time if
+// it is unknown, e.g. because the token was set with SetToken.
+func (client *ProviderClient) TokenExpiresAt() time.Time {
+	if client.mut != nil {
+		client.mut.RLock()
+		defer client.mut.RUnlock()
+	}
+	return client.expiresAt
+}
+
 // SetToken safely sets the value of the auth token in the ProviderClient. Applications may
 // use this method in a custom ReauthFunc.
 //
@@ -207,6 +260,7 @@ func (client *ProviderClient) SetToken(t string) {
 	}
 	client.TokenID = t
 	client.authResult = nil
+	client.expiresAt = time.Time{}
 }
 
 // SetTokenAndAuthResult safely sets the value of the auth token in the
@@ -214,12 +268,17 @@ func (client *ProviderClient) SetToken(t string) {
 // token creation request. Applications may call this in a custom ReauthFunc.
 func (client *ProviderClient) SetTokenAndAuthResult(r AuthResult) error {
 	tokenID := ""
+	var expiresAt time.Time
 	var err error
 	if r != nil {
 		tokenID, err = r.ExtractTokenID()
 		if err != nil {
 			return err
 		}
+		if e, ok := r.(expiringAuthResult); ok {
+			// an unknown expiration time only disables the proactive refresh
+			expiresAt, _ = e.ExtractExpiresAt()
+		}
 	}
 
 	if client.mut != nil {
@@ -228,6 +287,7 @@ func (client *ProviderClient) SetTokenAndAuthResult(r AuthResult) error {
 	}
 	client.TokenID = tokenID
 	client.authResult = r
+	client.expiresAt = expiresAt
 	return nil
 }
 
@@ -244,6 +304,7 @@ func (client *ProviderClient) CopyTokenFrom(other *ProviderClient) {
 	}
 	client.TokenID = other.TokenID
 	client.authResult = other.authResult
+	client.expiresAt = other.expiresAt
 }
 
 // IsThrowaway safely reads the value of the client Throwaway field.
@@ -270,12 +331,19 @@ func (client *ProviderClient) SetThrowaway(v bool) {
 // reauthenticated in the meantime. If no previous token is known, an empty
 // string should be passed instead to force unconditional reauthentication.
 func (client *ProviderClient) Reauthenticate(previousToken string) error {
-	if client.ReauthFunc == nil {
+	return client.ReauthenticateWithContext(client.Context, previousToken)
+}
+
+// ReauthenticateWithContext is like Reauthenticate, but passes ctx to
+// client.ReauthContextFunc, if set. A caller waiting for a reauthentication
+// started elsewhere stops waiting when ctx is done.
+func (client *ProviderClient) ReauthenticateWithContext(ctx context.Context, previousToken string) error {
+	if !client.canReauth() {
 		return nil
 	}
 
 	if client.reauthmut == nil {
-		return client.ReauthFunc()
+		return client.reauth(ctx)
 	}
 
 	future := newReauthFuture()
@@ -290,13 +358,21 @@ func (client *ProviderClient) Reauthenticate(previousToken string) error {
 
 	// If Reauthenticate is running elsewhere, wait for its result.
 	if ongoing != nil {
-		return ongoing.Get()
+		if ctx == nil {
+			return ongoing.Get()
+		}
+		select {
+		case <-ongoing.done:
+			return ongoing.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	// Perform the actual reauthentication.
 	var err error
 	if previousToken == "" || client.TokenID == previousToken {
-		err = client.ReauthFunc()
+		err = client.reauth(ctx)
 	} else {
 		err = nil
 	}
@@ -310,6 +386,19 @@ func (client *ProviderClient) Reauthenticate(previousToken string) error {
 	return err
 }
 
+// canReauth reports whether either ReauthFunc or ReauthContextFunc is set.
+func (client *ProviderClient) canReauth() bool {
+	return client.ReauthContextFunc != nil || client.ReauthFunc != nil
+}
+
+// reauth calls ReauthContextFunc if set, falling back to ReauthFunc.
+func (client *ProviderClient) reauth(ctx context.Context) error {
+	if client.ReauthContextFunc != nil {
+		return client.ReauthContextFunc(ctx)
+	}
+	return client.ReauthFunc()
+}
+
 // RequestOpts customizes the behavior of the provider.Request() method.
 type RequestOpts struct {
 	// JSONBody, if provided, will be encoded as JSON and used as the body of the HTTP request. The
@@ -335,6 +424,18 @@ type RequestOpts struct {
 	// KeepResponseBody specifies whether to keep the HTTP response body. Usually used, when the HTTP
 	// response body is considered for further use. Valid when JSONResponse is nil.
 	KeepResponseBody bool
+	// Context, if provided, is attached to the HTTP request instead of ProviderClient.Context. It
+	// is also passed to RetryFunc, RetryBackoffFunc and ReauthContextFunc, so that a single call
+	// can be cancelled or given a deadline without modifying the shared ProviderClient.
+	Context context.Context
+	// RetryPolicy, if provided, overrides ProviderClient.RetryPolicy for this request only.
+	RetryPolicy *RetryPolicy
+
+	// serviceType, microversion and resourceBase are set by ServiceClient.Request and passed to
+	// the Instrumentation callbacks.
+	serviceType  string
+	microversion string
+	resourceBase string
 }
 
 // requestState contains temporary state for a single ProviderClient.Request() call.
@@ -346,20 +447,119 @@ type requestState struct {
 	hasReauthenticated bool
 	// Retry-After backoff counter, increments during each backoff call
 	retries uint
+	// attempts counts the requests sent so far, it is used by the RetryPolicy.
+	attempts uint
+	// ctx is the context of the request, either RequestOpts.Context or ProviderClient.Context.
+	ctx context.Context
+	// retry is set by doRequest when the request has to be sent again.
+	retry bool
+	// retryErr is the error which caused the request to be sent again. It is nil when the request
+	// is sent again after reauthentication.
+	retryErr error
+	// retried counts the times the request was sent again because of an error.
+	retried uint
+	// reauths counts the reauthentications triggered by the request.
+	reauths uint
+	// statusCode is the status code of the last response received.
+	statusCode int
+	// info is passed to the Instrumentation callbacks, it is only set when
+	// ProviderClient.Instrumentation is set.
+	info *RequestInfo
+}
+
+// retryOn records that the request has to be sent again because of err.
+func (state *requestState) retryOn(err error) {
+	state.retry = true
+	state.retryErr = err
 }
 
 var applicationJSON = "application/json"
 
 // Request performs an HTTP request using the ProviderClient's current HTTPClient. An authentication
-// header will automatically be provided.
+// header will automatically be provided. The request uses options.Context if set, and
+// ProviderClient.Context otherwise.
 func (client *ProviderClient) Request(method, url string, options *RequestOpts) (*http.Response, error) {
-	return client.doRequest(method, url, options, &requestState{
+	ctx := client.Context
+	if options.Context != nil {
+		ctx = options.Context
+	}
+	state := &requestState{
 		hasReauthenticated: false,
-	})
+		ctx:                ctx,
+	}
+
+	inst := client.Instrumentation
+	if inst == nil {
+		return client.request(method, url, options, state)
+	}
+
+	info := requestInfo(method, url, options)
+	state.info = &info
+	if inst.BeforeRequest != nil {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if c := inst.BeforeRequest(ctx, info); c != nil {
+			state.ctx = c
+		}
+	}
+
+	start := time.Now()
+	resp, err := client.request(method, url, options, state)
+	if inst.AfterRequest != nil {
+		stats := RequestStats{
+			StatusCode: state.statusCode,
+			Attempts:   state.attempts,
+			Retries:    state.retried,
+			Reauths:    state.reauths,
+			Duration:   time.Since(start),
+			Err:        err,
+		}
+		if code := statusCodeOf(err); code != 0 {
+			stats.StatusCode = code
+		}
+		inst.AfterRequest(state.ctx, info, stats)
+	}
+	return resp, err
 }
 
+// request sends the request until it succeeds or can't be retried anymore.
+func (client *ProviderClient) request(method, url string, options *RequestOpts, state *requestState) (*http.Response, error) {
+	client.refreshExpiringToken(state.ctx)
+	for {
+		state.retry = false
+		state.retryErr = nil
+		resp, err := client.doRequest(method, url, options, state)
+		if state.retry {
+			if state.retryErr != nil {
+				state.retried = state.retried + 1
+				if state.info != nil && client.Instrumentation.OnRetry != nil {
+					client.Instrumentation.OnRetry(state.ctx, *state.info, state.attempts, state.retryErr)
+				}
+			}
+			if options.RawBody != nil {
+				if seeker, ok := options.RawBody.(io.Seeker); ok {
+					seeker.Seek(0, io.SeekStart)
+				}
+			}
+			continue
+		}
+
+		if err != nil && state.hasReauthenticated {
+			e := &ErrErrorAfterReauthentication{}
+			e.ErrOriginal = err
+			return nil, e
+		}
+		return resp, err
+	}
+}
+
+// doRequest sends a single HTTP request. If the request has to be sent again,
+// e.g. after reauthentication or because a retry was allowed, it sets
+// state.retry and the returned values must be discarded.
 func (client *ProviderClient) doRequest(method, url string, options *RequestOpts, state *requestState) (*http.Response, error) {
 	var body io.Reader
+	var rendered []byte
 	var contentType *string
 
 	// Derive the content body by either encoding an arbitrary object as JSON, or by taking a provided
@@ -369,7 +569,8 @@ func (client *ProviderClient) doRequest(method, url string, options *RequestOpts
 			return nil, errors.New("please provide only one of JSONBody or RawBody to gophercloud.Request()")
 		}
 
-		rendered, err := json.Marshal(options.JSONBody)
+		var err error
+		rendered, err = json.Marshal(options.JSONBody)
 		if err != nil {
 			return nil, err
 		}
@@ -392,8 +593,8 @@ func (client *ProviderClient) doRequest(method, url string, options *RequestOpts
 	if err != nil {
 		return nil, err
 	}
-	if client.Context != nil {
-		req = req.WithContext(client.Context)
+	if state.ctx != nil {
+		req = req.WithContext(state.ctx)
 	}
 
 	// Populate the request headers. Apply options.MoreHeaders last, to give the caller the chance to
@@ -420,17 +621,40 @@ func (client *ProviderClient) doRequest(method, url string, options *RequestOpts
 	prereqtok := req.Header.Get("X-Auth-Token")
 
 	// Issue the request.
+	state.attempts = state.attempts + 1
+	start := time.Now()
 	resp, err := client.HTTPClient.Do(req)
+	if err == nil {
+		state.statusCode = resp.StatusCode
+	}
+	if client.Logger != nil {
+		var respBody []byte
+		if err == nil && client.LogBodies && !options.KeepResponseBody &&
+			strings.HasPrefix(resp.Header.Get("Content-Type"), applicationJSON) {
+			// buffer the body so that it can be both logged and consumed below
+			respBody, err = ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+		}
+		client.logRequest(req, rendered, resp, respBody, start, err)
+		if err != nil && resp != nil {
+			return nil, err
+		}
+	}
 	if err != nil {
+		if client.retryPolicyAllows(method, options, state, nil, err) {
+			return nil, nil
+		}
 		if client.RetryFunc != nil {
 			var e error
 			state.retries = state.retries + 1
-			e = client.RetryFunc(client.Context, method, url, options, err, state.retries)
+			e = client.RetryFunc(state.ctx, method, url, options, err, state.retries)
 			if e != nil {
 				return nil, e
 			}
 
-			return client.doRequest(method, url, options, state)
+			state.retryOn(err)
+			return nil, nil
 		}
 		return nil, err
 	}
@@ -461,6 +685,7 @@ func (client *ProviderClient) doRequest(method, url string, options *RequestOpts
 			Body:           body,
 			ResponseHeader: resp.Header,
 		}
+		respErr.parseFault()
 
 		errType := options.ErrorContext
 		switch resp.StatusCode {
@@ -470,34 +695,22 @@ func (client *ProviderClient) doRequest(method, url string, options *RequestOpts
 				err = error400er.Error400(respErr)
 			}
 		case http.StatusUnauthorized:
-			if client.ReauthFunc != nil && !state.hasReauthenticated {
-				err = client.Reauthenticate(prereqtok)
+			if client.canReauth() && !state.hasReauthenticated {
+				reauthStart := time.Now()
+				err = client.ReauthenticateWithContext(state.ctx, prereqtok)
+				state.reauths = state.reauths + 1
+				if state.info != nil && client.Instrumentation.OnReauth != nil {
+					client.Instrumentation.OnReauth(state.ctx, *state.info, time.Since(reauthStart), err)
+				}
 				if err != nil {
 					e := &ErrUnableToReauthenticate{}
 					e.ErrOriginal = respErr
 					e.ErrReauth = err
 					return nil, e
 				}
-				if options.RawBody != nil {
-					if seeker, ok := options.RawBody.(io.Seeker); ok {
-						seeker.Seek(0, 0)
-					}
-				}
 				state.hasReauthenticated = true
-				resp, err = client.doRequest(method, url, options, state)
-				if err != nil {
-					switch err.(type) {
-					case *ErrUnexpectedResponseCode:
-						e := &ErrErrorAfterReauthentication{}
-						e.ErrOriginal = err.(*ErrUnexpectedResponseCode)
-						return nil, e
-					default:
-						e := &ErrErrorAfterReauthentication{}
-						e.ErrOriginal = err
-						return nil, e
-					}
-				}
-				return resp, nil
+				state.retry = true
+				return nil, nil
 			}
 			err = ErrDefault401{respErr}
 			if error401er, ok := errType.(Err401er); ok {
@@ -543,13 +756,14 @@ func (client *ProviderClient) doRequest(method, url string, options *RequestOpts
 				var e error
 
 				state.retries = state.retries + 1
-				e = f(client.Context, &respErr, err, state.retries)
+				e = f(state.ctx, &respErr, err, state.retries)
 
 				if e != nil {
 					return resp, e
 				}
 
-				return client.doRequest(method, url, options, state)
+				state.retryOn(err)
+				return nil, nil
 			}
 		case http.StatusInternalServerError:
 			err = ErrDefault500{respErr}
@@ -567,15 +781,20 @@ func (client *ProviderClient) doRequest(method, url string, options *RequestOpts
 			err = respErr
 		}
 
+		if client.retryPolicyAllows(method, options, state, &respErr, err) {
+			return nil, nil
+		}
+
 		if err != nil && client.RetryFunc != nil {
 			var e error
 			state.retries = state.retries + 1
-			e = client.RetryFunc(client.Context, method, url, options, err, state.retries)
+			e = client.RetryFunc(state.ctx, method, url, options, err, state.retries)
 			if e != nil {
 				return resp, e
 			}
 
-			return client.doRequest(method, url, options, state)
+			state.retryOn(err)
+			return nil, nil
 		}
 
 		return resp, err
@@ -594,12 +813,13 @@ func (client *ProviderClient) doRequest(method, url string, options *RequestOpts
 			if client.RetryFunc != nil {
 				var e error
 				state.retries = state.retries + 1
-				e = client.RetryFunc(client.Context, method, url, options, err, state.retries)
+				e = client.RetryFunc(state.ctx, method, url, options, err, state.retries)
 				if e != nil {
 					return resp, e
 				}
 
-				return client.doRequest(method, url, options, state)
+				state.retryOn(err)
+				return nil, nil
 			}
 			return nil, err
 		}
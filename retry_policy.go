@@ -0,0 +1,236 @@
+package gophercloud
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultRetryPolicyMaxAttempts is the default maximum number of times a
+	// request is sent by a RetryPolicy, including the first attempt.
+	DefaultRetryPolicyMaxAttempts = 3
+
+	// DefaultRetryPolicyInitialBackoff is the default delay before the first
+	// retry of a RetryPolicy.
+	DefaultRetryPolicyInitialBackoff = 500 * time.Millisecond
+
+	// DefaultRetryPolicyMaxBackoff is the default upper bound of the delay
+	// between two attempts of a RetryPolicy.
+	DefaultRetryPolicyMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy describes how requests failing because of a transient error are
+// retried. It can be set on ProviderClient.RetryPolicy, and overridden for a
+// single request with RequestOpts.RetryPolicy.
+//
+// Only requests using an idempotent HTTP method (GET, HEAD, PUT, DELETE,
+// OPTIONS) are retried, and only when they failed with a network error, such
+// as a refused connection or a timeout, or with one of StatusCodes. A request
+// with a RawBody is only retried if the RawBody implements io.Seeker, in which
+// case it is rewound before being sent again.
+//
+// The delay between attempts grows exponentially from InitialBackoff up to
+// MaxBackoff, with a random jitter. If the response carries a Retry-After
+// header, its value is used instead, up to MaxBackoff.
+//
+// RetryPolicy is consulted before RetryFunc, which is only called once the
+// RetryPolicy gave up.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, including
+	// the first attempt. When not set, defaults to DefaultRetryPolicyMaxAttempts.
+	// Set it to 1 to disable retries.
+	MaxAttempts uint
+
+	// InitialBackoff is the delay before the first retry. When not set, defaults
+	// to DefaultRetryPolicyInitialBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper bound of the delay between two attempts, which
+	// also caps the delay requested by a Retry-After header. When not set,
+	// defaults to DefaultRetryPolicyMaxBackoff.
+	MaxBackoff time.Duration
+
+	// StatusCodes is the list of HTTP status codes which are retried. When not
+	// set, defaults to 502, 503 and 504.
+	StatusCodes []int
+}
+
+var defaultRetryPolicyStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+func (p *RetryPolicy) maxAttempts() uint {
+	if p.MaxAttempts == 0 {
+		return DefaultRetryPolicyMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retriesStatusCode(code int) bool {
+	codes := p.StatusCodes
+	if codes == nil {
+		codes = defaultRetryPolicyStatusCodes
+	}
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return DefaultRetryPolicyMaxBackoff
+	}
+	return p.MaxBackoff
+}
+
+// backoff returns the delay to wait after the given number of failed
+// attempts. Half of the delay is randomized to avoid synchronized retries.
+func (p *RetryPolicy) backoff(attempts uint) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = DefaultRetryPolicyInitialBackoff
+	}
+	max := p.maxBackoff()
+
+	delay := initial
+	for i := uint(1); i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// retryPolicyAllows reports whether a failed request must be sent again
+// according to the effective RetryPolicy. If so, it waits for the backoff
+// delay and sets state.retry. respErr is nil when the request failed before
+// receiving a response.
+func (client *ProviderClient) retryPolicyAllows(method string, options *RequestOpts, state *requestState, respErr *ErrUnexpectedResponseCode, err error) bool {
+	policy := client.RetryPolicy
+	if options.RetryPolicy != nil {
+		policy = options.RetryPolicy
+	}
+	if policy == nil || state.attempts >= policy.maxAttempts() {
+		return false
+	}
+
+	if !isIdempotentMethod(method) {
+		return false
+	}
+	if options.RawBody != nil {
+		if _, ok := options.RawBody.(io.Seeker); !ok {
+			return false
+		}
+	}
+	if state.ctx != nil && state.ctx.Err() != nil {
+		return false
+	}
+
+	var delay time.Duration
+	if respErr != nil {
+		if !policy.retriesStatusCode(respErr.Actual) {
+			return false
+		}
+		if d, ok := parseRetryAfter(respErr.ResponseHeader.Get("Retry-After")); ok {
+			delay = d
+			if max := policy.maxBackoff(); delay > max {
+				delay = max
+			}
+		} else {
+			delay = policy.backoff(state.attempts)
+		}
+	} else {
+		if !isTransientError(err) {
+			return false
+		}
+		delay = policy.backoff(state.attempts)
+	}
+
+	if sleepWithContext(state.ctx, delay) != nil {
+		return false
+	}
+
+	state.retryOn(err)
+	return true
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// isTransientError reports whether err is a network error which is likely to
+// go away when the request is sent again.
+func isTransientError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.ParseUint(v, 10, 32); err == nil {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleepWithContext waits for d, or until ctx is done.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if ctx == nil {
+		time.Sleep(d)
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
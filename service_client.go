@@ -1,8 +1,11 @@
 package gophercloud
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -32,6 +35,26 @@ type ServiceClient struct {
 	// MoreHeaders allows users (or Gophercloud) to set service-wide headers on requests. Put another way,
 	// values set in this field will be set on all the HTTP requests the service client sends.
 	MoreHeaders map[string]string
+
+	// ctx is the context attached to every request sent by this service client, unless the
+	// request sets its own RequestOpts.Context. Use WithContext to set it.
+	ctx context.Context
+}
+
+// WithContext returns a shallow copy of the service client whose requests are
+// bound to ctx. The copy shares the underlying ProviderClient, so the token and
+// reauthentication state remain common to both clients.
+//
+// Since resource packages only take a *ServiceClient, this is the way to give
+// a single call, or a single pager, its own deadline or cancellation:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	server, err := servers.Get(client.WithContext(ctx), id).Extract()
+func (client *ServiceClient) WithContext(ctx context.Context) *ServiceClient {
+	c := *client
+	c.ctx = ctx
+	return &c
 }
 
 // ResourceBaseURL returns the base URL of any resources used by this service. It MUST end with a /.
@@ -140,15 +163,73 @@ func (client *ServiceClient) setMicroversionHeader(opts *RequestOpts) {
 	}
 }
 
+// SupportsMicroversion reports whether the Microversion of the client is greater than or equal to
+// version, i.e. whether the features introduced by version can be used. It returns false when the
+// Microversion is not set or when either microversion is malformed.
+func (client *ServiceClient) SupportsMicroversion(version string) bool {
+	major, minor, err := ParseMicroversion(version)
+	if err != nil {
+		return false
+	}
+	currentMajor, currentMinor, err := ParseMicroversion(client.Microversion)
+	if err != nil {
+		return false
+	}
+	if currentMajor != major {
+		return currentMajor > major
+	}
+	return currentMinor >= minor
+}
+
+// ParseMicroversion parses a microversion of the form "<major>.<minor>". The
+// "v" prefix of the versions of the version documents is accepted.
+func ParseMicroversion(version string) (major, minor int, err error) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid microversion format: %q", version)
+	}
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid microversion format: %q", version)
+	}
+	if minor, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, fmt.Errorf("invalid microversion format: %q", version)
+	}
+	return major, minor, nil
+}
+
 // Request carries out the HTTP operation for the service client
 func (client *ServiceClient) Request(method, url string, options *RequestOpts) (*http.Response, error) {
+	if options != nil {
+		// the caller may reuse its options with another client
+		opts := *options
+		options = &opts
+	}
+	if client.ctx != nil {
+		if options == nil {
+			options = new(RequestOpts)
+		}
+		if options.Context == nil {
+			options.Context = client.ctx
+		}
+	}
 	if len(client.MoreHeaders) > 0 {
 		if options == nil {
 			options = new(RequestOpts)
 		}
+		// merge into a new map, so that the caller's one is left untouched
+		moreHeaders := make(map[string]string, len(options.MoreHeaders)+len(client.MoreHeaders))
+		for k, v := range options.MoreHeaders {
+			moreHeaders[k] = v
+		}
 		for k, v := range client.MoreHeaders {
-			options.MoreHeaders[k] = v
+			moreHeaders[k] = v
 		}
+		options.MoreHeaders = moreHeaders
+	}
+	if options != nil {
+		options.serviceType = client.Type
+		options.microversion = client.Microversion
+		options.resourceBase = client.ResourceBaseURL()
 	}
 	return client.ProviderClient.Request(method, url, options)
 }
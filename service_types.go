@@ -0,0 +1,30 @@
+package gophercloud
+
+// serviceTypeAliasGroups lists service types which name the same API, as
+// published by the OpenStack service-types-authority, with the official type
+// first. The versioned aliases of older API versions, like volumev2, are left
+// out, because the clients asking for the newer versions can't use them.
+var serviceTypeAliasGroups = [][]string{
+	{"block-storage", "volumev3", "block-store"},
+	{"shared-file-system", "sharev2", "share"},
+	{"workflow", "workflowv2"},
+	{"container-infrastructure-management", "container-infra", "container-infrastructure"},
+	{"message", "messaging"},
+	{"application-container", "container"},
+}
+
+// ServiceTypeAliases returns the other service types under which a service of
+// type serviceType may appear in a service catalog, in order of preference.
+// For example, a "volumev3" service may be registered as "block-storage".
+func ServiceTypeAliases(serviceType string) []string {
+	for _, group := range serviceTypeAliasGroups {
+		for i, t := range group {
+			if t == serviceType {
+				aliases := make([]string, 0, len(group)-1)
+				aliases = append(aliases, group[:i]...)
+				return append(aliases, group[i+1:]...)
+			}
+		}
+	}
+	return nil
+}
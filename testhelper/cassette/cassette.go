@@ -0,0 +1,305 @@
+/*
+Package cassette provides an http.RoundTripper which records the HTTP
+interactions of a ProviderClient to a file, and replays them later without
+network access.
+
+Tokens, passwords, signatures and other secrets are scrubbed from the recorded
+URLs, headers, JSON bodies and form bodies with gophercloud.RedactURL,
+gophercloud.RedactHeaders, gophercloud.RedactJSON and gophercloud.RedactQuery,
+so that cassettes can be committed along with the tests.
+
+Example to record, then replay the requests of an acceptance test
+
+	mode := cassette.ModeReplay
+	if os.Getenv("GOPHERCLOUD_RECORD") != "" {
+		mode = cassette.ModeRecord
+	}
+
+	rec, err := cassette.New("testdata/servers.json", mode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Stop()
+
+	provider, err := openstack.NewClient(authURL)
+	provider.HTTPClient = http.Client{Transport: rec}
+	err = openstack.Authenticate(provider, authOpts)
+*/
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// Mode specifies whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay replays the interactions of an existing cassette. Requests
+	// which don't match any recorded interaction fail.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends the requests to the real server, and saves the
+	// interactions to the cassette when the Recorder is stopped.
+	ModeRecord
+)
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// BodyEncoding is "base64" when the body is not valid UTF-8.
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// ErrInteractionNotFound is returned in ModeReplay when a request doesn't
+// match any of the unused recorded interactions.
+type ErrInteractionNotFound struct {
+	gophercloud.BaseError
+	Method string
+	URL    string
+}
+
+func (e ErrInteractionNotFound) Error() string {
+	return fmt.Sprintf("No recorded interaction matches %s %s", e.Method, e.URL)
+}
+
+// Recorder is an http.RoundTripper recording or replaying interactions.
+//
+// Requests are matched on their method, their normalized URL, in which the
+// query parameters are sorted, and their scrubbed body. Each recorded
+// interaction is replayed at most once, in the order of the recording, so that
+// polling the same URL replays the successive responses.
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a Recorder using the cassette file at path. In ModeReplay, the
+// file is loaded immediately. In ModeRecord, requests are sent with
+// transport, or http.DefaultTransport if nil.
+func New(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: transport,
+	}
+
+	if mode == ModeReplay {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &r.cassette); err != nil {
+			return nil, err
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// Stop saves the cassette file in ModeRecord. It does nothing in ModeReplay.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	recorded := Request{
+		Method: req.Method,
+		URL:    NormalizeURL(gophercloud.RedactURL(req.URL.String())),
+		Header: gophercloud.RedactHeaders(req.Header),
+		Body:   scrubBody(body, req.Header.Get("Content-Type")),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		r.used[i] = true
+
+		body := []byte(interaction.Response.Body)
+		if interaction.Response.BodyEncoding == "base64" {
+			var err error
+			body, err = base64.StdEncoding.DecodeString(interaction.Response.Body)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		header := http.Header{}
+		for k, v := range interaction.Response.Header {
+			header[k] = append([]string(nil), v...)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, ErrInteractionNotFound{Method: recorded.Method, URL: recorded.URL}
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	response := Response{
+		StatusCode: resp.StatusCode,
+		Header:     gophercloud.RedactHeaders(resp.Header),
+	}
+	if utf8.Valid(body) {
+		response.Body = scrubBody(body, resp.Header.Get("Content-Type"))
+	} else {
+		response.Body = base64.StdEncoding.EncodeToString(body)
+		response.BodyEncoding = "base64"
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: response,
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// NormalizeURL returns u with a lower case scheme and host, and sorted query
+// parameters.
+func NormalizeURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Fragment = ""
+	if parsed.RawQuery != "" {
+		// Encode sorts the parameters by key
+		parsed.RawQuery = parsed.Query().Encode()
+	}
+	return parsed.String()
+}
+
+// scrubBody redacts the secrets of JSON bodies, which are also compacted so
+// that they can be compared, and of form bodies, like the requests of the
+// OpenID Connect grants. Binary bodies are replaced by their SHA-256 sum, and
+// other bodies are returned as is.
+func scrubBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !utf8.Valid(body) {
+		return fmt.Sprintf("sha256:%x", sha256.Sum256(body))
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/x-www-form-urlencoded" {
+		return gophercloud.RedactQuery(string(body))
+	}
+	if !json.Valid(body) {
+		return string(body)
+	}
+	return string(gophercloud.RedactJSON(body))
+}
+
+func matches(recorded, req Request) bool {
+	return recorded.Method == req.Method &&
+		recorded.URL == req.URL &&
+		recorded.Body == req.Body
+}
@@ -0,0 +1,145 @@
+package testing
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/cassette"
+)
+
+func TestRecordReplay(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	count := 0
+	th.Mux.HandleFunc("/servers", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Subject-Token", "secret-token")
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, `{"server":{"id":"server-%d","adminPass":"s3cr3t"}}`, count)
+	})
+
+	dir, err := ioutil.TempDir("", "cassette")
+	th.AssertNoErr(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	send := func(rec *cassette.Recorder, name string) (map[string]interface{}, error) {
+		p := &gophercloud.ProviderClient{
+			HTTPClient: http.Client{Transport: rec},
+		}
+		p.SetToken("secret-token")
+		var body map[string]interface{}
+		_, err := p.Request("POST", th.Endpoint()+"servers?b=2&a=1", &gophercloud.RequestOpts{
+			JSONBody:     map[string]interface{}{"server": map[string]interface{}{"name": name, "adminPass": "s3cr3t"}},
+			JSONResponse: &body,
+		})
+		return body, err
+	}
+
+	// record
+	rec, err := cassette.New(path, cassette.ModeRecord, nil)
+	th.AssertNoErr(t, err)
+	_, err = send(rec, "first")
+	th.AssertNoErr(t, err)
+	_, err = send(rec, "second")
+	th.AssertNoErr(t, err)
+	th.AssertNoErr(t, rec.Stop())
+	th.AssertEquals(t, 2, count)
+
+	b, err := ioutil.ReadFile(path)
+	th.AssertNoErr(t, err)
+	if strings.Contains(string(b), "secret-token") || strings.Contains(string(b), "s3cr3t") {
+		t.Fatalf("secrets were not scrubbed from the cassette: %s", b)
+	}
+
+	// replay, in a different order
+	rec, err = cassette.New(path, cassette.ModeReplay, nil)
+	th.AssertNoErr(t, err)
+
+	body, err := send(rec, "second")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "server-2", body["server"].(map[string]interface{})["id"])
+
+	body, err = send(rec, "first")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "server-1", body["server"].(map[string]interface{})["id"])
+	th.AssertEquals(t, 2, count)
+
+	// each interaction is only replayed once
+	_, err = send(rec, "first")
+	var notFound cassette.ErrInteractionNotFound
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected an ErrInteractionNotFound, got %T: %v", err, err)
+	}
+}
+
+func TestRecordPasswordGrant(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestFormValues(t, r, map[string]string{
+			"grant_type":    "password",
+			"username":      "alice",
+			"password":      "wonderland",
+			"client_secret": "s3cr3t",
+		})
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access-s3cr3t","token_type":"Bearer"}`)
+	})
+	th.Mux.HandleFunc("/v1/AUTH_test/c/o", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		fmt.Fprint(w, "content")
+	})
+
+	dir, err := ioutil.TempDir("", "cassette")
+	th.AssertNoErr(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	send := func(rec *cassette.Recorder) {
+		c := http.Client{Transport: rec}
+		form := "grant_type=password&username=alice&password=wonderland&client_secret=s3cr3t"
+		resp, err := c.Post(th.Endpoint()+"token", "application/x-www-form-urlencoded", strings.NewReader(form))
+		th.AssertNoErr(t, err)
+		resp.Body.Close()
+		th.AssertEquals(t, http.StatusOK, resp.StatusCode)
+
+		resp, err = c.Get(th.Endpoint() + "v1/AUTH_test/c/o?temp_url_sig=s3cr3t&temp_url_expires=1593565980")
+		th.AssertNoErr(t, err)
+		resp.Body.Close()
+		th.AssertEquals(t, http.StatusOK, resp.StatusCode)
+	}
+
+	rec, err := cassette.New(path, cassette.ModeRecord, nil)
+	th.AssertNoErr(t, err)
+	send(rec)
+	th.AssertNoErr(t, rec.Stop())
+
+	b, err := ioutil.ReadFile(path)
+	th.AssertNoErr(t, err)
+	if strings.Contains(string(b), "wonderland") || strings.Contains(string(b), "s3cr3t") {
+		t.Fatalf("secrets were not scrubbed from the cassette: %s", b)
+	}
+
+	// the scrubbed requests still match
+	rec, err = cassette.New(path, cassette.ModeReplay, nil)
+	th.AssertNoErr(t, err)
+	send(rec)
+}
+
+func TestNormalizeURL(t *testing.T) {
+	th.AssertEquals(t, "http://example.com/v2.1/servers?a=1&b=2",
+		cassette.NormalizeURL("HTTP://Example.COM/v2.1/servers?b=2&a=1#fragment"))
+}
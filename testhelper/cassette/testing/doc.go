@@ -0,0 +1,2 @@
+// cassette unit tests
+package testing
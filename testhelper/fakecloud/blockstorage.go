@@ -0,0 +1,114 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// rfc3339Micro is the time format of the Cinder API.
+const rfc3339Micro = "2006-01-02T15:04:05.000000"
+
+// handleBlockStorage implements the volumes API of Cinder v3.
+func (c *Cloud) handleBlockStorage(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	parts := splitPath(r.URL.Path, "/volume/v3/"+c.ProjectID+"/")
+	if len(parts) == 0 || len(parts) > 2 || parts[0] != "volumes" {
+		fault(w, http.StatusNotFound, "itemNotFound", "The resource could not be found.")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		var volumes []interface{}
+		for _, v := range c.volumes.list(r.URL.Query()) {
+			volume := v.(map[string]interface{})
+			volumes = append(volumes, map[string]interface{}{
+				"id":    volume["id"],
+				"name":  volume["name"],
+				"links": volume["links"],
+			})
+		}
+		if volumes == nil {
+			volumes = []interface{}{}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"volumes": volumes})
+	case len(parts) == 1 && r.Method == "POST":
+		c.createVolume(w, r)
+	case len(parts) == 2 && parts[1] == "detail" && r.Method == "GET":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"volumes": c.volumes.list(r.URL.Query())})
+	case len(parts) == 2:
+		volume, ok := c.volumes.get(parts[1])
+		if !ok {
+			fault(w, http.StatusNotFound, "itemNotFound", fmt.Sprintf("Volume %s could not be found.", parts[1]))
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			if volume["status"] == "creating" {
+				volume["status"] = "available"
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"volume": volume})
+		case "PUT":
+			opts, err := readBody(r, "volume")
+			if err != nil {
+				fault(w, http.StatusBadRequest, "badRequest", err.Error())
+				return
+			}
+			merge(volume, opts, "name", "description", "metadata")
+			volume["updated_at"] = now().Format(rfc3339Micro)
+			writeJSON(w, http.StatusOK, map[string]interface{}{"volume": volume})
+		case "DELETE":
+			c.volumes.remove(parts[1])
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			fault(w, http.StatusMethodNotAllowed, "badMethod", "The method is not allowed for this resource.")
+		}
+	default:
+		fault(w, http.StatusNotFound, "itemNotFound", "The resource could not be found.")
+	}
+}
+
+func (c *Cloud) createVolume(w http.ResponseWriter, r *http.Request) {
+	opts, err := readBody(r, "volume")
+	if err != nil {
+		fault(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+
+	size, _ := opts["size"].(float64)
+	if size < 1 || size != float64(int(size)) {
+		fault(w, http.StatusBadRequest, "badRequest", fmt.Sprintf("Invalid input received: Volume size '%v' must be an integer and greater than 0.", opts["size"]))
+		return
+	}
+
+	id := newID()
+	created := now().Format(rfc3339Micro)
+	volume := map[string]interface{}{
+		"id":                           id,
+		"name":                         "",
+		"description":                  "",
+		"size":                         int(size),
+		"status":                       "creating",
+		"availability_zone":            "nova",
+		"bootable":                     "false",
+		"encrypted":                    false,
+		"multiattach":                  false,
+		"volume_type":                  "__DEFAULT__",
+		"attachments":                  []interface{}{},
+		"metadata":                     map[string]interface{}{},
+		"user_id":                      c.UserID,
+		"os-vol-tenant-attr:tenant_id": c.ProjectID,
+		"created_at":                   created,
+		"updated_at":                   created,
+		"links": []interface{}{
+			map[string]interface{}{"rel": "self", "href": c.Server.URL + "/volume/v3/" + c.ProjectID + "/volumes/" + id},
+		},
+	}
+	merge(volume, opts, "name", "description", "metadata", "availability_zone", "volume_type")
+	c.volumes.add(volume)
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{"volume": volume})
+}
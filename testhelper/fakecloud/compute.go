@@ -0,0 +1,241 @@
+package fakecloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// handleCompute implements the flavors and servers APIs of Nova.
+func (c *Cloud) handleCompute(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	parts := splitPath(r.URL.Path, "/compute/v2.1/")
+	if len(parts) == 0 {
+		fault(w, http.StatusNotFound, "itemNotFound", "The resource could not be found.")
+		return
+	}
+
+	switch parts[0] {
+	case "flavors":
+		c.handleFlavors(w, r, parts[1:])
+	case "servers":
+		c.handleServers(w, r, parts[1:])
+	default:
+		fault(w, http.StatusNotFound, "itemNotFound", "The resource could not be found.")
+	}
+}
+
+func (c *Cloud) handleFlavors(w http.ResponseWriter, r *http.Request, parts []string) {
+	if r.Method != "GET" || len(parts) > 1 {
+		fault(w, http.StatusMethodNotAllowed, "badMethod", "The method is not allowed for this resource.")
+		return
+	}
+
+	switch {
+	case len(parts) == 0 || parts[0] == "detail":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"flavors": c.flavors.list(nil)})
+	default:
+		flavor, ok := c.flavors.get(parts[0])
+		if !ok {
+			fault(w, http.StatusNotFound, "itemNotFound", fmt.Sprintf("Flavor %s could not be found.", parts[0]))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"flavor": flavor})
+	}
+}
+
+func (c *Cloud) handleServers(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == "GET":
+		var servers []interface{}
+		for _, s := range c.servers.list(r.URL.Query()) {
+			server := s.(map[string]interface{})
+			servers = append(servers, map[string]interface{}{
+				"id":    server["id"],
+				"name":  server["name"],
+				"links": server["links"],
+			})
+		}
+		if servers == nil {
+			servers = []interface{}{}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"servers": servers})
+	case len(parts) == 0 && r.Method == "POST":
+		c.createServer(w, r)
+	case len(parts) == 1 && parts[0] == "detail" && r.Method == "GET":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"servers": c.servers.list(r.URL.Query())})
+	case len(parts) == 1 || len(parts) == 2 && parts[1] == "action":
+		server, ok := c.servers.get(parts[0])
+		if !ok {
+			fault(w, http.StatusNotFound, "itemNotFound", fmt.Sprintf("Instance %s could not be found.", parts[0]))
+			return
+		}
+		if len(parts) == 2 {
+			c.serverAction(w, r, server)
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			if server["status"] == "BUILD" {
+				server["status"] = "ACTIVE"
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"server": server})
+		case "PUT":
+			opts, err := readBody(r, "server")
+			if err != nil {
+				fault(w, http.StatusBadRequest, "badRequest", err.Error())
+				return
+			}
+			merge(server, opts, "name", "accessIPv4", "accessIPv6")
+			server["updated"] = now().Format(time.RFC3339)
+			writeJSON(w, http.StatusOK, map[string]interface{}{"server": server})
+		case "DELETE":
+			c.servers.remove(parts[0])
+			for _, p := range c.ports.list(map[string][]string{"device_id": {parts[0]}}) {
+				c.ports.remove(p.(map[string]interface{})["id"].(string))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fault(w, http.StatusMethodNotAllowed, "badMethod", "The method is not allowed for this resource.")
+		}
+	default:
+		fault(w, http.StatusNotFound, "itemNotFound", "The resource could not be found.")
+	}
+}
+
+func (c *Cloud) createServer(w http.ResponseWriter, r *http.Request) {
+	opts, err := readBody(r, "server")
+	if err != nil {
+		fault(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+
+	name, _ := opts["name"].(string)
+	if name == "" {
+		fault(w, http.StatusBadRequest, "badRequest", "Invalid input for field/attribute name.")
+		return
+	}
+	flavorRef, _ := opts["flavorRef"].(string)
+	if _, ok := c.flavors.get(flavorRef); !ok {
+		fault(w, http.StatusBadRequest, "badRequest", fmt.Sprintf("Flavor %s could not be found.", flavorRef))
+		return
+	}
+
+	var networks []map[string]interface{}
+	if nets, ok := opts["networks"].([]interface{}); ok {
+		for _, n := range nets {
+			networkID, _ := n.(map[string]interface{})["uuid"].(string)
+			network, ok := c.networks.get(networkID)
+			if !ok {
+				fault(w, http.StatusBadRequest, "badRequest", fmt.Sprintf("Network %s could not be found.", networkID))
+				return
+			}
+			networks = append(networks, network)
+		}
+	}
+
+	id := newID()
+	addresses := map[string]interface{}{}
+	for _, network := range networks {
+		port := c.newPort(network, map[string]interface{}{
+			"device_id":    id,
+			"device_owner": "compute:nova",
+		})
+		c.ports.add(port)
+
+		var addrs []interface{}
+		for _, ip := range port["fixed_ips"].([]interface{}) {
+			addrs = append(addrs, map[string]interface{}{
+				"addr":                    ip.(map[string]interface{})["ip_address"],
+				"version":                 4,
+				"OS-EXT-IPS:type":         "fixed",
+				"OS-EXT-IPS-MAC:mac_addr": port["mac_address"],
+			})
+		}
+		addresses[network["name"].(string)] = addrs
+	}
+
+	var image interface{} = ""
+	if imageRef, ok := opts["imageRef"].(string); ok && imageRef != "" {
+		image = map[string]interface{}{"id": imageRef}
+	}
+	metadata, ok := opts["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+	}
+	links := []interface{}{
+		map[string]interface{}{"rel": "self", "href": c.Server.URL + "/compute/v2.1/servers/" + id},
+	}
+
+	created := now().Format(time.RFC3339)
+	c.servers.add(map[string]interface{}{
+		"id":         id,
+		"name":       name,
+		"status":     "BUILD",
+		"tenant_id":  c.ProjectID,
+		"user_id":    c.UserID,
+		"hostId":     newHexID(),
+		"created":    created,
+		"updated":    created,
+		"flavor":     map[string]interface{}{"id": flavorRef},
+		"image":      image,
+		"metadata":   metadata,
+		"addresses":  addresses,
+		"links":      links,
+		"accessIPv4": "",
+		"accessIPv6": "",
+	})
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"server": map[string]interface{}{
+			"id":        id,
+			"links":     links,
+			"adminPass": newHexID()[:12],
+		},
+	})
+}
+
+// serverAction implements the start, stop and reboot actions.
+func (c *Cloud) serverAction(w http.ResponseWriter, r *http.Request, server map[string]interface{}) {
+	if r.Method != "POST" {
+		fault(w, http.StatusMethodNotAllowed, "badMethod", "The method is not allowed for this resource.")
+		return
+	}
+
+	var action map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
+		fault(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+
+	switch {
+	case hasKey(action, "os-stop"):
+		server["status"] = "SHUTOFF"
+	case hasKey(action, "os-start") || hasKey(action, "reboot"):
+		server["status"] = "ACTIVE"
+	default:
+		fault(w, http.StatusBadRequest, "badRequest", "Unsupported server action.")
+		return
+	}
+	server["updated"] = now().Format(time.RFC3339)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// fault writes an error in the format of Nova and Cinder.
+func fault(w http.ResponseWriter, status int, kind, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		kind: map[string]interface{}{
+			"code":    status,
+			"message": message,
+		},
+	})
+}
+
+func hasKey(m map[string]interface{}, key string) bool {
+	_, ok := m[key]
+	return ok
+}
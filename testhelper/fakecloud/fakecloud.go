@@ -0,0 +1,325 @@
+/*
+Package fakecloud provides an in-memory OpenStack cloud for unit tests.
+
+It runs an httptest.Server implementing a stateful subset of the Keystone v3,
+Nova, Neutron and Cinder APIs: tokens and service catalog, servers and
+flavors, networks, subnets and ports, and volumes. Resources created through
+the API can be read, listed, updated and deleted, so that the code under test
+runs against the regular openstack package constructors without any HTTP
+fixture.
+
+Servers are created in the BUILD status, and volumes in the creating status.
+They respectively become ACTIVE and available the first time they are
+fetched, which exercises the WaitForStatus helpers.
+
+Example to authenticate against a fake cloud
+
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	provider, err := openstack.AuthenticatedClient(cloud.AuthOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	computeClient, err := openstack.NewComputeV2(provider, gophercloud.EndpointOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+*/
+package fakecloud
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+const (
+	// Username is the name of the only user of the cloud.
+	Username = "admin"
+	// Password is the password of Username.
+	Password = "secret"
+	// ProjectName is the name of the only project of the cloud.
+	ProjectName = "admin"
+	// DomainName is the name of the domain of the user and the project.
+	DomainName = "Default"
+	// Region is the region of all the endpoints of the catalog.
+	Region = "RegionOne"
+)
+
+// Cloud is a fake OpenStack cloud served by an httptest.Server.
+type Cloud struct {
+	// Server is the underlying HTTP server.
+	Server *httptest.Server
+
+	// UserID and ProjectID are the IDs of the only user and project.
+	UserID    string
+	ProjectID string
+
+	mu     sync.Mutex
+	tokens map[string]bool
+	// allocated counts the IP addresses allocated in each subnet.
+	allocated map[string]int
+
+	flavors  *collection
+	servers  *collection
+	networks *collection
+	subnets  *collection
+	ports    *collection
+	volumes  *collection
+}
+
+// New starts a fake cloud. It must be closed with Close.
+func New() *Cloud {
+	c := &Cloud{
+		UserID:    newID(),
+		ProjectID: newHexID(),
+		tokens:    make(map[string]bool),
+		allocated: make(map[string]int),
+		flavors:   newCollection(),
+		servers:   newCollection(),
+		networks:  newCollection(),
+		subnets:   newCollection(),
+		ports:     newCollection(),
+		volumes:   newCollection(),
+	}
+
+	for _, f := range []struct {
+		id    string
+		name  string
+		vcpus int
+		ram   int
+		disk  int
+	}{
+		{"1", "m1.tiny", 1, 512, 1},
+		{"2", "m1.small", 1, 2048, 20},
+		{"3", "m1.medium", 2, 4096, 40},
+		{"4", "m1.large", 4, 8192, 80},
+	} {
+		c.flavors.add(map[string]interface{}{
+			"id":                         f.id,
+			"name":                       f.name,
+			"vcpus":                      f.vcpus,
+			"ram":                        f.ram,
+			"disk":                       f.disk,
+			"swap":                       "",
+			"rxtx_factor":                1.0,
+			"os-flavor-access:is_public": true,
+			"OS-FLV-EXT-DATA:ephemeral":  0,
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/identity/", c.handleIdentity)
+	mux.Handle("/compute/v2.1/", c.authenticated(c.handleCompute))
+	mux.Handle("/network/v2.0/", c.authenticated(c.handleNetwork))
+	mux.Handle("/volume/v3/", c.authenticated(c.handleBlockStorage))
+	c.Server = httptest.NewServer(mux)
+
+	return c
+}
+
+// Close shuts down the server.
+func (c *Cloud) Close() {
+	c.Server.Close()
+}
+
+// IdentityEndpoint returns the Keystone v3 endpoint of the cloud.
+func (c *Cloud) IdentityEndpoint() string {
+	return c.Server.URL + "/identity/v3/"
+}
+
+// AuthOptions returns the options to authenticate against the cloud with
+// openstack.AuthenticatedClient.
+func (c *Cloud) AuthOptions() gophercloud.AuthOptions {
+	return gophercloud.AuthOptions{
+		IdentityEndpoint: c.IdentityEndpoint(),
+		Username:         Username,
+		Password:         Password,
+		DomainName:       DomainName,
+		TenantName:       ProjectName,
+	}
+}
+
+// RevokeTokens invalidates all the tokens issued so far, e.g. to test
+// reauthentication.
+func (c *Cloud) RevokeTokens() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens = make(map[string]bool)
+}
+
+// authenticated rejects the requests without a valid token. The lock is only
+// held to check the token, the handlers lock the state they use.
+func (c *Cloud) authenticated(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		valid := c.tokens[r.Header.Get("X-Auth-Token")]
+		c.mu.Unlock()
+		if !valid {
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":    http.StatusUnauthorized,
+					"title":   "Unauthorized",
+					"message": "The request you have made requires authentication.",
+				},
+			})
+			return
+		}
+		h(w, r)
+	})
+}
+
+// catalog returns the Keystone v3 service catalog of the cloud.
+func (c *Cloud) catalog() []interface{} {
+	services := []struct {
+		serviceType string
+		name        string
+		url         string
+	}{
+		{"identity", "keystone", c.IdentityEndpoint()},
+		{"compute", "nova", c.Server.URL + "/compute/v2.1/"},
+		{"network", "neutron", c.Server.URL + "/network/"},
+		{"volumev3", "cinderv3", c.Server.URL + "/volume/v3/" + c.ProjectID + "/"},
+	}
+
+	var catalog []interface{}
+	for _, s := range services {
+		catalog = append(catalog, map[string]interface{}{
+			"id":   newHexID(),
+			"type": s.serviceType,
+			"name": s.name,
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"id":        newHexID(),
+					"interface": "public",
+					"region":    Region,
+					"region_id": Region,
+					"url":       s.url,
+				},
+			},
+		})
+	}
+	return catalog
+}
+
+// collection stores resources as JSON objects, in the order of creation.
+type collection struct {
+	order []string
+	items map[string]map[string]interface{}
+}
+
+func newCollection() *collection {
+	return &collection{items: make(map[string]map[string]interface{})}
+}
+
+func (c *collection) add(item map[string]interface{}) {
+	id := item["id"].(string)
+	c.order = append(c.order, id)
+	c.items[id] = item
+}
+
+func (c *collection) get(id string) (map[string]interface{}, bool) {
+	item, ok := c.items[id]
+	return item, ok
+}
+
+func (c *collection) remove(id string) bool {
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+	delete(c.items, id)
+	for i, v := range c.order {
+		if v == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// list returns the items whose attributes match all the filters. Unknown
+// filters, such as pagination parameters, are ignored.
+func (c *collection) list(filters map[string][]string) []interface{} {
+	items := []interface{}{}
+	for _, id := range c.order {
+		item := c.items[id]
+		match := true
+		for k, v := range filters {
+			attr, ok := item[k]
+			if !ok || len(v) == 0 {
+				continue
+			}
+			if fmt.Sprint(attr) != v[0] {
+				match = false
+				break
+			}
+		}
+		if match {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// merge copies the allowed attributes of src into dst.
+func merge(dst, src map[string]interface{}, allowed ...string) {
+	for _, k := range allowed {
+		if v, ok := src[k]; ok {
+			dst[k] = v
+		}
+	}
+}
+
+// readBody decodes a request body of the form {"<key>": {...}}.
+func readBody(r *http.Request, key string) (map[string]interface{}, error) {
+	var body map[string]map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	v, ok := body[key]
+	if !ok {
+		return nil, fmt.Errorf("missing %q object in request body", key)
+	}
+	return v, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Openstack-Request-Id", "req-"+newID())
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// splitPath returns the segments of the path after prefix.
+func splitPath(path, prefix string) []string {
+	path = strings.Trim(strings.TrimPrefix(path, prefix), "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func newHexID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func newID() string {
+	id := newHexID()
+	return id[0:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:32]
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,149 @@
+package fakecloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// handleIdentity implements the version discovery and the tokens API of
+// Keystone v3. Users authenticate with the password or token methods.
+func (c *Cloud) handleIdentity(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/identity/", "/identity":
+		writeJSON(w, http.StatusMultipleChoices, map[string]interface{}{
+			"versions": map[string]interface{}{
+				"values": []interface{}{c.identityVersion()},
+			},
+		})
+	case "/identity/v3/", "/identity/v3":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"version": c.identityVersion(),
+		})
+	case "/identity/v3/auth/tokens":
+		switch r.Method {
+		case "POST":
+			c.createToken(w, r)
+		case "GET", "HEAD":
+			if !c.tokens[r.Header.Get("X-Auth-Token")] || !c.tokens[r.Header.Get("X-Subject-Token")] {
+				identityError(w, http.StatusNotFound, "Could not find token.")
+				return
+			}
+			w.Header().Set("X-Subject-Token", r.Header.Get("X-Subject-Token"))
+			if r.Method == "HEAD" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			writeJSON(w, http.StatusOK, c.token([]string{"token"}))
+		case "DELETE":
+			if !c.tokens[r.Header.Get("X-Auth-Token")] || !c.tokens[r.Header.Get("X-Subject-Token")] {
+				identityError(w, http.StatusNotFound, "Could not find token.")
+				return
+			}
+			delete(c.tokens, r.Header.Get("X-Subject-Token"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	default:
+		identityError(w, http.StatusNotFound, "The resource could not be found.")
+	}
+}
+
+func (c *Cloud) identityVersion() map[string]interface{} {
+	return map[string]interface{}{
+		"id":      "v3.14",
+		"status":  "stable",
+		"updated": "2020-04-07T00:00:00Z",
+		"links": []interface{}{
+			map[string]interface{}{"rel": "self", "href": c.IdentityEndpoint()},
+		},
+	}
+}
+
+func (c *Cloud) createToken(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Auth struct {
+			Identity struct {
+				Methods  []string `json:"methods"`
+				Password struct {
+					User struct {
+						ID       string `json:"id"`
+						Name     string `json:"name"`
+						Password string `json:"password"`
+					} `json:"user"`
+				} `json:"password"`
+				Token struct {
+					ID string `json:"id"`
+				} `json:"token"`
+			} `json:"identity"`
+		} `json:"auth"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		identityError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// like Keystone, every method of the request must succeed
+	identity := body.Auth.Identity
+	authenticated := len(identity.Methods) > 0
+	for _, method := range identity.Methods {
+		switch method {
+		case "password":
+			user := identity.Password.User
+			authenticated = authenticated && (user.Name == Username || user.ID == c.UserID) && user.Password == Password
+		case "token":
+			authenticated = authenticated && c.tokens[identity.Token.ID]
+		default:
+			authenticated = false
+		}
+	}
+	if !authenticated {
+		identityError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+		return
+	}
+
+	tokenID := newHexID()
+	c.tokens[tokenID] = true
+	w.Header().Set("X-Subject-Token", tokenID)
+	writeJSON(w, http.StatusCreated, c.token(identity.Methods))
+}
+
+// token returns the body of a token scoped to the only project.
+func (c *Cloud) token(methods []string) map[string]interface{} {
+	domain := map[string]interface{}{"id": "default", "name": DomainName}
+	return map[string]interface{}{
+		"token": map[string]interface{}{
+			"methods":    methods,
+			"issued_at":  now().Format(time.RFC3339),
+			"expires_at": now().Add(time.Hour).Format(time.RFC3339),
+			"user": map[string]interface{}{
+				"id":     c.UserID,
+				"name":   Username,
+				"domain": domain,
+			},
+			"project": map[string]interface{}{
+				"id":     c.ProjectID,
+				"name":   ProjectName,
+				"domain": domain,
+			},
+			"roles": []interface{}{
+				map[string]interface{}{"id": "admin", "name": "admin"},
+			},
+			"catalog": c.catalog(),
+		},
+	}
+}
+
+func identityError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    status,
+			"title":   http.StatusText(status),
+			"message": message,
+		},
+	})
+}
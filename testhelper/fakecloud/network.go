@@ -0,0 +1,267 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// handleNetwork implements the networks, subnets and ports APIs of Neutron.
+func (c *Cloud) handleNetwork(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	parts := splitPath(r.URL.Path, "/network/v2.0/")
+	if len(parts) == 0 || len(parts) > 2 {
+		networkError(w, http.StatusNotFound, "HTTPNotFound", "The resource could not be found.")
+		return
+	}
+
+	var (
+		coll     *collection
+		singular string
+		kind     string
+	)
+	switch parts[0] {
+	case "networks":
+		coll, singular, kind = c.networks, "network", "Network"
+	case "subnets":
+		coll, singular, kind = c.subnets, "subnet", "Subnet"
+	case "ports":
+		coll, singular, kind = c.ports, "port", "Port"
+	default:
+		networkError(w, http.StatusNotFound, "HTTPNotFound", "The resource could not be found.")
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, map[string]interface{}{parts[0]: coll.list(r.URL.Query())})
+		case "POST":
+			opts, err := readBody(r, singular)
+			if err != nil {
+				networkError(w, http.StatusBadRequest, "HTTPBadRequest", err.Error())
+				return
+			}
+			var item map[string]interface{}
+			switch singular {
+			case "network":
+				item = c.newNetwork(opts)
+			case "subnet":
+				item, err = c.newSubnet(opts)
+			case "port":
+				item, err = c.newPortFromOpts(opts)
+			}
+			if err != nil {
+				networkError(w, http.StatusBadRequest, "HTTPBadRequest", err.Error())
+				return
+			}
+			coll.add(item)
+			writeJSON(w, http.StatusCreated, map[string]interface{}{singular: item})
+		default:
+			networkError(w, http.StatusMethodNotAllowed, "HTTPMethodNotAllowed", "The method is not allowed for this resource.")
+		}
+		return
+	}
+
+	id := parts[1]
+	item, ok := coll.get(id)
+	if !ok {
+		networkError(w, http.StatusNotFound, kind+"NotFound", fmt.Sprintf("%s %s could not be found.", kind, id))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, map[string]interface{}{singular: item})
+	case "PUT":
+		opts, err := readBody(r, singular)
+		if err != nil {
+			networkError(w, http.StatusBadRequest, "HTTPBadRequest", err.Error())
+			return
+		}
+		switch singular {
+		case "network":
+			merge(item, opts, "name", "description", "admin_state_up", "shared")
+		case "subnet":
+			merge(item, opts, "name", "description", "enable_dhcp", "dns_nameservers", "gateway_ip")
+		case "port":
+			merge(item, opts, "name", "description", "admin_state_up", "device_id", "device_owner")
+		}
+		item["updated_at"] = now().Format(gophercloud.RFC3339NoZ)
+		writeJSON(w, http.StatusOK, map[string]interface{}{singular: item})
+	case "DELETE":
+		switch singular {
+		case "network":
+			if len(c.ports.list(map[string][]string{"network_id": {id}})) > 0 {
+				networkError(w, http.StatusConflict, "NetworkInUse", fmt.Sprintf("Unable to complete operation on network %s. There are one or more ports still in use on the network.", id))
+				return
+			}
+			for _, s := range c.subnets.list(map[string][]string{"network_id": {id}}) {
+				c.subnets.remove(s.(map[string]interface{})["id"].(string))
+			}
+		case "subnet":
+			for _, p := range c.ports.list(map[string][]string{"network_id": {item["network_id"].(string)}}) {
+				for _, ip := range p.(map[string]interface{})["fixed_ips"].([]interface{}) {
+					if ip.(map[string]interface{})["subnet_id"] == id {
+						networkError(w, http.StatusConflict, "SubnetInUse", fmt.Sprintf("Unable to complete operation on subnet %s: One or more ports have an IP allocation from this subnet.", id))
+						return
+					}
+				}
+			}
+			if network, ok := c.networks.get(item["network_id"].(string)); ok {
+				var subnets []interface{}
+				for _, s := range network["subnets"].([]interface{}) {
+					if s != id {
+						subnets = append(subnets, s)
+					}
+				}
+				if subnets == nil {
+					subnets = []interface{}{}
+				}
+				network["subnets"] = subnets
+			}
+		}
+		coll.remove(id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		networkError(w, http.StatusMethodNotAllowed, "HTTPMethodNotAllowed", "The method is not allowed for this resource.")
+	}
+}
+
+func (c *Cloud) newNetwork(opts map[string]interface{}) map[string]interface{} {
+	created := now().Format(gophercloud.RFC3339NoZ)
+	network := map[string]interface{}{
+		"id":              newID(),
+		"name":            "",
+		"description":     "",
+		"admin_state_up":  true,
+		"shared":          false,
+		"status":          "ACTIVE",
+		"subnets":         []interface{}{},
+		"tenant_id":       c.ProjectID,
+		"project_id":      c.ProjectID,
+		"created_at":      created,
+		"updated_at":      created,
+		"tags":            []interface{}{},
+		"revision_number": 1,
+	}
+	merge(network, opts, "name", "description", "admin_state_up", "shared")
+	return network
+}
+
+func (c *Cloud) newSubnet(opts map[string]interface{}) (map[string]interface{}, error) {
+	networkID, _ := opts["network_id"].(string)
+	network, ok := c.networks.get(networkID)
+	if !ok {
+		return nil, fmt.Errorf("Network %s could not be found.", networkID)
+	}
+
+	cidr, _ := opts["cidr"].(string)
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil || ip.To4() == nil {
+		return nil, fmt.Errorf("Invalid input for cidr. Reason: '%s' is not a valid IPv4 subnet.", cidr)
+	}
+
+	created := now().Format(gophercloud.RFC3339NoZ)
+	subnet := map[string]interface{}{
+		"id":                newID(),
+		"name":              "",
+		"description":       "",
+		"network_id":        networkID,
+		"cidr":              ipNet.String(),
+		"ip_version":        4,
+		"gateway_ip":        nthIP(ipNet, 1).String(),
+		"enable_dhcp":       true,
+		"dns_nameservers":   []interface{}{},
+		"host_routes":       []interface{}{},
+		"allocation_pools":  []interface{}{},
+		"tenant_id":         c.ProjectID,
+		"project_id":        c.ProjectID,
+		"created_at":        created,
+		"updated_at":        created,
+		"revision_number":   0,
+		"ipv6_address_mode": nil,
+		"ipv6_ra_mode":      nil,
+	}
+	merge(subnet, opts, "name", "description", "gateway_ip", "enable_dhcp", "dns_nameservers")
+	network["subnets"] = append(network["subnets"].([]interface{}), subnet["id"])
+	return subnet, nil
+}
+
+func (c *Cloud) newPortFromOpts(opts map[string]interface{}) (map[string]interface{}, error) {
+	networkID, _ := opts["network_id"].(string)
+	network, ok := c.networks.get(networkID)
+	if !ok {
+		return nil, fmt.Errorf("Network %s could not be found.", networkID)
+	}
+	return c.newPort(network, opts), nil
+}
+
+// newPort returns a port with an IP address allocated on each subnet of the
+// network.
+func (c *Cloud) newPort(network map[string]interface{}, opts map[string]interface{}) map[string]interface{} {
+	fixedIPs := []interface{}{}
+	for _, subnetID := range network["subnets"].([]interface{}) {
+		subnet, ok := c.subnets.get(subnetID.(string))
+		if !ok {
+			continue
+		}
+		_, ipNet, _ := net.ParseCIDR(subnet["cidr"].(string))
+		// .0 is the network address and .1 the gateway
+		c.allocated[subnetID.(string)]++
+		fixedIPs = append(fixedIPs, map[string]interface{}{
+			"subnet_id":  subnetID,
+			"ip_address": nthIP(ipNet, 1+c.allocated[subnetID.(string)]).String(),
+		})
+	}
+
+	id := newHexID()
+	created := now().Format(gophercloud.RFC3339NoZ)
+	port := map[string]interface{}{
+		"id":                    newID(),
+		"name":                  "",
+		"description":           "",
+		"network_id":            network["id"],
+		"admin_state_up":        true,
+		"status":                "ACTIVE",
+		"mac_address":           fmt.Sprintf("fa:16:3e:%s:%s:%s", id[0:2], id[2:4], id[4:6]),
+		"fixed_ips":             fixedIPs,
+		"device_id":             "",
+		"device_owner":          "",
+		"security_groups":       []interface{}{},
+		"allowed_address_pairs": []interface{}{},
+		"tenant_id":             c.ProjectID,
+		"project_id":            c.ProjectID,
+		"created_at":            created,
+		"updated_at":            created,
+		"revision_number":       1,
+	}
+	merge(port, opts, "name", "description", "admin_state_up", "device_id", "device_owner")
+	return port
+}
+
+// nthIP returns the n-th address of an IPv4 network.
+func nthIP(ipNet *net.IPNet, n int) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	copy(ip, ipNet.IP.To4())
+	for i := len(ip) - 1; i >= 0 && n > 0; i-- {
+		sum := int(ip[i]) + n
+		ip[i] = byte(sum % 256)
+		n = sum / 256
+	}
+	return ip
+}
+
+func networkError(w http.ResponseWriter, status int, kind, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"NeutronError": map[string]interface{}{
+			"type":    kind,
+			"message": message,
+			"detail":  "",
+		},
+	})
+}
@@ -0,0 +1,167 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/fakecloud"
+)
+
+func TestComputeAndNetwork(t *testing.T) {
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	provider, err := openstack.AuthenticatedClient(cloud.AuthOptions())
+	th.AssertNoErr(t, err)
+
+	networkClient, err := openstack.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	computeClient, err := openstack.NewComputeV2(provider, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+
+	network, err := networks.Create(networkClient, networks.CreateOpts{Name: "private"}).Extract()
+	th.AssertNoErr(t, err)
+	subnet, err := subnets.Create(networkClient, subnets.CreateOpts{
+		NetworkID: network.ID,
+		CIDR:      "192.168.1.0/24",
+		IPVersion: gophercloud.IPv4,
+	}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "192.168.1.1", subnet.GatewayIP)
+
+	allFlavors, err := flavors.ListDetail(computeClient, nil).AllPages()
+	th.AssertNoErr(t, err)
+	flavorList, err := flavors.ExtractFlavors(allFlavors)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 4, len(flavorList))
+
+	server, err := servers.Create(computeClient, servers.CreateOpts{
+		Name:      "test",
+		FlavorRef: flavorList[0].ID,
+		ImageRef:  "cirros",
+		Networks:  []servers.Network{{UUID: network.ID}},
+	}).Extract()
+	th.AssertNoErr(t, err)
+
+	th.AssertNoErr(t, servers.WaitForStatus(computeClient, server.ID, "ACTIVE", 10))
+
+	server, err = servers.Get(computeClient, server.ID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "test", server.Name)
+	th.AssertEquals(t, "ACTIVE", server.Status)
+
+	allPorts, err := ports.List(networkClient, ports.ListOpts{DeviceID: server.ID}).AllPages()
+	th.AssertNoErr(t, err)
+	portList, err := ports.ExtractPorts(allPorts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(portList))
+	th.AssertEquals(t, "192.168.1.2", portList[0].FixedIPs[0].IPAddress)
+
+	// the network can't be deleted while the server has a port on it
+	err = networks.Delete(networkClient, network.ID).ExtractErr()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusConflict))
+
+	th.AssertNoErr(t, servers.Delete(computeClient, server.ID).ExtractErr())
+	th.AssertNoErr(t, networks.Delete(networkClient, network.ID).ExtractErr())
+
+	_, err = servers.Get(computeClient, server.ID).Extract()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+	_, err = subnets.Get(networkClient, subnet.ID).Extract()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+}
+
+func TestBlockStorage(t *testing.T) {
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	provider, err := openstack.AuthenticatedClient(cloud.AuthOptions())
+	th.AssertNoErr(t, err)
+	client, err := openstack.NewBlockStorageV3(provider, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+
+	volume, err := volumes.Create(client, volumes.CreateOpts{Name: "data", Size: 10}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "creating", volume.Status)
+
+	th.AssertNoErr(t, volumes.WaitForStatus(client, volume.ID, "available", 10))
+
+	allVolumes, err := volumes.List(client, volumes.ListOpts{}).AllPages()
+	th.AssertNoErr(t, err)
+	volumeList, err := volumes.ExtractVolumes(allVolumes)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(volumeList))
+	th.AssertEquals(t, 10, volumeList[0].Size)
+
+	th.AssertNoErr(t, volumes.Delete(client, volume.ID, volumes.DeleteOpts{}).ExtractErr())
+	_, err = volumes.Get(client, volume.ID).Extract()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+}
+
+func TestReauthentication(t *testing.T) {
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	opts := cloud.AuthOptions()
+	opts.AllowReauth = true
+	provider, err := openstack.AuthenticatedClient(opts)
+	th.AssertNoErr(t, err)
+	client, err := openstack.NewComputeV2(provider, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+
+	token := provider.Token()
+	cloud.RevokeTokens()
+
+	_, err = flavors.Get(client, "1").Extract()
+	th.AssertNoErr(t, err)
+	if provider.Token() == token {
+		t.Fatal("expected the provider client to reauthenticate")
+	}
+}
+
+func TestInvalidCredentials(t *testing.T) {
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	opts := cloud.AuthOptions()
+	opts.Password = "wrong"
+	_, err := openstack.AuthenticatedClient(opts)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusUnauthorized))
+}
+
+func TestMultipleMethods(t *testing.T) {
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	provider, err := openstack.AuthenticatedClient(cloud.AuthOptions())
+	th.AssertNoErr(t, err)
+
+	// a valid token doesn't make up for a wrong password
+	body := fmt.Sprintf(`{"auth":{"identity":{"methods":["password","token"],"password":{"user":{"name":%q,"password":"wrong"}},"token":{"id":%q}}}}`,
+		fakecloud.Username, provider.Token())
+	resp, err := http.Post(cloud.IdentityEndpoint()+"auth/tokens", "application/json", strings.NewReader(body))
+	th.AssertNoErr(t, err)
+	resp.Body.Close()
+	th.AssertEquals(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestVersionDiscovery(t *testing.T) {
+	cloud := fakecloud.New()
+	defer cloud.Close()
+
+	opts := cloud.AuthOptions()
+	opts.IdentityEndpoint = cloud.Server.URL + "/identity/"
+	provider, err := openstack.AuthenticatedClient(opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, provider.Token() != "")
+}
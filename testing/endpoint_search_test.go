@@ -18,3 +18,21 @@ func TestApplyDefaultsToEndpointOpts(t *testing.T) {
 	expected = gophercloud.EndpointOpts{Availability: gophercloud.AvailabilityPublic, Type: "compute"}
 	th.CheckDeepEquals(t, expected, eo)
 }
+
+func TestEndpointOptsAvailabilities(t *testing.T) {
+	eo := gophercloud.EndpointOpts{
+		Availability: gophercloud.AvailabilityInternal,
+		AvailabilityFallback: []gophercloud.Availability{
+			gophercloud.AvailabilityInternal,
+			gophercloud.AvailabilityPublic,
+		},
+	}
+	expected := []gophercloud.Availability{gophercloud.AvailabilityInternal, gophercloud.AvailabilityPublic}
+	th.CheckDeepEquals(t, expected, eo.Availabilities())
+}
+
+func TestServiceTypeAliases(t *testing.T) {
+	th.CheckDeepEquals(t, []string{"block-storage", "block-store"}, gophercloud.ServiceTypeAliases("volumev3"))
+	th.CheckDeepEquals(t, []string{"sharev2", "share"}, gophercloud.ServiceTypeAliases("shared-file-system"))
+	th.CheckEquals(t, 0, len(gophercloud.ServiceTypeAliases("compute")))
+}
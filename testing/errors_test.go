@@ -1,6 +1,10 @@
 package testing
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/gophercloud/gophercloud"
@@ -23,3 +27,154 @@ func TestGetResponseCode(t *testing.T) {
 	th.AssertEquals(t, true, ok)
 	th.AssertEquals(t, err.GetStatusCode(), 404)
 }
+
+func TestErrUnexpectedResponseCodeFaults(t *testing.T) {
+	testCases := []struct {
+		body     string
+		header   string
+		fType    string
+		fMessage string
+		fDetail  string
+	}{
+		{
+			body:     `{"itemNotFound": {"message": "Instance foo could not be found.", "code": 404}}`,
+			header:   "X-Compute-Request-Id",
+			fType:    "itemNotFound",
+			fMessage: "Instance foo could not be found.",
+		},
+		{
+			body:     `{"NeutronError": {"type": "NetworkNotFound", "message": "Network foo could not be found.", "detail": ""}}`,
+			header:   "X-Openstack-Request-Id",
+			fType:    "NetworkNotFound",
+			fMessage: "Network foo could not be found.",
+		},
+		{
+			body:     `{"error": {"code": 404, "title": "Not Found", "message": "Could not find project: foo."}}`,
+			header:   "X-Openstack-Request-Id",
+			fType:    "Not Found",
+			fMessage: "Could not find project: foo.",
+		},
+		{
+			body:     `{"faultcode": "Client", "faultstring": "Load Balancer foo not found.", "debuginfo": null}`,
+			header:   "X-Openstack-Request-Id",
+			fMessage: "Load Balancer foo not found.",
+		},
+		{
+			body:     `{"error_message": "{\"faultcode\": \"Client\", \"faultstring\": \"Node foo could not be found.\", \"debuginfo\": null}"}`,
+			header:   "X-Openstack-Request-Id",
+			fMessage: "Node foo could not be found.",
+		},
+		{
+			body:     `{"code": 404, "type": "zone_not_found", "message": "Could not find Zone", "request_id": "req-1234"}`,
+			header:   "X-Openstack-Request-Id",
+			fType:    "zone_not_found",
+			fMessage: "Could not find Zone",
+		},
+		{
+			body:     `{"code": 404, "title": "Not Found", "description": "Secret not found."}`,
+			header:   "X-Openstack-Request-Id",
+			fType:    "Not Found",
+			fMessage: "Secret not found.",
+		},
+		{
+			body:     `{"badRequest": {"message": "Invalid volume.", "code": 400, "details": "Volume is in use."}}`,
+			header:   "X-Openstack-Request-Id",
+			fType:    "badRequest",
+			fMessage: "Invalid volume.",
+			fDetail:  "Volume is in use.",
+		},
+	}
+
+	for _, tc := range testCases {
+		th.SetupHTTP()
+		th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(tc.header, "req-1234")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, tc.body)
+		})
+
+		p := &gophercloud.ProviderClient{}
+		_, err := p.Request("GET", th.Endpoint()+"route", &gophercloud.RequestOpts{})
+		th.TeardownHTTP()
+
+		e, ok := err.(gophercloud.ErrDefault404)
+		th.AssertEquals(t, true, ok)
+		th.AssertEquals(t, tc.fType, e.FaultType)
+		th.AssertEquals(t, tc.fMessage, e.FaultMessage)
+		th.AssertEquals(t, tc.fDetail, e.FaultDetail)
+		th.AssertEquals(t, "req-1234", e.RequestID)
+		th.AssertEquals(t, true, strings.Contains(e.Error(), tc.fMessage))
+		th.AssertEquals(t, true, strings.Contains(e.Error(), "req-1234"))
+	}
+}
+
+func TestErrUnexpectedResponseCodeError(t *testing.T) {
+	respErr := gophercloud.ErrUnexpectedResponseCode{
+		URL:          "http://example.com",
+		Method:       "GET",
+		Expected:     []int{200},
+		Actual:       500,
+		FaultType:    "computeFault",
+		FaultMessage: "Unexpected API Error.",
+		RequestID:    "req-1234",
+	}
+
+	expected := "Expected HTTP response code [200] when accessing [GET http://example.com], but got 500 instead\n" +
+		"computeFault: Unexpected API Error. (request ID: req-1234)"
+	th.AssertEquals(t, expected, respErr.Error())
+
+	err500 := gophercloud.ErrDefault500{ErrUnexpectedResponseCode: respErr}
+	expected = "Internal Server Error: computeFault: Unexpected API Error. (request ID: req-1234)"
+	th.AssertEquals(t, expected, err500.Error())
+}
+
+func TestErrorsIsAndAs(t *testing.T) {
+	respErr := gophercloud.ErrUnexpectedResponseCode{
+		URL:      "http://example.com",
+		Method:   "GET",
+		Expected: []int{200},
+		Actual:   404,
+	}
+
+	var err error = gophercloud.ErrDefault404{ErrUnexpectedResponseCode: respErr}
+	err = &gophercloud.ErrErrorAfterReauthentication{ErrOriginal: err}
+	err = fmt.Errorf("getting server: %w", err)
+
+	th.AssertEquals(t, true, errors.Is(err, gophercloud.ErrNotFound))
+	th.AssertEquals(t, false, errors.Is(err, gophercloud.ErrConflict))
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+	th.AssertEquals(t, false, gophercloud.ResponseCodeIs(err, http.StatusConflict))
+
+	var err404 gophercloud.ErrDefault404
+	th.AssertEquals(t, true, errors.As(err, &err404))
+	th.AssertEquals(t, "http://example.com", err404.URL)
+
+	var errCode gophercloud.ErrUnexpectedResponseCode
+	th.AssertEquals(t, true, errors.As(err, &errCode))
+	th.AssertEquals(t, 404, errCode.Actual)
+
+	th.AssertEquals(t, false, gophercloud.ResponseCodeIs(errors.New("not found"), http.StatusNotFound))
+	th.AssertEquals(t, false, gophercloud.ResponseCodeIs(nil, http.StatusNotFound))
+}
+
+func TestErrUnableToReauthenticateUnwrap(t *testing.T) {
+	respErr := gophercloud.ErrUnexpectedResponseCode{
+		Actual: 401,
+	}
+	errReauth := errors.New("keystone is down")
+	err := &gophercloud.ErrUnableToReauthenticate{
+		ErrOriginal: respErr,
+		ErrReauth:   gophercloud.ErrDefault503{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 503}},
+	}
+
+	th.AssertEquals(t, true, errors.Is(err, gophercloud.ErrUnauthorized))
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusUnauthorized))
+
+	// the reauthentication error is matched too, after the original one
+	var err503 gophercloud.ErrDefault503
+	th.AssertEquals(t, true, errors.As(err, &err503))
+	th.AssertEquals(t, 503, err503.Actual)
+
+	err.ErrReauth = errReauth
+	th.AssertEquals(t, true, errors.Is(err, errReauth))
+}
@@ -0,0 +1,149 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+func TestURLTemplate(t *testing.T) {
+	nova := "http://compute.example.com/v2.1/"
+	swift := "http://swift.example.com/v1/AUTH_abc/"
+	testCases := []struct {
+		serviceType string
+		base        string
+		url         string
+		expected    string
+	}{
+		{"compute", nova, nova + "servers/detail?limit=10", "servers/detail"},
+		{"compute", nova, nova + "servers/f3f6b4b0-2a4b-4c3a-9d3e-0d1a5b0c7e21/action", "servers/{id}/action"},
+		{"compute", nova, nova + "flavors/42", "flavors/{id}"},
+		{"identity", "", "http://keystone.example.com/v3/users/5f1c3b4e0a8d4d0c9b1e2f3a4b5c6d7e/groups", "v3/users/{id}/groups"},
+		{"object-store", swift, swift, ""},
+		{"object-store", swift, swift + "container", "{container}"},
+		{"object-store", swift, swift + "container/path/to/object", "{container}/{object}"},
+	}
+
+	for _, tc := range testCases {
+		th.AssertEquals(t, tc.expected, gophercloud.URLTemplate(tc.serviceType, tc.base, tc.url))
+	}
+}
+
+func TestInstrumentationRetries(t *testing.T) {
+	type key struct{}
+
+	var (
+		info    gophercloud.RequestInfo
+		stats   gophercloud.RequestStats
+		retries []uint
+	)
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.RetryPolicy = &gophercloud.RetryPolicy{InitialBackoff: time.Millisecond}
+	p.Instrumentation = &gophercloud.Instrumentation{
+		BeforeRequest: func(ctx context.Context, i gophercloud.RequestInfo) context.Context {
+			info = i
+			return context.WithValue(ctx, key{}, "span")
+		},
+		AfterRequest: func(ctx context.Context, i gophercloud.RequestInfo, s gophercloud.RequestStats) {
+			th.AssertEquals(t, "span", ctx.Value(key{}))
+			stats = s
+		},
+		OnRetry: func(ctx context.Context, i gophercloud.RequestInfo, attempt uint, err error) {
+			th.AssertEquals(t, "span", ctx.Value(key{}))
+			th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusServiceUnavailable))
+			retries = append(retries, attempt)
+		},
+		OnReauth: func(ctx context.Context, i gophercloud.RequestInfo, d time.Duration, err error) {
+			t.Fatal("OnReauth should not be called")
+		},
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	count := 0
+	th.Mux.HandleFunc("/servers/42", func(w http.ResponseWriter, r *http.Request) {
+		count += 1
+		if count < 3 {
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "OK")
+	})
+
+	sc := &gophercloud.ServiceClient{
+		ProviderClient: p,
+		Endpoint:       th.Endpoint(),
+		Type:           "compute",
+		Microversion:   "2.79",
+	}
+	_, err := sc.Get(sc.ServiceURL("servers", "42"), nil, &gophercloud.RequestOpts{KeepResponseBody: true})
+	th.AssertNoErr(t, err)
+
+	th.AssertEquals(t, "compute", info.ServiceType)
+	th.AssertEquals(t, "2.79", info.Microversion)
+	th.AssertEquals(t, "GET", info.Method)
+	th.AssertEquals(t, "servers/{id}", info.URLTemplate)
+	th.AssertDeepEquals(t, []uint{1, 2}, retries)
+
+	th.AssertEquals(t, http.StatusOK, stats.StatusCode)
+	th.AssertEquals(t, uint(3), stats.Attempts)
+	th.AssertEquals(t, uint(2), stats.Retries)
+	th.AssertEquals(t, uint(0), stats.Reauths)
+	th.AssertNoErr(t, stats.Err)
+}
+
+func TestInstrumentationReauth(t *testing.T) {
+	var (
+		stats   gophercloud.RequestStats
+		reauths int
+	)
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.ReauthFunc = func() error {
+		p.SetToken("new-token")
+		return nil
+	}
+	p.Instrumentation = &gophercloud.Instrumentation{
+		AfterRequest: func(ctx context.Context, i gophercloud.RequestInfo, s gophercloud.RequestStats) {
+			stats = s
+		},
+		OnRetry: func(ctx context.Context, i gophercloud.RequestInfo, attempt uint, err error) {
+			t.Fatal("OnRetry should not be called")
+		},
+		OnReauth: func(ctx context.Context, i gophercloud.RequestInfo, d time.Duration, err error) {
+			th.AssertNoErr(t, err)
+			reauths++
+		},
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := p.Request("GET", th.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+
+	th.AssertEquals(t, 1, reauths)
+	th.AssertEquals(t, http.StatusNotFound, stats.StatusCode)
+	th.AssertEquals(t, uint(2), stats.Attempts)
+	th.AssertEquals(t, uint(0), stats.Retries)
+	th.AssertEquals(t, uint(1), stats.Reauths)
+	th.AssertEquals(t, err, stats.Err)
+}
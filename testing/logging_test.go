@@ -0,0 +1,169 @@
+package testing
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	th "github.com/gophercloud/gophercloud/testhelper"
+	"github.com/gophercloud/gophercloud/testhelper/client"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Auth-Token", "secret-token")
+	h.Set("X-Subject-Token", "secret-token")
+	h.Set("X-Storage-Token", "secret-token")
+	h.Set("Content-Type", "application/json")
+
+	r := gophercloud.RedactHeaders(h)
+	th.AssertEquals(t, gophercloud.RedactedValue, r.Get("X-Auth-Token"))
+	th.AssertEquals(t, gophercloud.RedactedValue, r.Get("X-Subject-Token"))
+	th.AssertEquals(t, gophercloud.RedactedValue, r.Get("X-Storage-Token"))
+	th.AssertEquals(t, "application/json", r.Get("Content-Type"))
+
+	// the original headers are left untouched
+	th.AssertEquals(t, "secret-token", h.Get("X-Auth-Token"))
+}
+
+func TestRedactJSON(t *testing.T) {
+	testCases := []struct {
+		body     string
+		expected string
+	}{
+		{
+			body:     `{"auth":{"identity":{"methods":["password"],"password":{"user":{"name":"admin","password":"s3cr3t"}}}}}`,
+			expected: `{"auth":{"identity":{"methods":["password"],"password":"***"}}}`,
+		},
+		{
+			body:     `{"auth":{"identity":{"methods":["application_credential"],"application_credential":{"id":"abc","secret":"s3cr3t"}}}}`,
+			expected: `{"auth":{"identity":{"application_credential":{"id":"abc","secret":"***"},"methods":["application_credential"]}}}`,
+		},
+		{
+			body:     `{"auth":{"identity":{"methods":["token"],"token":{"id":"s3cr3t"}}}}`,
+			expected: `{"auth":{"identity":{"methods":["token"],"token":{"id":"***"}}}}`,
+		},
+		{
+			body:     `{"auth":{"identity":{"methods":["totp"],"totp":{"user":{"id":"abc","passcode":"123456"}}}}}`,
+			expected: `{"auth":{"identity":{"methods":["totp"],"totp":{"user":{"id":"abc","passcode":"***"}}}}}`,
+		},
+		{
+			body:     `{"name":"secret","payload":"s3cr3t","payload_content_type":"text/plain"}`,
+			expected: `{"name":"secret","payload":"***","payload_content_type":"text/plain"}`,
+		},
+		{
+			body:     `{"server":{"name":"derp","adminPass":"s3cr3t"}}`,
+			expected: `{"server":{"adminPass":"***","name":"derp"}}`,
+		},
+		{
+			body:     `not json`,
+			expected: `[8 bytes of non-JSON content omitted]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		th.AssertEquals(t, tc.expected, string(gophercloud.RedactJSON([]byte(tc.body))))
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{
+			url:      "https://swift.example.com/v1/AUTH_test/c/o?temp_url_sig=s3cr3t&temp_url_expires=1593565980",
+			expected: "https://swift.example.com/v1/AUTH_test/c/o?temp_url_sig=***&temp_url_expires=1593565980",
+		},
+		{
+			url:      "https://example.com/bucket/o?X-Amz-Credential=abc&X-Amz-Signature=s3cr3t",
+			expected: "https://example.com/bucket/o?X-Amz-Credential=abc&X-Amz-Signature=***",
+		},
+		{
+			url:      "https://example.com/v2.0/networks?name=password&limit=10",
+			expected: "https://example.com/v2.0/networks?name=password&limit=10",
+		},
+	}
+
+	for _, tc := range testCases {
+		th.AssertEquals(t, tc.expected, gophercloud.RedactURL(tc.url))
+	}
+
+	th.AssertEquals(t, "grant_type=password&username=admin&password=***&client_secret=***",
+		gophercloud.RedactQuery("grant_type=password&username=admin&password=s3cr3t&client_secret=s3cr3t"))
+}
+
+func TestRequestLogger(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Openstack-Request-Id", "req-1234")
+		w.Header().Set("X-Subject-Token", "new-secret-token")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"credential":{"user_id":"abc","secret":"s3cr3t"}}`)
+	})
+
+	var logs []gophercloud.RequestLog
+	p := &gophercloud.ProviderClient{
+		Logger: gophercloud.LoggerFunc(func(l gophercloud.RequestLog) {
+			logs = append(logs, l)
+		}),
+		LogBodies: true,
+	}
+	p.SetToken(client.TokenID)
+
+	var actual struct {
+		Credential struct {
+			Secret string `json:"secret"`
+		} `json:"credential"`
+	}
+	_, err := p.Request("POST", th.Endpoint()+"route?temp_url_sig=s3cr3t", &gophercloud.RequestOpts{
+		JSONBody:     map[string]string{"password": "s3cr3t"},
+		JSONResponse: &actual,
+	})
+	th.AssertNoErr(t, err)
+
+	// the response is still decoded
+	th.AssertEquals(t, "s3cr3t", actual.Credential.Secret)
+
+	th.AssertEquals(t, 1, len(logs))
+	l := logs[0]
+	th.AssertEquals(t, "POST", l.Method)
+	th.AssertEquals(t, th.Endpoint()+"route?temp_url_sig=***", l.URL)
+	th.AssertEquals(t, http.StatusCreated, l.StatusCode)
+	th.AssertEquals(t, "req-1234", l.RequestID)
+	th.AssertEquals(t, gophercloud.RedactedValue, l.RequestHeader.Get("X-Auth-Token"))
+	th.AssertEquals(t, gophercloud.RedactedValue, l.ResponseHeader.Get("X-Subject-Token"))
+	th.AssertEquals(t, `{"password":"***"}`, string(l.RequestBody))
+	th.AssertEquals(t, `{"credential":{"secret":"***","user_id":"abc"}}`, string(l.ResponseBody))
+	th.AssertEquals(t, false, strings.Contains(l.String(), "s3cr3t"))
+}
+
+func TestLogRequestRedactsError(t *testing.T) {
+	var logs []gophercloud.RequestLog
+	p := &gophercloud.ProviderClient{
+		Logger: gophercloud.LoggerFunc(func(l gophercloud.RequestLog) {
+			logs = append(logs, l)
+		}),
+	}
+
+	// nothing listens on port 1, so the HTTP client fails with an error
+	// embedding the URL
+	_, err := p.Request("GET", "http://127.0.0.1:1/route?temp_url_sig=s3cr3t&temp_url_expires=1593565980", &gophercloud.RequestOpts{})
+	th.AssertErr(t, err)
+
+	th.AssertEquals(t, 1, len(logs))
+	th.AssertErr(t, logs[0].Err)
+	msg := logs[0].Err.Error()
+	th.AssertEquals(t, false, strings.Contains(msg, "s3cr3t"))
+	th.AssertEquals(t, true, strings.Contains(msg, "temp_url_sig=***&temp_url_expires=1593565980"))
+
+	var urlErr *url.Error
+	th.AssertEquals(t, true, errors.As(logs[0].Err, &urlErr))
+}
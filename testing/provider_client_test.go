@@ -710,3 +710,280 @@ func TestRequestWrongOkCode(t *testing.T) {
 		t.Fatalf("expected error type gophercloud.ErrUnexpectedResponseCode but got %T", err)
 	}
 }
+
+func TestRequestWithOptsContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "OK")
+	}))
+	defer ts.Close()
+
+	p := &gophercloud.ProviderClient{Context: context.Background()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := p.Request("GET", ts.URL, &gophercloud.RequestOpts{Context: ctx})
+	if err == nil {
+		t.Fatal("expecting error, got nil")
+	}
+	if !strings.Contains(err.Error(), ctx.Err().Error()) {
+		t.Fatalf("expecting error to contain: %q, got %q", ctx.Err().Error(), err.Error())
+	}
+
+	// the provider's context is left untouched
+	_, err = p.Request("GET", ts.URL, &gophercloud.RequestOpts{})
+	th.AssertNoErr(t, err)
+}
+
+func TestRequestGeneralRetryReceivesOptsContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.RetryFunc = func(context context.Context, method, url string, options *gophercloud.RequestOpts, err error, failCount uint) error {
+		th.AssertEquals(t, "value", context.Value(key{}))
+		return err
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+
+	_, err := p.Request("GET", th.Endpoint()+"/route", &gophercloud.RequestOpts{Context: ctx})
+	th.AssertErr(t, err)
+}
+
+func TestReauthContextFunc(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	var reauths int
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.ReauthFunc = func() error {
+		t.Fatal("ReauthFunc should not be called when ReauthContextFunc is set")
+		return nil
+	}
+	p.ReauthContextFunc = func(c context.Context) error {
+		th.AssertEquals(t, "value", c.Value(key{}))
+		reauths++
+		p.SetToken("new-token")
+		return nil
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintln(w, "OK")
+	})
+
+	_, err := p.Request("GET", th.Endpoint()+"/route", &gophercloud.RequestOpts{Context: ctx})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, reauths)
+}
+
+func TestRequestRetryPolicy(t *testing.T) {
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.RetryPolicy = &gophercloud.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Millisecond,
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	count := 0
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		count += 1
+		if count < 3 {
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "OK")
+	})
+
+	_, err := p.Request("GET", th.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, count)
+}
+
+func TestRequestRetryPolicyMaxAttempts(t *testing.T) {
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.RetryPolicy = &gophercloud.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	count := 0
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		count += 1
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+
+	_, err := p.Request("GET", th.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertErr(t, err)
+	th.AssertEquals(t, 3, count)
+
+	// RequestOpts.RetryPolicy overrides the provider's one
+	count = 0
+	_, err = p.Request("GET", th.Endpoint()+"route", &gophercloud.RequestOpts{
+		RetryPolicy: &gophercloud.RetryPolicy{MaxAttempts: 1},
+	})
+	th.AssertErr(t, err)
+	th.AssertEquals(t, 1, count)
+}
+
+func TestRequestRetryPolicyNonIdempotent(t *testing.T) {
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.RetryPolicy = &gophercloud.RetryPolicy{
+		InitialBackoff: time.Millisecond,
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	count := 0
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		count += 1
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+	})
+
+	_, err := p.Request("POST", th.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertErr(t, err)
+	th.AssertEquals(t, 1, count)
+}
+
+func TestRequestRetryPolicyRetryAfter(t *testing.T) {
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.RetryPolicy = &gophercloud.RetryPolicy{
+		// the backoff would exceed the test timeout without Retry-After
+		InitialBackoff: time.Hour,
+		MaxBackoff:     time.Hour,
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	count := 0
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		count += 1
+		if count < 2 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "OK")
+	})
+
+	_, err := p.Request("GET", th.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, count)
+}
+
+func TestRequestRetryPolicyRetryAfterCapped(t *testing.T) {
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.RetryPolicy = &gophercloud.RetryPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	count := 0
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		count += 1
+		if count < 2 {
+			// the delay is capped to MaxBackoff
+			w.Header().Set("Retry-After", "3600")
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "OK")
+	})
+
+	start := time.Now()
+	_, err := p.Request("GET", th.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, count)
+	if d := time.Since(start); d > time.Minute {
+		t.Fatalf("expected the Retry-After delay to be capped, waited %s", d)
+	}
+}
+
+func TestRequestRetryPolicyRewindsRawBody(t *testing.T) {
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.SetToken(client.TokenID)
+	p.RetryPolicy = &gophercloud.RetryPolicy{
+		InitialBackoff: time.Millisecond,
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	count := 0
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		count += 1
+		b, err := ioutil.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, "payload", string(b))
+		if count < 2 {
+			http.Error(w, "gateway timeout", http.StatusGatewayTimeout)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	_, err := p.Request("PUT", th.Endpoint()+"route", &gophercloud.RequestOpts{
+		RawBody: strings.NewReader("payload"),
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, count)
+}
+
+func TestRequestRetryPolicyConnectionRefused(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	var attempts uint
+	p := &gophercloud.ProviderClient{}
+	p.RetryPolicy = &gophercloud.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	}
+	p.RetryFunc = func(context context.Context, method, url string, options *gophercloud.RequestOpts, err error, failCount uint) error {
+		// RetryFunc is only called once the RetryPolicy gave up
+		attempts = failCount
+		return err
+	}
+
+	_, err := p.Request("GET", url, &gophercloud.RequestOpts{})
+	th.AssertErr(t, err)
+	th.AssertEquals(t, uint(1), attempts)
+}
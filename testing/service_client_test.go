@@ -1,8 +1,10 @@
 package testing
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/gophercloud/gophercloud"
@@ -32,3 +34,94 @@ func TestMoreHeaders(t *testing.T) {
 	th.AssertNoErr(t, err)
 	th.AssertEquals(t, resp.Request.Header.Get("custom"), "header")
 }
+
+func TestServiceClientWithContext(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	c := new(gophercloud.ServiceClient)
+	c.ProviderClient = new(gophercloud.ProviderClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.WithContext(ctx).Get(fmt.Sprintf("%s/route", th.Endpoint()), nil, nil)
+	th.AssertErr(t, err)
+	if !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("expecting error to contain: %q, got %q", context.Canceled.Error(), err.Error())
+	}
+
+	// the original service client is not bound to the context
+	_, err = c.Get(fmt.Sprintf("%s/route", th.Endpoint()), nil, nil)
+	th.AssertNoErr(t, err)
+
+	// nor are the options of the request
+	opts := &gophercloud.RequestOpts{}
+	_, err = c.WithContext(ctx).Request("GET", fmt.Sprintf("%s/route", th.Endpoint()), opts)
+	th.AssertErr(t, err)
+	th.AssertEquals(t, nil, opts.Context)
+	_, err = c.Request("GET", fmt.Sprintf("%s/route", th.Endpoint()), opts)
+	th.AssertNoErr(t, err)
+}
+
+func TestServiceClientMoreHeaders(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Client", "client")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	c := new(gophercloud.ServiceClient)
+	c.ProviderClient = new(gophercloud.ProviderClient)
+	c.MoreHeaders = map[string]string{"X-Client": "client"}
+
+	_, err := c.Request("GET", fmt.Sprintf("%s/route", th.Endpoint()), nil)
+	th.AssertNoErr(t, err)
+
+	// the headers of the caller are sent, but not modified
+	opts := &gophercloud.RequestOpts{MoreHeaders: map[string]string{"X-Caller": "caller"}}
+	for i := 0; i < 2; i++ {
+		_, err = c.Request("GET", fmt.Sprintf("%s/route", th.Endpoint()), opts)
+		th.AssertNoErr(t, err)
+	}
+	th.CheckDeepEquals(t, map[string]string{"X-Caller": "caller"}, opts.MoreHeaders)
+
+	opts = &gophercloud.RequestOpts{}
+	_, err = c.Request("GET", fmt.Sprintf("%s/route", th.Endpoint()), opts)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, 0, len(opts.MoreHeaders))
+}
+
+func TestSupportsMicroversion(t *testing.T) {
+	c := &gophercloud.ServiceClient{}
+	th.AssertEquals(t, false, c.SupportsMicroversion("2.1"))
+
+	c.Microversion = "2.79"
+	th.AssertEquals(t, true, c.SupportsMicroversion("2.1"))
+	th.AssertEquals(t, true, c.SupportsMicroversion("2.79"))
+	th.AssertEquals(t, false, c.SupportsMicroversion("2.80"))
+	th.AssertEquals(t, false, c.SupportsMicroversion("3.0"))
+	th.AssertEquals(t, true, c.SupportsMicroversion("1.99"))
+	th.AssertEquals(t, false, c.SupportsMicroversion("latest"))
+}
+
+func TestParseMicroversion(t *testing.T) {
+	major, minor, err := gophercloud.ParseMicroversion("2.79")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, major)
+	th.AssertEquals(t, 79, minor)
+
+	major, minor, err = gophercloud.ParseMicroversion("v3.0")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, major)
+	th.AssertEquals(t, 0, minor)
+
+	for _, version := range []string{"", "latest", "2", "2.1.1", "2.x"} {
+		_, _, err = gophercloud.ParseMicroversion(version)
+		th.AssertErr(t, err)
+	}
+}
@@ -0,0 +1,141 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	th "github.com/gophercloud/gophercloud/testhelper"
+)
+
+// expiringToken is an AuthResult whose token expires at a known time.
+type expiringToken struct {
+	id        string
+	expiresAt time.Time
+}
+
+func (r expiringToken) ExtractTokenID() (string, error) {
+	return r.id, nil
+}
+
+func (r expiringToken) ExtractExpiresAt() (time.Time, error) {
+	return r.expiresAt, nil
+}
+
+func TestTokenExpiresAt(t *testing.T) {
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+
+	expiresAt := time.Now().Add(time.Hour)
+	th.AssertNoErr(t, p.SetTokenAndAuthResult(expiringToken{"token", expiresAt}))
+	th.AssertEquals(t, expiresAt, p.TokenExpiresAt())
+
+	var other gophercloud.ProviderClient
+	other.CopyTokenFrom(p)
+	th.AssertEquals(t, expiresAt, other.TokenExpiresAt())
+
+	p.SetToken("manual")
+	th.AssertEquals(t, true, p.TokenExpiresAt().IsZero())
+}
+
+func TestRequestRefreshesExpiringToken(t *testing.T) {
+	var reauths int32
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.TokenRefreshMargin = time.Minute
+	th.AssertNoErr(t, p.SetTokenAndAuthResult(expiringToken{"old-token", time.Now().Add(30 * time.Second)}))
+	p.ReauthFunc = func() error {
+		atomic.AddInt32(&reauths, 1)
+		time.Sleep(10 * time.Millisecond)
+		return p.SetTokenAndAuthResult(expiringToken{"new-token", time.Now().Add(time.Hour)})
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		// the body can't be sent again, so a 401 response would fail the request
+		if r.Header.Get("X-Auth-Token") != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, "payload", string(b))
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pr, pw := io.Pipe()
+			go func() {
+				fmt.Fprint(pw, "payload")
+				pw.Close()
+			}()
+			_, err := p.Request("PUT", th.Endpoint()+"route", &gophercloud.RequestOpts{
+				RawBody: pr,
+				OkCodes: []int{201},
+			})
+			th.AssertNoErr(t, err)
+		}()
+	}
+	wg.Wait()
+
+	th.AssertEquals(t, int32(1), atomic.LoadInt32(&reauths))
+}
+
+func TestRequestDoesNotRefreshValidToken(t *testing.T) {
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.TokenRefreshMargin = time.Minute
+	th.AssertNoErr(t, p.SetTokenAndAuthResult(expiringToken{"token", time.Now().Add(time.Hour)}))
+	p.ReauthFunc = func() error {
+		t.Fatal("the token should not be refreshed")
+		return nil
+	}
+
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		th.AssertEquals(t, "token", r.Header.Get("X-Auth-Token"))
+		fmt.Fprintln(w, "OK")
+	})
+
+	_, err := p.Request("GET", th.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertNoErr(t, err)
+}
+
+func TestRefreshTokenInBackground(t *testing.T) {
+	refreshed := make(chan struct{})
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+	p.TokenRefreshMargin = time.Minute
+	th.AssertNoErr(t, p.SetTokenAndAuthResult(expiringToken{"old-token", time.Now().Add(time.Minute + 50*time.Millisecond)}))
+	p.ReauthContextFunc = func(ctx context.Context) error {
+		err := p.SetTokenAndAuthResult(expiringToken{"new-token", time.Now().Add(time.Hour)})
+		close(refreshed)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.RefreshTokenInBackground(ctx)
+
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the token was not refreshed in the background")
+	}
+	th.AssertEquals(t, "new-token", p.Token())
+}
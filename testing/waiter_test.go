@@ -0,0 +1,151 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	th "github.com/gophercloud/gophercloud/testhelper"
+)
+
+func statesRefreshFunc(states ...string) gophercloud.StateRefreshFunc {
+	i := 0
+	return func(ctx context.Context) (string, error) {
+		state := states[i]
+		if i < len(states)-1 {
+			i++
+		}
+		return state, nil
+	}
+}
+
+func TestWaiter(t *testing.T) {
+	var progress []string
+	w := gophercloud.Waiter{
+		TargetStates:  []string{"ACTIVE"},
+		FailureStates: []string{"ERROR"},
+		Interval:      time.Millisecond,
+		Progress: func(state string) {
+			progress = append(progress, state)
+		},
+	}
+
+	err := w.Wait(context.Background(), statesRefreshFunc("BUILD", "BUILD", "ACTIVE"))
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, []string{"BUILD", "BUILD", "ACTIVE"}, progress)
+}
+
+func TestWaiterFailureState(t *testing.T) {
+	w := gophercloud.Waiter{
+		TargetStates:  []string{"ACTIVE"},
+		FailureStates: []string{"ERROR"},
+		Interval:      time.Millisecond,
+	}
+
+	err := w.Wait(context.Background(), statesRefreshFunc("BUILD", "ERROR", "ACTIVE"))
+	th.AssertErr(t, err)
+
+	var failureErr gophercloud.ErrFailureState
+	th.AssertEquals(t, true, errors.As(err, &failureErr))
+	th.AssertEquals(t, "ERROR", failureErr.State)
+	th.AssertEquals(t, "Resource reached state [ERROR] while waiting for one of [ACTIVE]", err.Error())
+}
+
+func TestWaiterTargetIsFailureState(t *testing.T) {
+	w := gophercloud.Waiter{
+		TargetStates:  []string{"ERROR"},
+		FailureStates: []string{"ERROR"},
+		Interval:      time.Millisecond,
+	}
+
+	err := w.Wait(context.Background(), statesRefreshFunc("BUILD", "ERROR"))
+	th.AssertNoErr(t, err)
+}
+
+func TestWaiterTimeout(t *testing.T) {
+	w := gophercloud.Waiter{
+		TargetStates: []string{"ACTIVE"},
+		Interval:     time.Millisecond,
+		Timeout:      20 * time.Millisecond,
+	}
+
+	err := w.Wait(context.Background(), statesRefreshFunc("BUILD"))
+	_, ok := err.(gophercloud.ErrTimeOut)
+	th.AssertEquals(t, true, ok)
+}
+
+func TestWaiterContextCancelled(t *testing.T) {
+	w := gophercloud.Waiter{
+		TargetStates: []string{"ACTIVE"},
+		Interval:     time.Millisecond,
+		Timeout:      time.Minute,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	polls := 0
+	err := w.Wait(ctx, func(ctx context.Context) (string, error) {
+		polls++
+		if polls == 3 {
+			cancel()
+		}
+		return "BUILD", nil
+	})
+	th.AssertEquals(t, context.Canceled, err)
+	th.AssertEquals(t, 3, polls)
+}
+
+func TestWaiterBackoff(t *testing.T) {
+	var times []time.Time
+	w := gophercloud.Waiter{
+		TargetStates: []string{"ACTIVE"},
+		Interval:     10 * time.Millisecond,
+		Backoff:      2,
+		MaxInterval:  20 * time.Millisecond,
+		Progress: func(state string) {
+			times = append(times, time.Now())
+		},
+	}
+
+	err := w.Wait(context.Background(), statesRefreshFunc("BUILD", "BUILD", "BUILD", "ACTIVE"))
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 4, len(times))
+	if d := times[3].Sub(times[2]); d < 20*time.Millisecond {
+		t.Fatalf("expected interval to be at least 20ms, got %s", d)
+	}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	ctx := context.Background()
+
+	err := gophercloud.WaitForStatus(ctx, "available", time.Minute, statesRefreshFunc("available"))
+	th.AssertNoErr(t, err)
+
+	// every error status fails, unless it is the requested one
+	err = gophercloud.WaitForStatus(ctx, "available", time.Minute, statesRefreshFunc("error_deleting"))
+	var failureErr gophercloud.ErrFailureState
+	th.AssertEquals(t, true, errors.As(err, &failureErr))
+	th.AssertEquals(t, "error_deleting", failureErr.State)
+
+	err = gophercloud.WaitForStatus(ctx, "ERROR", time.Minute, statesRefreshFunc("ERROR"))
+	th.AssertNoErr(t, err)
+
+	// a zero timeout times out without polling
+	polls := 0
+	err = gophercloud.WaitForStatus(ctx, "available", 0, func(ctx context.Context) (string, error) {
+		polls++
+		return "available", nil
+	})
+	_, ok := err.(gophercloud.ErrTimeOut)
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, 0, polls)
+
+	// a negative timeout waits until the context is done
+	ctx, cancel := context.WithCancel(ctx)
+	err = gophercloud.WaitForStatus(ctx, "available", -1, func(ctx context.Context) (string, error) {
+		cancel()
+		return "creating", nil
+	})
+	th.AssertEquals(t, context.Canceled, err)
+}
@@ -0,0 +1,175 @@
+package gophercloud
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultTokenCacheMargin is the minimum remaining lifetime of a cached token
+// for it to be reused.
+const DefaultTokenCacheMargin = 5 * time.Minute
+
+// CachedToken is a token stored in a TokenCache.
+type CachedToken struct {
+	// ID is the token.
+	ID string `json:"id"`
+
+	// ExpiresAt is the expiration time of the token.
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Body is the body of the response which created the token, holding the
+	// service catalog.
+	Body json.RawMessage `json:"body"`
+}
+
+// TokenCache stores tokens so that they can be reused by other
+// ProviderClients, possibly in other processes, instead of authenticating
+// again. The keys identify the auth URL, the user, the scope and the
+// credentials the token was created with.
+type TokenCache interface {
+	// Get returns the token stored for key, or nil if there is none.
+	Get(key string) (*CachedToken, error)
+
+	// Set stores the token for key.
+	Set(key string, token *CachedToken) error
+
+	// Delete removes the token stored for key, if any.
+	Delete(key string) error
+}
+
+// TokenCacheLocker is implemented by the TokenCaches which can serialize the
+// authentications of the clients sharing them, so that a single token is
+// created when several clients authenticate at the same time.
+type TokenCacheLocker interface {
+	// Lock locks key, and returns the function unlocking it.
+	Lock(key string) (unlock func(), err error)
+}
+
+// FileTokenCache is a TokenCache storing each token in a file of a directory,
+// readable by the current user only. It can be shared by several processes.
+type FileTokenCache struct {
+	// Dir is the directory of the token files.
+	Dir string
+
+	// LockTimeout is the maximum time Lock waits for a lock held by another
+	// client. When not set, defaults to 30 seconds.
+	LockTimeout time.Duration
+}
+
+// NewFileTokenCache returns a FileTokenCache storing the tokens in dir, which
+// is created if needed. When dir is empty, the gophercloud/tokens directory
+// of the user cache directory is used.
+func NewFileTokenCache(dir string) (*FileTokenCache, error) {
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(cacheDir, "gophercloud", "tokens")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileTokenCache{Dir: dir}, nil
+}
+
+// path returns the path of the file of key. Keys are hashed, since they
+// contain credentials.
+func (c *FileTokenCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements TokenCache.
+func (c *FileTokenCache) Get(key string) (*CachedToken, error) {
+	b, err := ioutil.ReadFile(c.path(key) + ".json")
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var token CachedToken
+	if err := json.Unmarshal(b, &token); err != nil {
+		// ignore a corrupted file, it will be overwritten
+		return nil, nil
+	}
+	return &token, nil
+}
+
+// Set implements TokenCache. The file is replaced atomically, so that
+// concurrent readers never see a partial token.
+func (c *FileTokenCache) Set(key string, token *CachedToken) error {
+	b, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(c.Dir, ".token-")
+	if err != nil {
+		return err
+	}
+	// TempFile creates the file with the 0600 permissions
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), c.path(key)+".json"); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// Delete implements TokenCache.
+func (c *FileTokenCache) Delete(key string) error {
+	err := os.Remove(c.path(key) + ".json")
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Lock implements TokenCacheLocker with a lock file, which works across
+// processes and platforms. A lock file older than twice the LockTimeout is
+// considered stale and removed, in case its owner died.
+func (c *FileTokenCache) Lock(key string) (func(), error) {
+	timeout := c.LockTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	lockPath := c.path(key) + ".lock"
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if fi, err := os.Stat(lockPath); err == nil && time.Since(fi.ModTime()) > 2*timeout {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the token cache lock %s", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
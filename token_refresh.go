@@ -0,0 +1,83 @@
+package gophercloud
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTokenRefreshMargin is the margin used by RefreshTokenInBackground
+// when ProviderClient.TokenRefreshMargin is not set.
+const DefaultTokenRefreshMargin = 5 * time.Minute
+
+// tokenRefreshRetryInterval is the minimum interval between two background
+// refreshes, so that a failing identity service isn't flooded.
+const tokenRefreshRetryInterval = 10 * time.Second
+
+// tokenRefreshPollInterval is the interval at which the background refresh
+// checks the token when its expiration time is unknown.
+const tokenRefreshPollInterval = time.Minute
+
+// tokenExpiresWithin reports whether the token is known to expire within the
+// margin.
+func (client *ProviderClient) tokenExpiresWithin(margin time.Duration) bool {
+	expiresAt := client.TokenExpiresAt()
+	return !expiresAt.IsZero() && time.Until(expiresAt) <= margin
+}
+
+// refreshExpiringToken reauthenticates before a request is sent when the token
+// expires within TokenRefreshMargin. Concurrent requests wait for the same
+// reauthentication. An error is ignored: the request is sent with the current
+// token, and a 401 response triggers a reauthentication as usual.
+func (client *ProviderClient) refreshExpiringToken(ctx context.Context) {
+	if client.TokenRefreshMargin <= 0 || client.IsThrowaway() || !client.canReauth() {
+		return
+	}
+	if client.tokenExpiresWithin(client.TokenRefreshMargin) {
+		_ = client.ReauthenticateWithContext(ctx, client.Token())
+	}
+}
+
+/*
+RefreshTokenInBackground starts a goroutine which reauthenticates the client
+when its token expires within TokenRefreshMargin, or DefaultTokenRefreshMargin
+if not set, so that requests never wait for a reauthentication. It stops when
+ctx is done.
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	provider.RefreshTokenInBackground(ctx)
+*/
+func (client *ProviderClient) RefreshTokenInBackground(ctx context.Context) {
+	margin := client.TokenRefreshMargin
+	if margin <= 0 {
+		margin = DefaultTokenRefreshMargin
+	}
+
+	go func() {
+		for {
+			wait := tokenRefreshPollInterval
+			if expiresAt := client.TokenExpiresAt(); !expiresAt.IsZero() {
+				wait = time.Until(expiresAt.Add(-margin))
+			}
+			if wait > 0 {
+				if err := sleepWithContext(ctx, wait); err != nil {
+					return
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			if client.tokenExpiresWithin(margin) {
+				_ = client.ReauthenticateWithContext(ctx, client.Token())
+				// whether the reauthentication failed or returned a token
+				// expiring within the margin, don't try again right away
+				if client.tokenExpiresWithin(margin) {
+					if err := sleepWithContext(ctx, tokenRefreshRetryInterval); err != nil {
+						return
+					}
+				}
+			}
+		}
+	}()
+}
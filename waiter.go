@@ -0,0 +1,160 @@
+package gophercloud
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// DefaultWaiterInterval is the default delay between two polls of a Waiter.
+const DefaultWaiterInterval = time.Second
+
+// StateRefreshFunc returns the current state of a resource, e.g. its status.
+// It is called by Waiter.Wait with the context passed to Wait, which should be
+// bound to the requests it sends, e.g. with ServiceClient.WithContext.
+type StateRefreshFunc func(ctx context.Context) (string, error)
+
+// Waiter polls a resource until it reaches one of TargetStates. It fails fast
+// when the resource reaches one of FailureStates instead of waiting until the
+// timeout.
+//
+// Resource packages wrap it in a more convenient function that's specific to
+// a certain resource, but it can also be useful on its own:
+//
+//	w := gophercloud.Waiter{
+//		TargetStates:  []string{"ACTIVE"},
+//		FailureStates: []string{"ERROR"},
+//		Backoff:       1.5,
+//		MaxInterval:   30 * time.Second,
+//	}
+//	err := w.Wait(ctx, func(ctx context.Context) (string, error) {
+//		s, err := servers.Get(client.WithContext(ctx), id).Extract()
+//		if err != nil {
+//			return "", err
+//		}
+//		return s.Status, nil
+//	})
+type Waiter struct {
+	// TargetStates are the states in which the wait succeeds.
+	TargetStates []string
+
+	// FailureStates are the states in which the wait fails with an
+	// ErrFailureState error.
+	FailureStates []string
+
+	// Interval is the delay between two polls. When not set, defaults to
+	// DefaultWaiterInterval.
+	Interval time.Duration
+
+	// Backoff, if greater than 1, multiplies the interval after each poll.
+	Backoff float64
+
+	// MaxInterval, if set, is the upper bound of the interval when Backoff is
+	// used.
+	MaxInterval time.Duration
+
+	// Timeout, if set, is the maximum duration of the wait. When it expires,
+	// Wait returns an ErrTimeOut error. The context passed to Wait can also
+	// carry a deadline, in which case its error is returned instead.
+	Timeout time.Duration
+
+	// Progress, if set, is called with the state returned by each poll.
+	Progress func(state string)
+}
+
+// Wait polls refresh, starting immediately, until it returns one of the
+// target states, one of the failure states, an error, or until ctx is done.
+func (w Waiter) Wait(ctx context.Context, refresh StateRefreshFunc) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	pollCtx := ctx
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		pollCtx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWaiterInterval
+	}
+
+	for {
+		state, err := refresh(pollCtx)
+		if err != nil {
+			if ctx.Err() == nil && pollCtx.Err() == context.DeadlineExceeded {
+				return ErrTimeOut{}
+			}
+			return err
+		}
+
+		if w.Progress != nil {
+			w.Progress(state)
+		}
+
+		if containsState(w.TargetStates, state) {
+			return nil
+		}
+		if containsState(w.FailureStates, state) {
+			return ErrFailureState{State: state, Expected: w.TargetStates}
+		}
+
+		if err := sleepWithContext(pollCtx, interval); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return ErrTimeOut{}
+		}
+
+		if w.Backoff > 1 {
+			interval = time.Duration(float64(interval) * w.Backoff)
+			if w.MaxInterval > 0 && interval > w.MaxInterval {
+				interval = w.MaxInterval
+			}
+		}
+	}
+}
+
+// WaitForStatus polls refresh every DefaultWaiterInterval until it returns
+// status, or an error status other than status, such as ERROR or
+// error_deleting, in which case it fails with an ErrFailureState without
+// waiting any longer. It backs the WaitForStatus and WaitForStatusContext
+// functions of the resource packages.
+//
+// It returns an ErrTimeOut after timeout. Like WaitFor, it times out
+// immediately if timeout is 0. A negative timeout waits until ctx is done.
+func WaitForStatus(ctx context.Context, status string, timeout time.Duration, refresh StateRefreshFunc) error {
+	if timeout == 0 {
+		return ErrTimeOut{}
+	}
+
+	w := Waiter{TargetStates: []string{status}}
+	if timeout > 0 {
+		w.Timeout = timeout
+	}
+	return w.Wait(ctx, func(ctx context.Context) (string, error) {
+		current, err := refresh(ctx)
+		if err == nil && current != status && isErrorStatus(current) {
+			return "", ErrFailureState{State: current, Expected: w.TargetStates}
+		}
+		return current, err
+	})
+}
+
+// isErrorStatus reports whether status is one of the error statuses of the
+// OpenStack resources, like ERROR for servers and error, error_deleting or
+// error_attaching for volumes, snapshots and attachments.
+func isErrorStatus(status string) bool {
+	return strings.HasPrefix(strings.ToLower(status), "error")
+}
+
+func containsState(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}